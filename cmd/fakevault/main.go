@@ -7,13 +7,15 @@
 // endpoints the remote connector calls (see pkg/remote-connector.go) plus the
 // write + search endpoints the remote writer / search command call (see
 // pkg/remote-writer.go), all backed by an in-memory, mutex-guarded store
-// seeded from a fixed fixture set. It is a test helper and is never shipped
-// (goreleaser builds the root `main: .` only).
+// seeded from a fixed fixture set. PATCH honors If-Match (revision id) and
+// If-Unmodified-Since, answering 412 when the secret moved on. It is a test
+// helper and is never shipped (goreleaser builds the root `main: .` only).
 package main
 
 import (
 	"crypto/rand"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
@@ -34,6 +36,21 @@ type secret struct {
 	Description string
 	Password    string
 	File        string
+	// Revision counts value changes; the revision id served as
+	// current_revision is "<key>-<Revision>".
+	Revision    int
+	LastChanged time.Time
+}
+
+// fixtureTime is the last_changed of the seeded fixtures.
+var fixtureTime = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// errPreconditionFailed aborts an update whose If-Match or
+// If-Unmodified-Since does not hold.
+var errPreconditionFailed = errors.New("precondition failed")
+
+func revisionID(key string, rev int) string {
+	return fmt.Sprintf("%s-%d", key, rev)
 }
 
 // store is the in-memory, mutex-guarded secret set. It starts out seeded with
@@ -55,6 +72,8 @@ func newStore() *store {
 				URL:         "https://demo.example/login",
 				Password:    "demo-pass-123",
 				File:        "demo-file-contents",
+				Revision:    1,
+				LastChanged: fixtureTime,
 			},
 			"AbC123": {
 				ContentType: "password",
@@ -63,6 +82,8 @@ func newStore() *store {
 				URL:         "https://api.internal",
 				Password:    "s3cr3t-value",
 				File:        "certificate-bytes",
+				Revision:    1,
+				LastChanged: fixtureTime,
 			},
 		},
 	}
@@ -83,17 +104,21 @@ func (s *store) put(key string, v secret) {
 
 // update atomically applies fn to the secret at key, holding the lock across the
 // whole read-modify-write so concurrent updates to the same key can't lose each
-// other's changes. Returns false (and does not call fn) if the key is absent.
-func (s *store) update(key string, fn func(secret) secret) (secret, bool) {
+// other's changes. Returns false (and does not call fn) if the key is absent;
+// an error from fn leaves the secret unchanged.
+func (s *store) update(key string, fn func(secret) (secret, error)) (secret, bool, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	v, ok := s.data[key]
 	if !ok {
-		return secret{}, false
+		return secret{}, false, nil
+	}
+	v, err := fn(v)
+	if err != nil {
+		return secret{}, true, err
 	}
-	v = fn(v)
 	s.data[key] = v
-	return v, true
+	return v, true, nil
 }
 
 // search returns keys of secrets whose key or username contains q
@@ -128,29 +153,37 @@ func main() {
 	st := newStore()
 	mux := http.NewServeMux()
 
-	// GET /api/secrets/{key}/ — secret metadata (username, url, current_revision).
+	// GET /api/secrets/{key}/ — secret metadata (name, username, url,
+	// current_revision, last_changed, ...).
 	mux.HandleFunc("GET /api/secrets/{key}/", func(w http.ResponseWriter, r *http.Request) {
 		if !authOK(w, r) {
 			return
 		}
-		s, ok := st.get(r.PathValue("key"))
+		key := r.PathValue("key")
+		s, ok := st.get(key)
 		if !ok {
 			http.NotFound(w, r)
 			return
 		}
 		writeJSON(w, map[string]any{
-			"username": s.Username,
-			"url":      s.URL,
+			"name":         s.Name,
+			"username":     s.Username,
+			"url":          s.URL,
+			"description":  s.Description,
+			"content_type": s.ContentType,
 			"current_revision": fmt.Sprintf(
 				"http://%s/api/secret-revisions/%s/",
 				r.Host,
-				r.PathValue("key"),
+				revisionID(key, s.Revision),
 			),
+			"last_changed": s.LastChanged.Format(time.RFC3339Nano),
 		})
 	})
 
 	// PATCH /api/secrets/{key}/ — partial update of metadata and/or secret_data.
-	// content_type is immutable and never read from the request body.
+	// content_type is immutable and never read from the request body. A new
+	// value bumps the revision; If-Match / If-Unmodified-Since are checked
+	// under the store lock, so a precondition can't race another PATCH.
 	mux.HandleFunc("PATCH /api/secrets/{key}/", func(w http.ResponseWriter, r *http.Request) {
 		if !authOK(w, r) {
 			return
@@ -170,7 +203,10 @@ func main() {
 		}
 		// Read-modify-write under a single lock so concurrent PATCHes can't lose
 		// updates. secret_data keys absent from the body leave the value untouched.
-		_, ok := st.update(key, func(s secret) secret {
+		_, ok, err := st.update(key, func(s secret) (secret, error) {
+			if !preconditionsHold(r, revisionID(key, s.Revision), s.LastChanged) {
+				return s, errPreconditionFailed
+			}
 			if req.Name != nil {
 				s.Name = *req.Name
 			}
@@ -189,26 +225,37 @@ func main() {
 			if fc, ok := req.SecretData["file_content"]; ok {
 				s.File = fc
 			}
-			return s
+			if len(req.SecretData) > 0 {
+				s.Revision++
+			}
+			s.LastChanged = time.Now().UTC()
+			return s, nil
 		})
 		if !ok {
 			http.NotFound(w, r)
 			return
 		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusPreconditionFailed)
+			return
+		}
 
 		writeJSON(w, map[string]any{
 			"api_url": fmt.Sprintf("http://%s/api/secrets/%s/", r.Host, key),
 		})
 	})
 
-	// GET /api/secret-revisions/{key}/data — revision data (password, file).
+	// GET /api/secret-revisions/{revision}/data — revision data (password,
+	// file). The fake keeps only the current value, so every revision id of a
+	// secret serves it.
 	mux.HandleFunc(
-		"GET /api/secret-revisions/{key}/data",
+		"GET /api/secret-revisions/{revision}/data",
 		func(w http.ResponseWriter, r *http.Request) {
 			if !authOK(w, r) {
 				return
 			}
-			s, ok := st.get(r.PathValue("key"))
+			key, _, _ := strings.Cut(r.PathValue("revision"), "-")
+			s, ok := st.get(key)
 			if !ok {
 				http.NotFound(w, r)
 				return
@@ -231,11 +278,14 @@ func main() {
 			for _, key := range keys {
 				s, _ := st.get(key)
 				results = append(results, map[string]any{
-					"hashid":   key,
-					"name":     s.Name,
-					"username": s.Username,
-					"url":      s.URL,
-					"api_url":  fmt.Sprintf("http://%s/api/secrets/%s/", r.Host, key),
+					"hashid":       key,
+					"name":         s.Name,
+					"username":     s.Username,
+					"url":          s.URL,
+					"content_type": s.ContentType,
+					"status":       "ok",
+					"last_changed": s.LastChanged.Format(time.RFC3339Nano),
+					"api_url":      fmt.Sprintf("http://%s/api/secrets/%s/", r.Host, key),
 				})
 			}
 			writeJSON(
@@ -270,6 +320,8 @@ func main() {
 				Description: req.Description,
 				Password:    req.SecretData["password"],
 				File:        req.SecretData["file_content"],
+				Revision:    1,
+				LastChanged: time.Now().UTC(),
 			})
 			writeJSON(w, map[string]any{
 				"api_url": fmt.Sprintf("http://%s/api/secrets/%s/", r.Host, key),
@@ -280,6 +332,15 @@ func main() {
 		}
 	})
 
+	// POST /api/generate_password/ — a random password, as `create --generate`
+	// and `inventory apply` (generate: true) request it.
+	mux.HandleFunc("POST /api/generate_password/", func(w http.ResponseWriter, r *http.Request) {
+		if !authOK(w, r) {
+			return
+		}
+		writeJSON(w, map[string]any{"password": genKey() + genKey()})
+	})
+
 	ln, err := net.Listen("tcp", *addr)
 	if err != nil {
 		log.Fatalf("fakevault: listen %s: %v", *addr, err)
@@ -306,6 +367,31 @@ func authOK(w http.ResponseWriter, r *http.Request) bool {
 	return false
 }
 
+// preconditionsHold evaluates If-Match against the secret's current revision
+// id and If-Unmodified-Since against its last change (at the header's
+// one-second resolution). Absent headers hold.
+func preconditionsHold(r *http.Request, revision string, lastChanged time.Time) bool {
+	if ifMatch := r.Header.Get("If-Match"); ifMatch != "" {
+		matched := false
+		for _, tag := range strings.Split(ifMatch, ",") {
+			tag = strings.Trim(strings.TrimPrefix(strings.TrimSpace(tag), "W/"), `"`)
+			if tag == "*" || tag == revision {
+				matched = true
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if since := r.Header.Get("If-Unmodified-Since"); since != "" {
+		t, err := http.ParseTime(since)
+		if err == nil && lastChanged.Truncate(time.Second).After(t) {
+			return false
+		}
+	}
+	return true
+}
+
 func writeJSON(w http.ResponseWriter, v any) {
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(v); err != nil {
@@ -318,8 +404,9 @@ func writeJSON(w http.ResponseWriter, v any) {
 // only rejects empty keys, so the exact alphabet is not load-bearing).
 const keyAlphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
 
-// genKey generates a short random key for a newly created secret, using
-// crypto/rand so the fake never depends on wall-clock time.
+// genKey generates a short random key for a newly created secret (and, twice,
+// a generated password), using crypto/rand so the fake never depends on
+// wall-clock time.
 func genKey() string {
 	b := make([]byte, 8)
 	if _, err := rand.Read(b); err != nil {
@@ -0,0 +1,131 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package teamvault
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/bborbe/errors"
+)
+
+// RepoAliasesFile is the name of the per-repository aliases file. It is
+// looked up in the working directory and its parents.
+const RepoAliasesFile = ".teamvault-aliases"
+
+var aliasNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// Aliases maps friendly names (e.g. "prod-db") to TeamVault keys.
+type Aliases map[string]Key
+
+// ValidateAliasName checks that name can be used as an alias: letters,
+// digits, '.', '_' and '-', starting with a letter or digit.
+func ValidateAliasName(ctx context.Context, name string) error {
+	if !aliasNamePattern.MatchString(name) {
+		return errors.Errorf(
+			ctx,
+			"invalid alias name %q: use letters, digits, '.', '_' and '-'",
+			name,
+		)
+	}
+	return nil
+}
+
+// AliasesPath is the path of a JSON aliases file ({"name": "key", ...}).
+type AliasesPath string
+
+// String returns the string representation of the AliasesPath.
+func (a AliasesPath) String() string {
+	return string(a)
+}
+
+// ProfileAliasesPath returns the aliases file belonging to a config file:
+// ~/.teamvault.json uses ~/.teamvault.aliases.json, so every config
+// (profile) has its own aliases.
+func ProfileAliasesPath(configPath TeamvaultConfigPath) (AliasesPath, error) {
+	path, err := configPath.NormalizePath()
+	if err != nil {
+		return "", err
+	}
+	base := strings.TrimSuffix(path.String(), filepath.Ext(path.String()))
+	return AliasesPath(base + ".aliases.json"), nil
+}
+
+// FindRepoAliasesPath returns the RepoAliasesFile in dir or the nearest
+// parent directory containing one.
+func FindRepoAliasesPath(dir string) (AliasesPath, bool) {
+	for {
+		path := filepath.Join(dir, RepoAliasesFile)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return AliasesPath(path), true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// Read parses the aliases file. A missing or empty file yields no aliases.
+func (a AliasesPath) Read(ctx context.Context) (Aliases, error) {
+	content, err := os.ReadFile(a.String())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Aliases{}, nil
+		}
+		return nil, errors.Wrapf(ctx, err, "read aliases %s failed", a)
+	}
+	aliases := Aliases{}
+	if len(strings.TrimSpace(string(content))) == 0 {
+		return aliases, nil
+	}
+	if err := json.Unmarshal(content, &aliases); err != nil {
+		return nil, errors.Wrapf(ctx, err, "parse aliases %s failed", a)
+	}
+	return aliases, nil
+}
+
+// Write stores the aliases as indented JSON, creating the directory if
+// needed.
+func (a AliasesPath) Write(ctx context.Context, aliases Aliases) error {
+	content, err := json.MarshalIndent(aliases, "", "  ")
+	if err != nil {
+		return errors.Wrapf(ctx, err, "marshal aliases failed")
+	}
+	if err := os.MkdirAll(filepath.Dir(a.String()), 0700); err != nil {
+		return errors.Wrapf(ctx, err, "create directory for %s failed", a)
+	}
+	if err := os.WriteFile(a.String(), append(content, '\n'), 0600); err != nil {
+		return errors.Wrapf(ctx, err, "write aliases %s failed", a)
+	}
+	return nil
+}
+
+// NewAliasKeyResolver creates a KeyResolver that replaces alias names by
+// their keys and passes everything else to next. Aliases take precedence
+// over keys of the same spelling.
+func NewAliasKeyResolver(aliases Aliases, next KeyResolver) KeyResolver {
+	return &aliasKeyResolver{
+		aliases: aliases,
+		next:    next,
+	}
+}
+
+type aliasKeyResolver struct {
+	aliases Aliases
+	next    KeyResolver
+}
+
+func (a *aliasKeyResolver) ResolveKey(ctx context.Context, value string) (Key, error) {
+	if key, ok := a.aliases[strings.TrimSpace(value)]; ok {
+		return a.next.ResolveKey(ctx, key.String())
+	}
+	return a.next.ResolveKey(ctx, value)
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package teamvault_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	teamvault "github.com/Seibert-Data/teamvault-cli/v5/pkg"
+)
+
+var _ = Describe("Aliases", func() {
+	var ctx context.Context
+	var dir string
+	BeforeEach(func() {
+		ctx = context.Background()
+		dir = GinkgoT().TempDir()
+	})
+
+	Context("AliasesPath", func() {
+		It("reads a missing file as no aliases", func() {
+			aliases, err := teamvault.AliasesPath(filepath.Join(dir, "missing.json")).Read(ctx)
+			Expect(err).To(BeNil())
+			Expect(aliases).To(BeEmpty())
+		})
+
+		It("round-trips written aliases", func() {
+			path := teamvault.AliasesPath(filepath.Join(dir, "sub", "aliases.json"))
+			Expect(path.Write(ctx, teamvault.Aliases{"prod-db": "AbC123"})).To(Succeed())
+
+			aliases, err := path.Read(ctx)
+			Expect(err).To(BeNil())
+			Expect(aliases).To(Equal(teamvault.Aliases{"prod-db": "AbC123"}))
+			info, err := os.Stat(path.String())
+			Expect(err).To(BeNil())
+			Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+		})
+
+		It("fails on invalid json", func() {
+			path := filepath.Join(dir, "aliases.json")
+			Expect(os.WriteFile(path, []byte("{"), 0600)).To(Succeed())
+
+			_, err := teamvault.AliasesPath(path).Read(ctx)
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	It("places profile aliases next to the config file", func() {
+		path, err := teamvault.ProfileAliasesPath(
+			teamvault.TeamvaultConfigPath(filepath.Join(dir, "config.json")),
+		)
+		Expect(err).To(BeNil())
+		Expect(path).To(Equal(teamvault.AliasesPath(filepath.Join(dir, "config.aliases.json"))))
+	})
+
+	It("finds the repo aliases file in a parent directory", func() {
+		nested := filepath.Join(dir, "a", "b")
+		Expect(os.MkdirAll(nested, 0700)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, teamvault.RepoAliasesFile), []byte("{}"), 0600)).
+			To(Succeed())
+
+		path, ok := teamvault.FindRepoAliasesPath(nested)
+		Expect(ok).To(BeTrue())
+		Expect(path).To(Equal(teamvault.AliasesPath(filepath.Join(dir, teamvault.RepoAliasesFile))))
+	})
+
+	DescribeTable(
+		"ValidateAliasName",
+		func(name string, expectedError bool) {
+			err := teamvault.ValidateAliasName(ctx, name)
+			if expectedError {
+				Expect(err).NotTo(BeNil())
+			} else {
+				Expect(err).To(BeNil())
+			}
+		},
+		Entry("simple", "prod-db", false),
+		Entry("dotted", "prod.db_admin", false),
+		Entry("empty", "", true),
+		Entry("leading dash", "-db", true),
+		Entry("whitespace", "prod db", true),
+		Entry("slash", "prod/db", true),
+	)
+
+	Context("AliasKeyResolver", func() {
+		var resolver teamvault.KeyResolver
+		BeforeEach(func() {
+			resolver = teamvault.NewAliasKeyResolver(
+				teamvault.Aliases{"prod-db": "AbC123"},
+				teamvault.NewKeyResolver(""),
+			)
+		})
+
+		It("replaces an alias by its key", func() {
+			Expect(resolver.ResolveKey(ctx, "prod-db")).To(Equal(teamvault.Key("AbC123")))
+		})
+
+		It("passes other values on", func() {
+			Expect(resolver.ResolveKey(ctx, "https://vault.example.com/secrets/XyZ789/")).
+				To(Equal(teamvault.Key("XyZ789")))
+		})
+	})
+})
@@ -6,6 +6,7 @@ package teamvault
 
 import (
 	"fmt"
+	"net/url"
 	"strings"
 )
 
@@ -19,8 +20,24 @@ func (a ApiUrl) String() string {
 
 // Key extracts the TeamVault Key from the API URL path.
 func (a ApiUrl) Key() (Key, error) {
-	parts := strings.Split(a.String(), "/")
-	if len(parts) < 3 {
+	u, err := url.Parse(a.String())
+	if err != nil {
+		return "", fmt.Errorf("parse key form api-url failed: %w", err)
+	}
+	return keyFromPath(u.Path)
+}
+
+// keyFromPath returns the segment following "secrets" in a TeamVault web or
+// API path (/secrets/<key>/, /api/secrets/<key>/...). Paths without it fall
+// back to the segment before the trailing slash.
+func keyFromPath(path string) (Key, error) {
+	parts := strings.Split(path, "/")
+	for i, part := range parts {
+		if part == "secrets" && i+1 < len(parts) && parts[i+1] != "" {
+			return Key(parts[i+1]), nil
+		}
+	}
+	if len(parts) < 3 || parts[len(parts)-2] == "" {
 		return "", fmt.Errorf("parse key form api-url failed")
 	}
 	return Key(parts[len(parts)-2]), nil
@@ -49,5 +49,23 @@ var _ = Describe("ApiUrl", func() {
 			false,
 			teamvault.Key("key123"),
 		),
+		Entry(
+			"url with query",
+			teamvault.ApiUrl("https://teamvault.example.com/api/secrets/key123/?format=json"),
+			false,
+			teamvault.Key("key123"),
+		),
+		Entry(
+			"web url",
+			teamvault.ApiUrl("https://teamvault.example.com/secrets/key123/"),
+			false,
+			teamvault.Key("key123"),
+		),
+		Entry(
+			"web url with revision path",
+			teamvault.ApiUrl("https://teamvault.example.com/secrets/key123/revisions/"),
+			false,
+			teamvault.Key("key123"),
+		),
 	)
 })
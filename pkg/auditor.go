@@ -0,0 +1,192 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package teamvault
+
+import (
+	"context"
+	"crypto/sha256"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bborbe/errors"
+	libtime "github.com/bborbe/time"
+)
+
+// AuditFinding is a problem the audit found with a password.
+type AuditFinding string
+
+const (
+	// AuditWeak means the password scores below AuditOptions.MinScore.
+	AuditWeak AuditFinding = "weak"
+	// AuditReused means other secrets have the same password.
+	AuditReused AuditFinding = "reused"
+	// AuditStale means the secret did not change for longer than
+	// AuditOptions.MaxAge.
+	AuditStale AuditFinding = "stale"
+	// AuditNeedsChange means TeamVault flags the secret as needing change,
+	// e.g. because a user who knew it lost access.
+	AuditNeedsChange AuditFinding = "needs change"
+)
+
+// statusNeedsChanging is the TeamVault status of secrets flagged for change.
+const statusNeedsChanging = "needs_changing"
+
+// AuditOptions control an audit.
+type AuditOptions struct {
+	// Query selects the secrets; only password secrets are audited.
+	Query SearchQuery
+	// Concurrency bounds the passwords fetched in parallel.
+	Concurrency int
+	// MinScore is the lowest PasswordStrength.Score not reported as weak.
+	MinScore int
+	// MaxAge is the age after which a secret is reported as stale; 0
+	// disables the check.
+	MaxAge time.Duration
+}
+
+// DefaultAuditOptions returns options auditing all secrets, 4 at a time,
+// requiring a score of 3 and a change at least once a year.
+func DefaultAuditOptions() AuditOptions {
+	return AuditOptions{
+		Concurrency: 4,
+		MinScore:    3,
+		MaxAge:      365 * 24 * time.Hour,
+	}
+}
+
+// AuditResult reports one secret. It holds no password or hash of it.
+type AuditResult struct {
+	Key          Key       `json:"key"`
+	Name         string    `json:"name"`
+	Username     string    `json:"username,omitempty"`
+	Status       string    `json:"status,omitempty"`
+	LastModified time.Time `json:"last_modified,omitzero"`
+	Score        int       `json:"score"`
+	Entropy      float64   `json:"entropy"`
+	// ReusedWith lists the other secrets with the same password.
+	ReusedWith []Key          `json:"reused_with,omitempty"`
+	Findings   []AuditFinding `json:"findings"`
+	// Error is set if the password could not be read; the secret is then
+	// not scored.
+	Error string `json:"error,omitempty"`
+}
+
+// Auditor checks passwords for weakness, reuse and age.
+type Auditor interface {
+	// Audit returns a result for every password secret matching the query,
+	// in search order.
+	Audit(ctx context.Context, options AuditOptions) ([]AuditResult, error)
+}
+
+// NewAuditor creates an Auditor enumerating secrets via SearchSeq.
+func NewAuditor(conn Connector, currentDateTime libtime.CurrentDateTimeGetter) Auditor {
+	return &auditor{
+		conn:            conn,
+		currentDateTime: currentDateTime,
+	}
+}
+
+type auditor struct {
+	conn            Connector
+	currentDateTime libtime.CurrentDateTimeGetter
+}
+
+func (a *auditor) Audit(ctx context.Context, options AuditOptions) ([]AuditResult, error) {
+	if options.Concurrency < 1 {
+		return nil, errors.Errorf(
+			ctx,
+			"concurrency must be at least 1, got %d",
+			options.Concurrency,
+		)
+	}
+	var results []AuditResult
+	seen := map[Key]bool{}
+	for result, err := range SearchSeq(ctx, a.conn, options.Query) {
+		if err != nil {
+			return nil, errors.Wrapf(ctx, err, "list secrets failed")
+		}
+		// Pages can shift while secrets are created; never audit twice.
+		if seen[result.Key] {
+			continue
+		}
+		seen[result.Key] = true
+		if result.ContentType != "" && result.ContentType != string(ContentTypePassword) {
+			continue
+		}
+		results = append(results, AuditResult{
+			Key:          result.Key,
+			Name:         result.Name,
+			Username:     result.Username,
+			Status:       result.Status,
+			LastModified: result.LastModified,
+			Findings:     []AuditFinding{},
+		})
+	}
+
+	// Only hashes are kept, so passwords leave memory as soon as they are
+	// scored and reuse is detected without comparing values.
+	hashes := make([][sha256.Size]byte, len(results))
+	semaphore := make(chan struct{}, options.Concurrency)
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Go(func() {
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+			hashes[i] = a.score(ctx, &results[i])
+		})
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, errors.Wrapf(ctx, err, "audit canceled")
+	}
+
+	byHash := map[[sha256.Size]byte][]Key{}
+	for i, result := range results {
+		if result.Error == "" {
+			byHash[hashes[i]] = append(byHash[hashes[i]], result.Key)
+		}
+	}
+	staleBefore := a.currentDateTime.Now().Time().Add(-options.MaxAge)
+	for i := range results {
+		result := &results[i]
+		if result.Error != "" {
+			continue
+		}
+		if result.Score < options.MinScore {
+			result.Findings = append(result.Findings, AuditWeak)
+		}
+		if keys := byHash[hashes[i]]; len(keys) > 1 {
+			result.ReusedWith = slices.DeleteFunc(
+				slices.Clone(keys),
+				func(key Key) bool { return key == result.Key },
+			)
+			result.Findings = append(result.Findings, AuditReused)
+		}
+		if options.MaxAge > 0 && !result.LastModified.IsZero() &&
+			result.LastModified.Before(staleBefore) {
+			result.Findings = append(result.Findings, AuditStale)
+		}
+		if strings.EqualFold(result.Status, statusNeedsChanging) {
+			result.Findings = append(result.Findings, AuditNeedsChange)
+		}
+	}
+	return results, nil
+}
+
+// score reads the password of result, sets its strength and returns the
+// password's hash; on failure it sets result.Error.
+func (a *auditor) score(ctx context.Context, result *AuditResult) [sha256.Size]byte {
+	password, err := a.conn.Password(ctx, result.Key)
+	if err != nil {
+		result.Error = err.Error()
+		return [sha256.Size]byte{}
+	}
+	strength := EstimatePasswordStrength(password.String(), result.Name, result.Username)
+	result.Score = strength.Score
+	result.Entropy = strength.Entropy
+	return sha256.Sum256([]byte(password))
+}
@@ -0,0 +1,138 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package teamvault_test
+
+import (
+	"context"
+	stderrors "errors"
+	"time"
+
+	libtime "github.com/bborbe/time"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	teamvault "github.com/Seibert-Data/teamvault-cli/v5/pkg"
+	"github.com/Seibert-Data/teamvault-cli/v5/pkg/mocks"
+)
+
+var _ = Describe("Auditor", func() {
+	var ctx context.Context
+	var conn *mocks.Connector
+	var now time.Time
+	var passwords map[teamvault.Key]teamvault.Password
+	var auditor teamvault.Auditor
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		now = time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+		conn = &mocks.Connector{}
+		conn.SearchReturns([]teamvault.SearchResult{
+			{
+				Key:          "AbC123",
+				Name:         "payment-db",
+				ContentType:  "password",
+				Status:       "ok",
+				LastModified: now.AddDate(0, -1, 0),
+			},
+			{
+				Key:          "DeF456",
+				Name:         "billing-db",
+				ContentType:  "password",
+				Status:       "needs_changing",
+				LastModified: now.AddDate(-2, 0, 0),
+			},
+			{Key: "GhI789", Name: "shop-db", ContentType: "password"},
+			{Key: "XyZ789", Name: "payment-tls", ContentType: "file"},
+			{Key: "AbC123", Name: "payment-db", ContentType: "password"},
+		}, nil)
+		passwords = map[teamvault.Key]teamvault.Password{
+			"AbC123": "password1",
+			"DeF456": "r8N!c2pW@x4Lq#Zt6vB$m9Hy",
+			"GhI789": "password1",
+		}
+		conn.PasswordStub = func(
+			ctx context.Context,
+			key teamvault.Key,
+		) (teamvault.Password, error) {
+			return passwords[key], nil
+		}
+		currentDateTime := libtime.NewCurrentDateTime()
+		currentDateTime.SetNow(libtime.DateTime(now))
+		auditor = teamvault.NewAuditor(conn, currentDateTime)
+	})
+
+	It("reports weak, reused, stale and flagged passwords", func() {
+		results, err := auditor.Audit(ctx, teamvault.DefaultAuditOptions())
+
+		Expect(err).To(BeNil())
+		Expect(results).To(HaveLen(3))
+		Expect(results[0].Key).To(Equal(teamvault.Key("AbC123")))
+		Expect(results[0].Score).To(BeNumerically("<", 3))
+		Expect(results[0].ReusedWith).To(Equal([]teamvault.Key{"GhI789"}))
+		Expect(results[0].Findings).
+			To(Equal([]teamvault.AuditFinding{teamvault.AuditWeak, teamvault.AuditReused}))
+		Expect(results[1].Score).To(Equal(4))
+		Expect(results[1].ReusedWith).To(BeEmpty())
+		Expect(results[1].Findings).
+			To(Equal([]teamvault.AuditFinding{teamvault.AuditStale, teamvault.AuditNeedsChange}))
+		Expect(results[2].ReusedWith).To(Equal([]teamvault.Key{"AbC123"}))
+	})
+
+	It("skips secrets that are not passwords", func() {
+		_, err := auditor.Audit(ctx, teamvault.DefaultAuditOptions())
+
+		Expect(err).To(BeNil())
+		for i := range conn.PasswordCallCount() {
+			_, key := conn.PasswordArgsForCall(i)
+			Expect(key).NotTo(Equal(teamvault.Key("XyZ789")))
+		}
+	})
+
+	It("reports unreadable passwords without failing the audit", func() {
+		conn.PasswordStub = func(
+			ctx context.Context,
+			key teamvault.Key,
+		) (teamvault.Password, error) {
+			if key == "GhI789" {
+				return "", stderrors.New("forbidden")
+			}
+			return passwords[key], nil
+		}
+
+		results, err := auditor.Audit(ctx, teamvault.DefaultAuditOptions())
+
+		Expect(err).To(BeNil())
+		Expect(results[2].Error).To(ContainSubstring("forbidden"))
+		Expect(results[2].Findings).To(BeEmpty())
+		Expect(results[0].ReusedWith).To(BeEmpty())
+	})
+
+	It("does not report stale secrets without a maximum age", func() {
+		options := teamvault.DefaultAuditOptions()
+		options.MaxAge = 0
+
+		results, err := auditor.Audit(ctx, options)
+
+		Expect(err).To(BeNil())
+		Expect(results[1].Findings).To(Equal([]teamvault.AuditFinding{teamvault.AuditNeedsChange}))
+	})
+
+	It("returns an error if the search fails", func() {
+		conn.SearchReturns(nil, stderrors.New("unavailable"))
+
+		_, err := auditor.Audit(ctx, teamvault.DefaultAuditOptions())
+
+		Expect(err).NotTo(BeNil())
+	})
+
+	It("rejects a concurrency below 1", func() {
+		options := teamvault.DefaultAuditOptions()
+		options.Concurrency = 0
+
+		_, err := auditor.Audit(ctx, options)
+
+		Expect(err).NotTo(BeNil())
+	})
+})
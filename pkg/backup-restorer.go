@@ -0,0 +1,114 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package teamvault
+
+import (
+	"context"
+	"encoding/base64"
+	stderrors "errors"
+
+	"github.com/bborbe/errors"
+)
+
+// RestoreStatus is the outcome of restoring one secret.
+type RestoreStatus string
+
+const (
+	// RestoreCreated means the secret was recreated under a new key.
+	RestoreCreated RestoreStatus = "created"
+	// RestoreExists means a secret of that name exists and was left alone.
+	RestoreExists RestoreStatus = "exists"
+	// RestoreFailed means recreating the secret failed.
+	RestoreFailed RestoreStatus = "failed"
+)
+
+// RestoreResult reports the outcome of one secret. Key is the new or
+// existing secret; Secret.Key is the key at backup time.
+type RestoreResult struct {
+	Secret BackupSecret
+	Status RestoreStatus
+	Key    Key
+	Reason string
+}
+
+// BackupRestorer recreates the secrets of a backup.
+type BackupRestorer interface {
+	// Restore creates every secret whose name does not exist in TeamVault.
+	// It keeps going after failures and returns an error if any occurred;
+	// a re-run skips what was restored.
+	Restore(ctx context.Context, backup Backup) ([]RestoreResult, error)
+}
+
+// NewBackupRestorer creates a BackupRestorer that looks names up via
+// connector's Search and creates secrets through writer.
+func NewBackupRestorer(connector Connector, writer Writer) BackupRestorer {
+	return &backupRestorer{
+		connector: connector,
+		writer:    writer,
+	}
+}
+
+type backupRestorer struct {
+	connector Connector
+	writer    Writer
+}
+
+func (b *backupRestorer) Restore(ctx context.Context, backup Backup) ([]RestoreResult, error) {
+	results := make([]RestoreResult, 0, len(backup.Secrets))
+	failed := 0
+	for _, secret := range backup.Secrets {
+		result := b.restoreSecret(ctx, secret)
+		if result.Status == RestoreFailed {
+			failed++
+		}
+		results = append(results, result)
+	}
+	if failed > 0 {
+		return results, errors.Errorf(
+			ctx,
+			"%d of %d secrets failed to restore",
+			failed,
+			len(backup.Secrets),
+		)
+	}
+	return results, nil
+}
+
+func (b *backupRestorer) restoreSecret(ctx context.Context, secret BackupSecret) RestoreResult {
+	result := RestoreResult{Secret: secret}
+	key, err := NewNameResolver(b.connector, false).Resolve(ctx, secret.Name)
+	switch {
+	case err == nil:
+		result.Status, result.Key = RestoreExists, key
+		return result
+	case stderrors.Is(err, ErrNameAmbiguous):
+		result.Status, result.Reason = RestoreExists, "name exists more than once"
+		return result
+	case !stderrors.Is(err, ErrNameNotFound):
+		result.Status, result.Reason = RestoreFailed, err.Error()
+		return result
+	}
+
+	create := CreateSecret{
+		ContentType: secret.ContentType,
+		Name:        secret.Name,
+		Username:    secret.Username,
+		Url:         secret.Url.String(),
+		Description: secret.Description,
+		Password:    secret.Password,
+	}
+	if secret.ContentType == ContentTypeFile {
+		if create.FileContent, err = base64.StdEncoding.DecodeString(secret.File.String()); err != nil {
+			result.Status, result.Reason = RestoreFailed, "file content invalid"
+			return result
+		}
+	}
+	if result.Key, _, err = b.writer.Create(ctx, create); err != nil {
+		result.Status, result.Reason = RestoreFailed, err.Error()
+		return result
+	}
+	result.Status = RestoreCreated
+	return result
+}
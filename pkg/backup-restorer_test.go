@@ -0,0 +1,94 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package teamvault_test
+
+import (
+	"context"
+	stderrors "errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	teamvault "github.com/Seibert-Data/teamvault-cli/v5/pkg"
+	"github.com/Seibert-Data/teamvault-cli/v5/pkg/mocks"
+)
+
+var _ = Describe("BackupRestorer", func() {
+	var ctx context.Context
+	var conn *mocks.Connector
+	var writer *mocks.Writer
+	var backup teamvault.Backup
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		conn = &mocks.Connector{}
+		conn.SearchStub = func(ctx context.Context, name string) ([]teamvault.SearchResult, error) {
+			if name == "existing" {
+				return []teamvault.SearchResult{{Key: "ExI123", Name: "existing"}}, nil
+			}
+			return nil, nil
+		}
+		writer = &mocks.Writer{}
+		writer.CreateReturns("NeW456", "", nil)
+		backup = teamvault.Backup{
+			Version: teamvault.BackupVersion,
+			Secrets: []teamvault.BackupSecret{
+				{
+					Key:         "AbC123",
+					Name:        "payment-db",
+					Url:         "postgres://db",
+					ContentType: teamvault.ContentTypePassword,
+					Password:    "s3cret",
+				},
+				{
+					Key:         "XyZ789",
+					Name:        "payment-tls",
+					ContentType: teamvault.ContentTypeFile,
+					File:        "Y2VydA==",
+				},
+				{
+					Key:         "OlD111",
+					Name:        "existing",
+					ContentType: teamvault.ContentTypePassword,
+					Password:    "old",
+				},
+			},
+		}
+	})
+
+	It("creates missing secrets and leaves existing ones alone", func() {
+		results, err := teamvault.NewBackupRestorer(conn, writer).Restore(ctx, backup)
+
+		Expect(err).To(BeNil())
+		Expect(results).To(HaveLen(3))
+		Expect(results[0].Status).To(Equal(teamvault.RestoreCreated))
+		Expect(results[0].Key).To(Equal(teamvault.Key("NeW456")))
+		Expect(results[2].Status).To(Equal(teamvault.RestoreExists))
+		Expect(results[2].Key).To(Equal(teamvault.Key("ExI123")))
+		Expect(writer.CreateCallCount()).To(Equal(2))
+		_, password := writer.CreateArgsForCall(0)
+		Expect(password).To(Equal(teamvault.CreateSecret{
+			ContentType: teamvault.ContentTypePassword,
+			Name:        "payment-db",
+			Url:         "postgres://db",
+			Password:    "s3cret",
+		}))
+		_, file := writer.CreateArgsForCall(1)
+		Expect(file.ContentType).To(Equal(teamvault.ContentTypeFile))
+		Expect(file.FileContent).To(Equal([]byte("cert")))
+	})
+
+	It("keeps going after a failure and reports it", func() {
+		writer.CreateReturnsOnCall(0, "", "", stderrors.New("boom"))
+
+		results, err := teamvault.NewBackupRestorer(conn, writer).Restore(ctx, backup)
+
+		Expect(err).NotTo(BeNil())
+		Expect(err.Error()).To(ContainSubstring("1 of 3 secrets failed"))
+		Expect(results[0].Status).To(Equal(teamvault.RestoreFailed))
+		Expect(results[0].Reason).To(ContainSubstring("boom"))
+		Expect(results[1].Status).To(Equal(teamvault.RestoreCreated))
+	})
+})
@@ -0,0 +1,226 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package teamvault
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"time"
+
+	"filippo.io/age"
+	"github.com/bborbe/errors"
+	libtime "github.com/bborbe/time"
+)
+
+// BackupVersion is the format version written into new backups.
+const BackupVersion = 1
+
+// Backup is the content of a backup archive: every secret's metadata and
+// value at the time of the backup.
+type Backup struct {
+	Version int            `json:"version"`
+	Created time.Time      `json:"created"`
+	Secrets []BackupSecret `json:"secrets"`
+	// Skipped lists secrets whose content type cannot be restored (e.g. "cc").
+	Skipped []SearchResult `json:"skipped,omitempty"`
+	// Failed lists secrets that could not be read (e.g. forbidden, or
+	// deleted during the backup); they are not in Secrets.
+	Failed []BackupFailure `json:"failed,omitempty"`
+}
+
+// BackupFailure is a secret left out of a backup because reading it failed.
+type BackupFailure struct {
+	Key   Key    `json:"key"`
+	Name  string `json:"name,omitempty"`
+	Error string `json:"error"`
+}
+
+// BackupSecret is one secret in a backup.
+type BackupSecret struct {
+	Key          Key         `json:"key"`
+	Name         string      `json:"name"`
+	Username     string      `json:"username,omitempty"`
+	Url          Url         `json:"url,omitempty"`
+	Description  string      `json:"description,omitempty"`
+	ContentType  ContentType `json:"content_type"`
+	LastModified time.Time   `json:"last_modified,omitzero"`
+	// Password is set for password secrets.
+	Password Password `json:"password,omitempty"`
+	// File is the base64-encoded content of file secrets, as returned by
+	// Connector.File.
+	File File `json:"file,omitempty"`
+}
+
+// Validate checks the backup can be restored: a known version, and a name
+// and a value matching the content type for every secret.
+func (b Backup) Validate(ctx context.Context) error {
+	if b.Version != BackupVersion {
+		return errors.Errorf(ctx, "unsupported backup version %d", b.Version)
+	}
+	for _, secret := range b.Secrets {
+		if secret.Name == "" {
+			return errors.Errorf(ctx, "secret %s: name missing", secret.Key)
+		}
+		switch secret.ContentType {
+		case ContentTypePassword:
+			if secret.Password == "" {
+				return errors.Errorf(ctx, "secret %s: password missing", secret.Key)
+			}
+		case ContentTypeFile:
+			if _, err := base64.StdEncoding.DecodeString(secret.File.String()); err != nil {
+				return errors.Wrapf(ctx, err, "secret %s: file content invalid", secret.Key)
+			}
+		default:
+			return errors.Errorf(
+				ctx,
+				"secret %s: unsupported content type %q",
+				secret.Key,
+				secret.ContentType,
+			)
+		}
+	}
+	return nil
+}
+
+// BackupCreator reads every secret the user can access into a Backup.
+type BackupCreator interface {
+	// Create returns an error only if the secrets cannot be listed; secrets
+	// that cannot be read are recorded in Backup.Failed.
+	Create(ctx context.Context) (Backup, error)
+}
+
+// NewBackupCreator creates a BackupCreator that enumerates secrets with an
+// empty search via SearchSeq and reads their metadata (connector must
+// implement MetadataReader) and values.
+func NewBackupCreator(
+	connector Connector,
+	currentDateTime libtime.CurrentDateTimeGetter,
+) BackupCreator {
+	return &backupCreator{
+		connector:       connector,
+		currentDateTime: currentDateTime,
+	}
+}
+
+type backupCreator struct {
+	connector       Connector
+	currentDateTime libtime.CurrentDateTimeGetter
+}
+
+func (b *backupCreator) Create(ctx context.Context) (Backup, error) {
+	backup := Backup{
+		Version: BackupVersion,
+		Created: b.currentDateTime.Now().Time().UTC(),
+		Secrets: []BackupSecret{},
+	}
+	seen := map[Key]bool{}
+	for result, err := range SearchSeq(ctx, b.connector, SearchQuery{}) {
+		if err != nil {
+			return Backup{}, errors.Wrapf(ctx, err, "list secrets failed")
+		}
+		// Pages can shift while secrets are created; never back up twice.
+		if seen[result.Key] {
+			continue
+		}
+		seen[result.Key] = true
+		fail := func(err error) {
+			backup.Failed = append(backup.Failed, BackupFailure{
+				Key:   result.Key,
+				Name:  result.Name,
+				Error: err.Error(),
+			})
+		}
+		metadata, err := ReadMetadata(ctx, b.connector, result.Key)
+		if err != nil {
+			fail(err)
+			continue
+		}
+		secret := BackupSecret{
+			Key:          result.Key,
+			Name:         metadata.Name,
+			Username:     metadata.Username,
+			Url:          metadata.Url,
+			Description:  metadata.Description,
+			ContentType:  metadata.ContentType,
+			LastModified: metadata.LastModified,
+		}
+		switch metadata.ContentType {
+		case ContentTypePassword:
+			if secret.Password, err = b.connector.Password(ctx, result.Key); err != nil {
+				fail(errors.Wrapf(ctx, err, "read password of %s failed", result.Key))
+				continue
+			}
+		case ContentTypeFile:
+			if secret.File, err = b.connector.File(ctx, result.Key); err != nil {
+				fail(errors.Wrapf(ctx, err, "read file of %s failed", result.Key))
+				continue
+			}
+		default:
+			result.ContentType = string(metadata.ContentType)
+			backup.Skipped = append(backup.Skipped, result)
+			continue
+		}
+		backup.Secrets = append(backup.Secrets, secret)
+	}
+	return backup, nil
+}
+
+// WriteBackup writes backup as gzip-compressed JSON, encrypted with age to
+// recipients (X25519 public keys, or a single scrypt passphrase recipient).
+func WriteBackup(
+	ctx context.Context,
+	writer io.Writer,
+	backup Backup,
+	recipients ...age.Recipient,
+) error {
+	if len(recipients) == 0 {
+		return errors.New(ctx, "backup needs at least one recipient or a passphrase")
+	}
+	encrypted, err := age.Encrypt(writer, recipients...)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "encrypt backup failed")
+	}
+	compressed := gzip.NewWriter(encrypted)
+	if err := json.NewEncoder(compressed).Encode(backup); err != nil {
+		return errors.Wrapf(ctx, err, "write backup failed")
+	}
+	if err := compressed.Close(); err != nil {
+		return errors.Wrapf(ctx, err, "compress backup failed")
+	}
+	// Close writes the final chunk; without it the archive is truncated.
+	if err := encrypted.Close(); err != nil {
+		return errors.Wrapf(ctx, err, "encrypt backup failed")
+	}
+	return nil
+}
+
+// ReadBackup decrypts a backup written by WriteBackup with one of
+// identities and validates it. age authenticates the whole archive, so a
+// tampered or truncated file fails here.
+func ReadBackup(ctx context.Context, reader io.Reader, identities ...age.Identity) (Backup, error) {
+	decrypted, err := age.Decrypt(reader, identities...)
+	if err != nil {
+		return Backup{}, errors.Wrapf(ctx, err, "decrypt backup failed")
+	}
+	decompressed, err := gzip.NewReader(decrypted)
+	if err != nil {
+		return Backup{}, errors.Wrapf(ctx, err, "decompress backup failed")
+	}
+	var backup Backup
+	if err := json.NewDecoder(decompressed).Decode(&backup); err != nil {
+		return Backup{}, errors.Wrapf(ctx, err, "parse backup failed")
+	}
+	// Read to the end so a corrupted final chunk is reported too.
+	if _, err := io.Copy(io.Discard, decompressed); err != nil {
+		return Backup{}, errors.Wrapf(ctx, err, "read backup failed")
+	}
+	if err := backup.Validate(ctx); err != nil {
+		return Backup{}, errors.Wrapf(ctx, err, "invalid backup")
+	}
+	return backup, nil
+}
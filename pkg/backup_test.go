@@ -0,0 +1,195 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package teamvault_test
+
+import (
+	"bytes"
+	"context"
+	stderrors "errors"
+	"time"
+
+	"filippo.io/age"
+	libtime "github.com/bborbe/time"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	teamvault "github.com/Seibert-Data/teamvault-cli/v5/pkg"
+	"github.com/Seibert-Data/teamvault-cli/v5/pkg/mocks"
+)
+
+var _ = Describe("Backup", func() {
+	var ctx context.Context
+	var backup teamvault.Backup
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		backup = teamvault.Backup{
+			Version: teamvault.BackupVersion,
+			Created: time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC),
+			Secrets: []teamvault.BackupSecret{
+				{
+					Key:         "AbC123",
+					Name:        "payment-db",
+					Username:    "payment",
+					ContentType: teamvault.ContentTypePassword,
+					Password:    "s3cret",
+				},
+				{
+					Key:         "XyZ789",
+					Name:        "payment-tls",
+					ContentType: teamvault.ContentTypeFile,
+					File:        "Y2VydA==",
+				},
+			},
+		}
+	})
+
+	Context("BackupCreator", func() {
+		It("reads metadata and values of every secret found", func() {
+			conn := metadataConnector{
+				Connector:      &mocks.Connector{},
+				MetadataReader: &mocks.MetadataReader{},
+			}
+			conn.SearchReturns([]teamvault.SearchResult{
+				{Key: "AbC123", Name: "payment-db"},
+				{Key: "XyZ789", Name: "payment-tls"},
+				{Key: "AbC123", Name: "payment-db"},
+				{Key: "CcC000", Name: "company-card"},
+			}, nil)
+			conn.MetadataReader.MetadataStub = func(
+				ctx context.Context,
+				key teamvault.Key,
+			) (teamvault.SecretMetadata, error) {
+				for _, secret := range backup.Secrets {
+					if secret.Key == key {
+						return teamvault.SecretMetadata{
+							Key:         key,
+							Name:        secret.Name,
+							Username:    secret.Username,
+							ContentType: secret.ContentType,
+						}, nil
+					}
+				}
+				return teamvault.SecretMetadata{
+					Key:         key,
+					Name:        "company-card",
+					ContentType: "cc",
+				}, nil
+			}
+			conn.PasswordReturns("s3cret", nil)
+			conn.FileReturns("Y2VydA==", nil)
+			currentDateTime := libtime.NewCurrentDateTime()
+			currentDateTime.SetNow(libtime.DateTime(backup.Created))
+
+			result, err := teamvault.NewBackupCreator(conn, currentDateTime).Create(ctx)
+
+			Expect(err).To(BeNil())
+			Expect(result.Secrets).To(Equal(backup.Secrets))
+			Expect(result.Created).To(Equal(backup.Created))
+			Expect(result.Skipped).To(Equal([]teamvault.SearchResult{
+				{Key: "CcC000", Name: "company-card", ContentType: "cc"},
+			}))
+			_, name := conn.SearchArgsForCall(0)
+			Expect(name).To(Equal(""))
+		})
+
+		It("records unreadable secrets as failed and keeps going", func() {
+			conn := metadataConnector{
+				Connector:      &mocks.Connector{},
+				MetadataReader: &mocks.MetadataReader{},
+			}
+			conn.SearchReturns([]teamvault.SearchResult{
+				{Key: "GoNe00", Name: "deleted"},
+				{Key: "DeNy00", Name: "forbidden"},
+				{Key: "AbC123", Name: "payment-db"},
+			}, nil)
+			conn.MetadataReader.MetadataStub = func(
+				ctx context.Context,
+				key teamvault.Key,
+			) (teamvault.SecretMetadata, error) {
+				if key == "GoNe00" {
+					return teamvault.SecretMetadata{}, stderrors.New("not found")
+				}
+				return teamvault.SecretMetadata{
+					Key:         key,
+					Name:        "payment-db",
+					ContentType: teamvault.ContentTypePassword,
+				}, nil
+			}
+			conn.PasswordStub = func(
+				ctx context.Context,
+				key teamvault.Key,
+			) (teamvault.Password, error) {
+				if key == "DeNy00" {
+					return "", stderrors.New("forbidden")
+				}
+				return "s3cret", nil
+			}
+
+			result, err := teamvault.NewBackupCreator(conn, libtime.NewCurrentDateTime()).
+				Create(ctx)
+
+			Expect(err).To(BeNil())
+			Expect(result.Secrets).To(HaveLen(1))
+			Expect(result.Secrets[0].Key).To(Equal(teamvault.Key("AbC123")))
+			Expect(result.Failed).To(HaveLen(2))
+			Expect(result.Failed[0].Key).To(Equal(teamvault.Key("GoNe00")))
+			Expect(result.Failed[0].Error).To(ContainSubstring("not found"))
+			Expect(result.Failed[1].Key).To(Equal(teamvault.Key("DeNy00")))
+			Expect(result.Failed[1].Error).To(ContainSubstring("forbidden"))
+		})
+	})
+
+	Context("WriteBackup and ReadBackup", func() {
+		var identity *age.X25519Identity
+
+		BeforeEach(func() {
+			var err error
+			identity, err = age.GenerateX25519Identity()
+			Expect(err).To(BeNil())
+		})
+
+		It("round-trips an archive", func() {
+			var archive bytes.Buffer
+			Expect(teamvault.WriteBackup(ctx, &archive, backup, identity.Recipient())).To(Succeed())
+			Expect(archive.String()).NotTo(ContainSubstring("s3cret"))
+
+			result, err := teamvault.ReadBackup(ctx, &archive, identity)
+			Expect(err).To(BeNil())
+			Expect(result).To(Equal(backup))
+		})
+
+		It("fails with the wrong identity", func() {
+			var archive bytes.Buffer
+			Expect(teamvault.WriteBackup(ctx, &archive, backup, identity.Recipient())).To(Succeed())
+			other, err := age.GenerateX25519Identity()
+			Expect(err).To(BeNil())
+
+			_, err = teamvault.ReadBackup(ctx, &archive, other)
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(ContainSubstring("decrypt backup failed"))
+		})
+
+		It("fails for a tampered archive", func() {
+			var archive bytes.Buffer
+			Expect(teamvault.WriteBackup(ctx, &archive, backup, identity.Recipient())).To(Succeed())
+			content := archive.Bytes()
+			content[len(content)-1] ^= 0xff
+
+			_, err := teamvault.ReadBackup(ctx, bytes.NewReader(content), identity)
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("rejects an archive with an invalid secret", func() {
+			backup.Secrets[0].Password = ""
+			var archive bytes.Buffer
+			Expect(teamvault.WriteBackup(ctx, &archive, backup, identity.Recipient())).To(Succeed())
+
+			_, err := teamvault.ReadBackup(ctx, &archive, identity)
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(ContainSubstring("password missing"))
+		})
+	})
+})
@@ -6,6 +6,7 @@ package teamvault
 
 import (
 	"context"
+	"iter"
 	"sync"
 )
 
@@ -96,3 +97,17 @@ func (c *cacheConnector) File(ctx context.Context, key Key) (File, error) {
 func (c *cacheConnector) Search(ctx context.Context, key string) ([]SearchResult, error) {
 	return c.connector.Search(ctx, key)
 }
+
+// Metadata reads the metadata of the wrapped connector; it is never cached,
+// so revision checks see the server's state.
+func (c *cacheConnector) Metadata(ctx context.Context, key Key) (SecretMetadata, error) {
+	return ReadMetadata(ctx, c.connector, key)
+}
+
+// SearchSeq streams the search of the wrapped connector; see SearchSeq.
+func (c *cacheConnector) SearchSeq(
+	ctx context.Context,
+	query SearchQuery,
+) iter.Seq2[SearchResult, error] {
+	return SearchSeq(ctx, c.connector, query)
+}
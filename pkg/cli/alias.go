@@ -0,0 +1,324 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/bborbe/errors"
+	"github.com/spf13/cobra"
+
+	teamvault "github.com/Seibert-Data/teamvault-cli/v5/pkg"
+)
+
+// aliasFiles holds both alias sources: the profile file next to the config
+// file and the nearest .teamvault-aliases of the working directory. The
+// repo file wins on conflicts, so a checkout can pin its own keys.
+type aliasFiles struct {
+	profilePath teamvault.AliasesPath
+	profile     teamvault.Aliases
+	repoPath    teamvault.AliasesPath
+	repo        teamvault.Aliases
+}
+
+// merged returns the effective aliases.
+func (a *aliasFiles) merged() teamvault.Aliases {
+	result := teamvault.Aliases{}
+	for name, key := range a.profile {
+		result[name] = key
+	}
+	for name, key := range a.repo {
+		result[name] = key
+	}
+	return result
+}
+
+// source returns the file the effective alias name comes from.
+func (a *aliasFiles) source(name string) teamvault.AliasesPath {
+	if _, ok := a.repo[name]; ok {
+		return a.repoPath
+	}
+	return a.profilePath
+}
+
+// readAliases reads the profile and repo aliases. Missing files are empty.
+func (sf *SharedFlags) readAliases(ctx context.Context) (*aliasFiles, error) {
+	profilePath, err := teamvault.ProfileAliasesPath(teamvault.TeamvaultConfigPath(sf.configPath))
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "locate profile aliases failed")
+	}
+	profile, err := profilePath.Read(ctx)
+	if err != nil {
+		return nil, err
+	}
+	files := &aliasFiles{
+		profilePath: profilePath,
+		profile:     profile,
+		repo:        teamvault.Aliases{},
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "get working directory failed")
+	}
+	if repoPath, ok := teamvault.FindRepoAliasesPath(wd); ok {
+		files.repoPath = repoPath
+		if files.repo, err = repoPath.Read(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// createAliasCommand creates the alias parent command.
+func createAliasCommand(ctx context.Context, sf *SharedFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "alias",
+		Short: "Manage friendly names for TeamVault keys",
+		Long: `Manage friendly names for TeamVault keys.
+
+Aliases are accepted wherever a key is expected, including the teamvault*
+template functions. They live in two JSON files:
+
+  profile  next to the config file, e.g. ~/.teamvault.aliases.json
+  repo     .teamvault-aliases in the working directory or a parent
+
+Repo aliases win over profile aliases of the same name.`,
+	}
+	cmd.AddCommand(createAliasAddCommand(ctx, sf))
+	cmd.AddCommand(createAliasRmCommand(ctx, sf))
+	cmd.AddCommand(createAliasLsCommand(ctx, sf))
+	cmd.AddCommand(createAliasVerifyCommand(ctx, sf))
+	return cmd
+}
+
+// aliasTarget returns the file add/rm edit: the profile file, or with
+// --repo the nearest repo file (created in the working directory if none
+// exists).
+func aliasTarget(
+	ctx context.Context,
+	files *aliasFiles,
+	repo bool,
+) (teamvault.AliasesPath, teamvault.Aliases, error) {
+	if !repo {
+		return files.profilePath, files.profile, nil
+	}
+	if files.repoPath != "" {
+		return files.repoPath, files.repo, nil
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", nil, errors.Wrapf(ctx, err, "get working directory failed")
+	}
+	return teamvault.AliasesPath(filepath.Join(wd, teamvault.RepoAliasesFile)), files.repo, nil
+}
+
+// createAliasAddCommand creates the alias add subcommand.
+func createAliasAddCommand(ctx context.Context, sf *SharedFlags) *cobra.Command {
+	var repo, force bool
+
+	cmd := &cobra.Command{
+		Use:   "add <name> <key>",
+		Short: "Add an alias for a key or secret URL",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			if err := teamvault.ValidateAliasName(ctx, name); err != nil {
+				return err
+			}
+			vaultUrl, err := sf.vaultUrl(ctx)
+			if err != nil {
+				return err
+			}
+			key, err := teamvault.NewKeyResolver(vaultUrl).ResolveKey(ctx, args[1])
+			if err != nil {
+				return errors.Wrap(ctx, err, "invalid key")
+			}
+			files, err := sf.readAliases(ctx)
+			if err != nil {
+				return err
+			}
+			path, aliases, err := aliasTarget(ctx, files, repo)
+			if err != nil {
+				return err
+			}
+			if existing, ok := aliases[name]; ok && existing != key && !force {
+				return errors.Errorf(
+					ctx,
+					"alias %q already points to %s in %s (use --force to replace)",
+					name,
+					existing,
+					path,
+				)
+			}
+			aliases[name] = key
+			if err := path.Write(ctx, aliases); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "Added alias %s for %s to %s.\n", name, key, path)
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&repo, "repo", false, "write to the repo's .teamvault-aliases")
+	cmd.Flags().BoolVar(&force, "force", false, "replace an existing alias")
+	return cmd
+}
+
+// createAliasRmCommand creates the alias rm subcommand.
+func createAliasRmCommand(ctx context.Context, sf *SharedFlags) *cobra.Command {
+	var repo bool
+
+	cmd := &cobra.Command{
+		Use:   "rm <name>",
+		Short: "Remove an alias",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			files, err := sf.readAliases(ctx)
+			if err != nil {
+				return err
+			}
+			path, aliases, err := aliasTarget(ctx, files, repo)
+			if err != nil {
+				return err
+			}
+			if _, ok := aliases[name]; !ok {
+				return errors.Errorf(ctx, "alias %q not found in %s", name, path)
+			}
+			delete(aliases, name)
+			if err := path.Write(ctx, aliases); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "Removed alias %s from %s.\n", name, path)
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&repo, "repo", false, "remove from the repo's .teamvault-aliases")
+	return cmd
+}
+
+// createAliasLsCommand creates the alias ls subcommand.
+func createAliasLsCommand(ctx context.Context, sf *SharedFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ls",
+		Short: "List the effective aliases",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			files, err := sf.readAliases(ctx)
+			if err != nil {
+				return err
+			}
+			asJSON, _ := cmd.Flags().GetBool("json")
+			return writeAliases(ctx, cmd.OutOrStdout(), files, asJSON)
+		},
+	}
+	cmd.Flags().Bool("json", false, "print aliases as a JSON array of objects {name,key,source}")
+	return cmd
+}
+
+// writeAliases writes the effective aliases sorted by name, as an aligned
+// NAME / KEY / SOURCE table or as JSON.
+func writeAliases(ctx context.Context, out io.Writer, files *aliasFiles, asJSON bool) error {
+	aliases := files.merged()
+	names := sortedAliasNames(aliases)
+	if asJSON {
+		type aliasJSON struct {
+			Name   string `json:"name"`
+			Key    string `json:"key"`
+			Source string `json:"source"`
+		}
+		items := make([]aliasJSON, 0, len(names))
+		for _, name := range names {
+			items = append(items, aliasJSON{
+				Name:   name,
+				Key:    aliases[name].String(),
+				Source: files.source(name).String(),
+			})
+		}
+		encoded, err := json.Marshal(items)
+		if err != nil {
+			return errors.Wrapf(ctx, err, "marshal json failed")
+		}
+		if _, err := fmt.Fprintf(out, "%s\n", encoded); err != nil {
+			return errors.Wrapf(ctx, err, "write aliases failed")
+		}
+		return nil
+	}
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tKEY\tSOURCE")
+	for _, name := range names {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", name, aliases[name], files.source(name))
+	}
+	if err := tw.Flush(); err != nil {
+		return errors.Wrapf(ctx, err, "flush alias table failed")
+	}
+	return nil
+}
+
+// createAliasVerifyCommand creates the alias verify subcommand. It reads
+// each alias's username, which proves the secret exists and is readable
+// without recording a password access in TeamVault's audit log. The disk
+// cache is bypassed, so it cannot answer for a secret gone from the server.
+func createAliasVerifyCommand(ctx context.Context, sf *SharedFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Check that every alias still resolves to a readable secret",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			files, err := sf.readAliases(ctx)
+			if err != nil {
+				return err
+			}
+			conn, err := newConnector(sf.withoutCache())(ctx)
+			if err != nil {
+				return errors.Wrap(ctx, err, "create connector failed")
+			}
+			keyResolver, err := sf.keyResolver(ctx)
+			if err != nil {
+				return err
+			}
+			aliases := files.merged()
+			names := sortedAliasNames(aliases)
+			failed := 0
+			tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(tw, "NAME\tKEY\tSTATUS")
+			for _, name := range names {
+				status := "ok"
+				key, err := keyResolver.ResolveKey(ctx, name)
+				if err == nil {
+					_, err = conn.User(ctx, key)
+				}
+				if err != nil {
+					failed++
+					status = "FAILED: " + err.Error()
+				}
+				fmt.Fprintf(tw, "%s\t%s\t%s\n", name, aliases[name], status)
+			}
+			if err := tw.Flush(); err != nil {
+				return errors.Wrapf(ctx, err, "flush alias table failed")
+			}
+			if failed > 0 {
+				return errors.Errorf(ctx, "%d of %d aliases failed", failed, len(names))
+			}
+			return nil
+		},
+	}
+	return cmd
+}
+
+func sortedAliasNames(aliases teamvault.Aliases) []string {
+	names := make([]string, 0, len(aliases))
+	for name := range aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,206 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	teamvault "github.com/Seibert-Data/teamvault-cli/v5/pkg"
+	"github.com/Seibert-Data/teamvault-cli/v5/pkg/cli"
+	"github.com/Seibert-Data/teamvault-cli/v5/pkg/mocks"
+)
+
+var _ = Describe("alias", func() {
+	var ctx context.Context
+	var fakeConn *mocks.Connector
+	var outBuf, errBuf bytes.Buffer
+	var resetConnector func()
+	var dir, repoDir, configPath, prevWd string
+
+	execute := func(args ...string) error {
+		outBuf.Reset()
+		errBuf.Reset()
+		cmd := cli.NewRootCommand(ctx)
+		cmd.SetArgs(append(args, "--teamvault-config", configPath))
+		cmd.SetOut(&outBuf)
+		cmd.SetErr(&errBuf)
+		return cmd.Execute()
+	}
+
+	readAliases := func(path string) teamvault.Aliases {
+		aliases, err := teamvault.AliasesPath(path).Read(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		return aliases
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		os.Setenv("STAGING", "true")
+		dir = GinkgoT().TempDir()
+		configPath = filepath.Join(dir, "config.json")
+		repoDir = filepath.Join(dir, "repo")
+		Expect(os.MkdirAll(filepath.Join(repoDir, "sub"), 0700)).To(Succeed())
+		var err error
+		prevWd, err = os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.Chdir(filepath.Join(repoDir, "sub"))).To(Succeed())
+
+		fakeConn = &mocks.Connector{}
+		fakeConn.PasswordStub = func(ctx context.Context, key teamvault.Key) (teamvault.Password, error) {
+			return teamvault.Password("pass-" + key.String()), nil
+		}
+		fakeConn.UserStub = func(ctx context.Context, key teamvault.Key) (teamvault.User, error) {
+			if key == "Gone99" {
+				return "", errors.New("secret not found")
+			}
+			return "AKIAEXAMPLE", nil
+		}
+		resetConnector = cli.SetNewConnectorForTest(
+			func(sf *cli.SharedFlags) func(context.Context) (teamvault.Connector, error) {
+				return func(ctx context.Context) (teamvault.Connector, error) {
+					return fakeConn, nil
+				}
+			},
+		)
+	})
+
+	AfterEach(func() {
+		resetConnector()
+		Expect(os.Chdir(prevWd)).To(Succeed())
+		os.Unsetenv("STAGING")
+	})
+
+	It("adds a profile alias next to the config file", func() {
+		Expect(execute("alias", "add", "prod-db", "AbC123")).To(Succeed())
+
+		Expect(readAliases(filepath.Join(dir, "config.aliases.json"))).
+			To(Equal(teamvault.Aliases{"prod-db": "AbC123"}))
+		Expect(errBuf.String()).To(ContainSubstring("Added alias prod-db for AbC123"))
+	})
+
+	It("stores the key of a secret url", func() {
+		Expect(execute("alias", "add", "prod-db", "https://vault.example.com/secrets/AbC123/")).
+			To(Succeed())
+
+		Expect(readAliases(filepath.Join(dir, "config.aliases.json"))).
+			To(Equal(teamvault.Aliases{"prod-db": "AbC123"}))
+	})
+
+	It("adds a repo alias in the working directory", func() {
+		Expect(execute("alias", "add", "--repo", "prod-db", "AbC123")).To(Succeed())
+
+		Expect(readAliases(filepath.Join(repoDir, "sub", teamvault.RepoAliasesFile))).
+			To(Equal(teamvault.Aliases{"prod-db": "AbC123"}))
+	})
+
+	It("refuses to replace an alias without --force", func() {
+		Expect(execute("alias", "add", "prod-db", "AbC123")).To(Succeed())
+
+		Expect(execute("alias", "add", "prod-db", "XyZ789")).To(HaveOccurred())
+		Expect(execute("alias", "add", "prod-db", "XyZ789", "--force")).To(Succeed())
+		Expect(readAliases(filepath.Join(dir, "config.aliases.json"))).
+			To(Equal(teamvault.Aliases{"prod-db": "XyZ789"}))
+	})
+
+	It("rejects an invalid alias name", func() {
+		Expect(execute("alias", "add", "prod db", "AbC123")).To(HaveOccurred())
+	})
+
+	It("removes an alias", func() {
+		Expect(execute("alias", "add", "prod-db", "AbC123")).To(Succeed())
+
+		Expect(execute("alias", "rm", "prod-db")).To(Succeed())
+		Expect(readAliases(filepath.Join(dir, "config.aliases.json"))).To(BeEmpty())
+		Expect(execute("alias", "rm", "prod-db")).To(HaveOccurred())
+	})
+
+	Context("with profile and repo aliases", func() {
+		BeforeEach(func() {
+			Expect(teamvault.AliasesPath(filepath.Join(dir, "config.aliases.json")).Write(
+				ctx,
+				teamvault.Aliases{"prod-db": "AbC123", "ci": "CiK001"},
+			)).To(Succeed())
+			Expect(teamvault.AliasesPath(filepath.Join(repoDir, teamvault.RepoAliasesFile)).Write(
+				ctx,
+				teamvault.Aliases{"ci": "RePo42"},
+			)).To(Succeed())
+		})
+
+		It("resolves an alias given as key", func() {
+			Expect(execute("aws-credential-process", "prod-db")).To(Succeed())
+
+			Expect(outBuf.String()).To(ContainSubstring(`"SecretAccessKey":"pass-AbC123"`))
+		})
+
+		It("prefers the repo alias", func() {
+			Expect(execute("aws-credential-process", "ci")).To(Succeed())
+
+			Expect(outBuf.String()).To(ContainSubstring(`"SecretAccessKey":"pass-RePo42"`))
+		})
+
+		It("lists the effective aliases with their source", func() {
+			Expect(execute("alias", "ls")).To(Succeed())
+
+			Expect(outBuf.String()).To(MatchRegexp(`ci\s+RePo42\s+.*repo/\.teamvault-aliases`))
+			Expect(outBuf.String()).To(MatchRegexp(`prod-db\s+AbC123\s+.*config\.aliases\.json`))
+		})
+
+		It("lists the aliases as json", func() {
+			Expect(execute("alias", "ls", "--json")).To(Succeed())
+
+			Expect(
+				outBuf.String(),
+			).To(ContainSubstring(`{"name":"prod-db","key":"AbC123","source":`))
+		})
+
+		It("verifies every alias through the connector", func() {
+			Expect(execute("alias", "verify")).To(Succeed())
+
+			Expect(fakeConn.UserCallCount()).To(Equal(2))
+			Expect(fakeConn.PasswordCallCount()).To(Equal(0))
+		})
+
+		It("fails verify when an alias points to a missing secret", func() {
+			Expect(execute("alias", "add", "old", "Gone99")).To(Succeed())
+
+			err := execute("alias", "verify")
+
+			Expect(err).To(MatchError(ContainSubstring("1 of 3 aliases failed")))
+			Expect(outBuf.String()).To(MatchRegexp(`old\s+Gone99\s+FAILED: secret not found`))
+		})
+
+		It("fails verify even when the disk cache still has the secret", func() {
+			home := GinkgoT().TempDir()
+			GinkgoT().Setenv("HOME", home)
+			cached := filepath.Join(home, ".teamvault-cache", "Gone99")
+			Expect(os.MkdirAll(cached, 0700)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(cached, "user"), []byte("stale"), 0600)).
+				To(Succeed())
+			defer cli.SetNewConnectorForTest(
+				func(sf *cli.SharedFlags) func(context.Context) (teamvault.Connector, error) {
+					return func(ctx context.Context) (teamvault.Connector, error) {
+						if sf.Uncached() {
+							return fakeConn, nil
+						}
+						return teamvault.NewDiskFallbackConnector(fakeConn), nil
+					}
+				},
+			)()
+			Expect(execute("alias", "add", "old", "Gone99")).To(Succeed())
+
+			err := execute("alias", "verify", "--cache")
+
+			Expect(err).To(MatchError(ContainSubstring("1 of 3 aliases failed")))
+			Expect(outBuf.String()).To(MatchRegexp(`old\s+Gone99\s+FAILED: secret not found`))
+		})
+	})
+})
@@ -0,0 +1,102 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/bborbe/errors"
+	"github.com/spf13/cobra"
+
+	teamvault "github.com/Seibert-Data/teamvault-cli/v5/pkg"
+)
+
+// createAskpassCommand builds the `askpass` subcommand, an SSH_ASKPASS /
+// SUDO_ASKPASS helper. The prompt arrives as the argument; the password of
+// the first "askpass" config rule whose pattern matches it is printed on
+// stdout. Unmatched prompts are refused with a non-zero exit, which ssh and
+// sudo treat as a cancelled prompt.
+func createAskpassCommand(ctx context.Context, sf *SharedFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "askpass [prompt]",
+		Short: "SSH_ASKPASS/SUDO_ASKPASS helper answering prompts from TeamVault",
+		Long: `SSH_ASKPASS/SUDO_ASKPASS helper answering prompts from TeamVault.
+
+The prompt (surrounding whitespace trimmed) is matched against the "askpass"
+rules of the config file, e.g.
+
+  "askpass": [{"pattern": "^deploy@db1's password:$", "key": "AbC123"}]
+
+The first matching rule's password is printed. The key may also be an alias
+or a secret URL. Prompts no rule matches, and
+ssh confirmation prompts (SSH_ASKPASS_PROMPT=confirm), are refused.
+
+ssh and sudo run the helper without arguments other than the prompt, so
+symlink the binary as teamvault-askpass and point SSH_ASKPASS/SUDO_ASKPASS at it.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			prompt := strings.TrimSpace(strings.Join(args, " "))
+			switch os.Getenv("SSH_ASKPASS_PROMPT") {
+			case "confirm", "none":
+				return errors.Errorf(ctx, "refusing ssh confirmation prompt %q", prompt)
+			}
+			config, err := sf.readConfig(ctx)
+			if err != nil {
+				return err
+			}
+			var rules []teamvault.AskpassRule
+			if config != nil {
+				rules = config.Askpass
+			}
+			ruleKey, err := matchAskpassRule(ctx, rules, prompt)
+			if err != nil {
+				return err
+			}
+			keyResolver, err := sf.keyResolver(ctx)
+			if err != nil {
+				return err
+			}
+			key, err := keyResolver.ResolveKey(ctx, ruleKey.String())
+			if err != nil {
+				return errors.Wrapf(ctx, err, "invalid key in askpass rule")
+			}
+			conn, err := newConnector(sf)(ctx)
+			if err != nil {
+				return errors.Wrap(ctx, err, "create connector failed")
+			}
+			pass, err := conn.Password(ctx, key)
+			if err != nil {
+				return errors.Wrap(ctx, err, "get password failed")
+			}
+			if _, err := fmt.Fprintf(cmd.OutOrStdout(), "%s\n", pass); err != nil {
+				return errors.Wrapf(ctx, err, "write password failed")
+			}
+			return nil
+		},
+	}
+	return cmd
+}
+
+// matchAskpassRule returns the key of the first rule whose pattern matches
+// prompt, or an error when none does.
+func matchAskpassRule(
+	ctx context.Context,
+	rules []teamvault.AskpassRule,
+	prompt string,
+) (teamvault.Key, error) {
+	for _, rule := range rules {
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return "", errors.Wrapf(ctx, err, "compile askpass pattern %q failed", rule.Pattern)
+		}
+		if re.MatchString(prompt) {
+			return rule.Key, nil
+		}
+	}
+	return "", errors.Errorf(ctx, "no askpass rule matches prompt %q", prompt)
+}
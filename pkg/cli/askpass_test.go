@@ -0,0 +1,116 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	teamvault "github.com/Seibert-Data/teamvault-cli/v5/pkg"
+	"github.com/Seibert-Data/teamvault-cli/v5/pkg/cli"
+	"github.com/Seibert-Data/teamvault-cli/v5/pkg/mocks"
+)
+
+var _ = Describe("askpass", func() {
+	var ctx context.Context
+	var fakeConn *mocks.Connector
+	var configPath string
+	var outBuf bytes.Buffer
+	var resetConnector func()
+
+	execute := func(prompt string) error {
+		outBuf.Reset()
+		cmd := cli.NewRootCommand(ctx)
+		cmd.SetArgs([]string{"--teamvault-config", configPath, "askpass", prompt})
+		cmd.SetOut(&outBuf)
+		cmd.SetErr(&bytes.Buffer{})
+		return cmd.Execute()
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		os.Setenv("STAGING", "true")
+		os.Unsetenv("SSH_ASKPASS_PROMPT")
+
+		configPath = filepath.Join(GinkgoT().TempDir(), "config.json")
+		Expect(os.WriteFile(configPath, []byte(`{
+			"url": "https://vault.example.com",
+			"user": "alice",
+			"pass": "pw",
+			"askpass": [
+				{"pattern": "^deploy@db1's password:$", "key": "DB1KEY"},
+				{"pattern": "^\\[sudo\\] password for ", "key": "SUDO01"},
+				{"pattern": "^backup@", "key": "backup-host"},
+				{"pattern": "^web@", "key": "https://vault.example.com/secrets/WEB001/"}
+			]
+		}`), 0600)).To(Succeed())
+
+		fakeConn = &mocks.Connector{}
+		fakeConn.PasswordReturns(teamvault.Password("s3cret"), nil)
+		resetConnector = cli.SetNewConnectorForTest(
+			func(sf *cli.SharedFlags) func(context.Context) (teamvault.Connector, error) {
+				return func(ctx context.Context) (teamvault.Connector, error) {
+					return fakeConn, nil
+				}
+			},
+		)
+	})
+
+	AfterEach(func() {
+		resetConnector()
+		os.Unsetenv("STAGING")
+		os.Unsetenv("SSH_ASKPASS_PROMPT")
+	})
+
+	It("prints the password of the matching rule", func() {
+		Expect(execute("deploy@db1's password: ")).To(Succeed())
+
+		Expect(outBuf.String()).To(Equal("s3cret\n"))
+		_, key := fakeConn.PasswordArgsForCall(0)
+		Expect(key).To(Equal(teamvault.Key("DB1KEY")))
+	})
+
+	It("answers sudo prompts", func() {
+		Expect(execute("[sudo] password for alice: ")).To(Succeed())
+
+		_, key := fakeConn.PasswordArgsForCall(0)
+		Expect(key).To(Equal(teamvault.Key("SUDO01")))
+	})
+
+	It("resolves aliases and secret URLs in rule keys", func() {
+		Expect(
+			teamvault.AliasesPath(filepath.Join(filepath.Dir(configPath), "config.aliases.json")).
+				Write(ctx, teamvault.Aliases{"backup-host": "BAK001"}),
+		).To(Succeed())
+
+		Expect(execute("backup@nas's password:")).To(Succeed())
+		Expect(execute("web@www1's password:")).To(Succeed())
+
+		_, key := fakeConn.PasswordArgsForCall(0)
+		Expect(key).To(Equal(teamvault.Key("BAK001")))
+		_, key = fakeConn.PasswordArgsForCall(1)
+		Expect(key).To(Equal(teamvault.Key("WEB001")))
+	})
+
+	It("refuses unmatched prompts", func() {
+		err := execute("root@db1's password: ")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("no askpass rule matches"))
+		Expect(outBuf.String()).To(BeEmpty())
+		Expect(fakeConn.PasswordCallCount()).To(Equal(0))
+	})
+
+	It("refuses ssh confirmation prompts", func() {
+		os.Setenv("SSH_ASKPASS_PROMPT", "confirm")
+
+		Expect(execute("deploy@db1's password: ")).To(HaveOccurred())
+		Expect(fakeConn.PasswordCallCount()).To(Equal(0))
+	})
+})
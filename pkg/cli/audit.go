@@ -0,0 +1,238 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cli
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/bborbe/errors"
+	"github.com/spf13/cobra"
+
+	teamvault "github.com/Seibert-Data/teamvault-cli/v5/pkg"
+)
+
+// createAuditCommand creates the audit command.
+func createAuditCommand(ctx context.Context, sf *SharedFlags) *cobra.Command {
+	options := teamvault.DefaultAuditOptions()
+	var staleDays int
+	var format string
+	var all bool
+
+	cmd := &cobra.Command{
+		Use:   "audit [QUERY]",
+		Short: "Report weak, reused and stale passwords",
+		Long: `Report weak, reused and stale passwords.
+
+Every password secret matching QUERY (all secrets without one) is read and
+reported as
+
+  weak          scoring below --min-score; the score estimates how hard the
+                password is to guess, from 0 (too guessable) to 4 (very
+                unguessable), like zxcvbn
+  reused        having the same password as other secrets, compared by hash
+  stale         unchanged for more than --stale-days days
+  needs change  flagged by TeamVault as needing change
+
+Passwords and their hashes are never printed. Only secrets with findings or
+read errors are listed unless --all is given.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != "table" && format != "json" && format != "csv" {
+				return errors.Errorf(ctx, "unknown format %q (want table, json or csv)", format)
+			}
+			if staleDays < 0 {
+				return errors.Errorf(ctx, "--stale-days must not be negative, got %d", staleDays)
+			}
+			if len(args) > 0 {
+				options.Query.Name = args[0]
+			}
+			options.MaxAge = time.Duration(staleDays) * 24 * time.Hour
+			// Without the disk cache, passwords are not written to it
+			// unencrypted and a failed read is reported, not scored from a
+			// stale copy.
+			conn, err := newConnector(sf.withoutCache())(ctx)
+			if err != nil {
+				return errors.Wrap(ctx, err, "create connector failed")
+			}
+			results, err := teamvault.NewAuditor(conn, newCurrentDateTime()).Audit(ctx, options)
+			if err != nil {
+				return err
+			}
+			listed := results
+			if !all {
+				listed = nil
+				for _, result := range results {
+					if len(result.Findings) > 0 || result.Error != "" {
+						listed = append(listed, result)
+					}
+				}
+			}
+			switch format {
+			case "json":
+				return writeAuditJSON(ctx, cmd.OutOrStdout(), listed)
+			case "csv":
+				return writeAuditCSV(ctx, cmd.OutOrStdout(), listed)
+			default:
+				return writeAuditTable(ctx, cmd.OutOrStdout(), listed, results)
+			}
+		},
+	}
+	cmd.Flags().IntVar(
+		&options.Concurrency,
+		"concurrency",
+		options.Concurrency,
+		"number of passwords read in parallel",
+	)
+	cmd.Flags().IntVar(
+		&options.MinScore,
+		"min-score",
+		options.MinScore,
+		"lowest score (0-4) not reported as weak",
+	)
+	cmd.Flags().IntVar(
+		&staleDays,
+		"stale-days",
+		int(options.MaxAge/(24*time.Hour)),
+		"report secrets unchanged for more days (0 = never)",
+	)
+	cmd.Flags().StringVar(&format, "format", "table", "output format: table, json or csv")
+	cmd.Flags().
+		BoolVar(&all, "all", false, "list all audited secrets, not only those with findings")
+	return cmd
+}
+
+// writeAuditTable prints the listed results followed by a summary of all.
+func writeAuditTable(
+	ctx context.Context,
+	out io.Writer,
+	listed []teamvault.AuditResult,
+	all []teamvault.AuditResult,
+) error {
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "KEY\tNAME\tSCORE\tENTROPY\tLAST MODIFIED\tFINDINGS\tREUSED WITH")
+	for _, result := range listed {
+		score, entropy, findings := "-", "-", "error: "+result.Error
+		if result.Error == "" {
+			score = strconv.Itoa(result.Score)
+			entropy = formatEntropy(result.Entropy)
+			findings = joinAuditFindings(result.Findings, ", ")
+		}
+		fmt.Fprintf(
+			tw,
+			"%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			result.Key,
+			result.Name,
+			score,
+			entropy,
+			formatAuditTime(result.LastModified),
+			findings,
+			joinAuditKeys(result.ReusedWith, ", "),
+		)
+	}
+	if err := tw.Flush(); err != nil {
+		return errors.Wrapf(ctx, err, "flush results failed")
+	}
+	counts := map[teamvault.AuditFinding]int{}
+	failed := 0
+	for _, result := range all {
+		if result.Error != "" {
+			failed++
+		}
+		for _, finding := range result.Findings {
+			counts[finding]++
+		}
+	}
+	if _, err := fmt.Fprintf(
+		out,
+		"Audit: %d passwords, %d weak, %d reused, %d stale, %d need change, %d failed.\n",
+		len(all),
+		counts[teamvault.AuditWeak],
+		counts[teamvault.AuditReused],
+		counts[teamvault.AuditStale],
+		counts[teamvault.AuditNeedsChange],
+		failed,
+	); err != nil {
+		return errors.Wrapf(ctx, err, "write summary failed")
+	}
+	return nil
+}
+
+// writeAuditJSON prints the results as a JSON array.
+func writeAuditJSON(ctx context.Context, out io.Writer, results []teamvault.AuditResult) error {
+	if results == nil {
+		results = []teamvault.AuditResult{}
+	}
+	encoded, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return errors.Wrapf(ctx, err, "marshal json failed")
+	}
+	if _, err := fmt.Fprintf(out, "%s\n", encoded); err != nil {
+		return errors.Wrapf(ctx, err, "write results failed")
+	}
+	return nil
+}
+
+// writeAuditCSV prints the results as CSV with a header row; findings and
+// reused keys are separated by semicolons.
+func writeAuditCSV(ctx context.Context, out io.Writer, results []teamvault.AuditResult) error {
+	w := csv.NewWriter(out)
+	records := [][]string{{
+		"key", "name", "username", "status", "last_modified",
+		"score", "entropy", "findings", "reused_with", "error",
+	}}
+	for _, result := range results {
+		records = append(records, []string{
+			result.Key.String(),
+			result.Name,
+			result.Username,
+			result.Status,
+			formatAuditTime(result.LastModified),
+			strconv.Itoa(result.Score),
+			formatEntropy(result.Entropy),
+			joinAuditFindings(result.Findings, ";"),
+			joinAuditKeys(result.ReusedWith, ";"),
+			result.Error,
+		})
+	}
+	if err := w.WriteAll(records); err != nil {
+		return errors.Wrapf(ctx, err, "write csv failed")
+	}
+	return nil
+}
+
+func formatEntropy(entropy float64) string {
+	return strconv.FormatFloat(entropy, 'f', 1, 64)
+}
+
+func formatAuditTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func joinAuditFindings(findings []teamvault.AuditFinding, separator string) string {
+	values := make([]string, 0, len(findings))
+	for _, finding := range findings {
+		values = append(values, string(finding))
+	}
+	return strings.Join(values, separator)
+}
+
+func joinAuditKeys(keys []teamvault.Key, separator string) string {
+	values := make([]string, 0, len(keys))
+	for _, key := range keys {
+		values = append(values, key.String())
+	}
+	return strings.Join(values, separator)
+}
@@ -0,0 +1,172 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	stderrors "errors"
+	"os"
+	"time"
+
+	libtime "github.com/bborbe/time"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	teamvault "github.com/Seibert-Data/teamvault-cli/v5/pkg"
+	"github.com/Seibert-Data/teamvault-cli/v5/pkg/cli"
+	"github.com/Seibert-Data/teamvault-cli/v5/pkg/mocks"
+)
+
+var _ = Describe("audit", func() {
+	var ctx context.Context
+	var fakeConn *mocks.Connector
+	var stdout bytes.Buffer
+	var uncached bool
+	var resets []func()
+
+	run := func(args ...string) error {
+		stdout.Reset()
+		cmd := cli.NewRootCommand(ctx)
+		cmd.SetArgs(append([]string{"audit"}, args...))
+		cmd.SetOut(&stdout)
+		cmd.SetErr(&bytes.Buffer{})
+		return cmd.Execute()
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		os.Setenv("STAGING", "true")
+		now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+		fakeConn = &mocks.Connector{}
+		fakeConn.SearchReturns([]teamvault.SearchResult{
+			{
+				Key:          "AbC123",
+				Name:         "payment-db",
+				ContentType:  "password",
+				LastModified: now.AddDate(0, -1, 0),
+			},
+			{
+				Key:          "DeF456",
+				Name:         "billing-db",
+				ContentType:  "password",
+				LastModified: now.AddDate(0, -2, 0),
+			},
+			{
+				Key:          "GhI789",
+				Name:         "shop-db",
+				ContentType:  "password",
+				LastModified: now.AddDate(0, -3, 0),
+			},
+		}, nil)
+		fakeConn.PasswordStub = func(
+			ctx context.Context,
+			key teamvault.Key,
+		) (teamvault.Password, error) {
+			if key == "GhI789" {
+				return "r8N!c2pW@x4Lq#Zt6vB$m9Hy", nil
+			}
+			return "password1", nil
+		}
+		resets = []func(){
+			cli.SetNewConnectorForTest(
+				func(sf *cli.SharedFlags) func(context.Context) (teamvault.Connector, error) {
+					uncached = sf.Uncached()
+					return func(ctx context.Context) (teamvault.Connector, error) {
+						return fakeConn, nil
+					}
+				},
+			),
+			cli.SetCurrentDateTimeForTest(
+				libtime.CurrentDateTimeGetterFunc(func() libtime.DateTime {
+					return libtime.DateTime(now)
+				}),
+			),
+		}
+	})
+
+	AfterEach(func() {
+		for _, reset := range resets {
+			reset()
+		}
+		os.Unsetenv("STAGING")
+	})
+
+	It("lists secrets with findings and a summary", func() {
+		Expect(run()).To(Succeed())
+
+		Expect(stdout.String()).To(ContainSubstring("AbC123"))
+		Expect(stdout.String()).To(ContainSubstring("weak, reused"))
+		Expect(stdout.String()).NotTo(ContainSubstring("GhI789"))
+		Expect(stdout.String()).
+			To(ContainSubstring("Audit: 3 passwords, 2 weak, 2 reused, 0 stale, 0 need change, 0 failed."))
+		Expect(stdout.String()).NotTo(ContainSubstring("password1"))
+	})
+
+	It("reads without the disk cache", func() {
+		Expect(run("--cache")).To(Succeed())
+
+		Expect(uncached).To(BeTrue())
+	})
+
+	It("reports unreadable passwords", func() {
+		fakeConn.PasswordStub = nil
+		fakeConn.PasswordReturns("", stderrors.New("server error"))
+
+		Expect(run()).To(Succeed())
+
+		Expect(stdout.String()).To(ContainSubstring("error: server error"))
+		Expect(stdout.String()).To(ContainSubstring("3 failed."))
+	})
+
+	It("lists all secrets with --all", func() {
+		Expect(run("--all")).To(Succeed())
+
+		Expect(stdout.String()).To(ContainSubstring("GhI789"))
+	})
+
+	It("reports stale secrets after --stale-days", func() {
+		Expect(run("--stale-days", "45", "--all")).To(Succeed())
+
+		Expect(stdout.String()).To(ContainSubstring("2 stale"))
+	})
+
+	It("passes the query to the search", func() {
+		Expect(run("db")).To(Succeed())
+
+		_, name := fakeConn.SearchArgsForCall(0)
+		Expect(name).To(Equal("db"))
+	})
+
+	It("prints JSON", func() {
+		Expect(run("--format", "json")).To(Succeed())
+
+		var results []teamvault.AuditResult
+		Expect(json.Unmarshal(stdout.Bytes(), &results)).To(Succeed())
+		Expect(results).To(HaveLen(2))
+		Expect(results[0].ReusedWith).To(Equal([]teamvault.Key{"DeF456"}))
+		Expect(stdout.String()).NotTo(ContainSubstring("password1"))
+	})
+
+	It("prints CSV", func() {
+		Expect(run("--format", "csv", "--all")).To(Succeed())
+
+		records, err := csv.NewReader(&stdout).ReadAll()
+		Expect(err).To(BeNil())
+		Expect(records).To(HaveLen(4))
+		Expect(records[0][0]).To(Equal("key"))
+		Expect(records[1][7]).To(Equal("weak;reused"))
+		Expect(records[1][8]).To(Equal("DeF456"))
+		Expect(records[3][5]).To(Equal("4"))
+	})
+
+	It("rejects unknown formats", func() {
+		err := run("--format", "xml")
+		Expect(err).NotTo(BeNil())
+		Expect(err.Error()).To(ContainSubstring("unknown format"))
+	})
+})
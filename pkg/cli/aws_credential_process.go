@@ -0,0 +1,117 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/bborbe/errors"
+	"github.com/spf13/cobra"
+
+	teamvault "github.com/Seibert-Data/teamvault-cli/v5/pkg"
+)
+
+// awsCredentialProcessOutput is the document AWS SDKs expect on stdout from
+// a credential_process program. Expiration is omitted: keys stored in
+// TeamVault are long-term credentials.
+type awsCredentialProcessOutput struct {
+	Version         int    `json:"Version"`
+	AccessKeyId     string `json:"AccessKeyId"`
+	SecretAccessKey string `json:"SecretAccessKey"`
+	SessionToken    string `json:"SessionToken,omitempty"`
+}
+
+// createAWSCredentialProcessCommand builds the `aws-credential-process`
+// subcommand. The secret's username is the access key id and its password
+// the secret access key, so ~/.aws/config can reference TeamVault with
+// `credential_process = teamvault-cli aws-credential-process <key>`.
+func createAWSCredentialProcessCommand(ctx context.Context, sf *SharedFlags) *cobra.Command {
+	var sessionTokenKey string
+
+	cmd := &cobra.Command{
+		Use:               "aws-credential-process [key]",
+		Short:             "Print AWS credential_process JSON for a TeamVault secret",
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeKeyArg(ctx, sf),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key, err := resolveKey(cmd, args)
+			if err != nil {
+				return err
+			}
+			var sessionToken teamvault.Key
+			if sessionTokenKey != "" {
+				keyResolver, err := sf.keyResolver(ctx)
+				if err != nil {
+					return err
+				}
+				if sessionToken, err = keyResolver.ResolveKey(ctx, sessionTokenKey); err != nil {
+					return errors.Wrap(ctx, err, "invalid --session-token-key")
+				}
+			}
+			conn, err := newConnector(sf)(ctx)
+			if err != nil {
+				return errors.Wrap(ctx, err, "create connector failed")
+			}
+			key, err = lookupKey(ctx, cmd, sf, conn, key)
+			if err != nil {
+				return err
+			}
+			accessKeyID, err := conn.User(ctx, key)
+			if err != nil {
+				return errors.Wrap(ctx, err, "get user failed")
+			}
+			secretAccessKey, err := conn.Password(ctx, key)
+			if err != nil {
+				return errors.Wrap(ctx, err, "get password failed")
+			}
+			if accessKeyID == "" || secretAccessKey == "" {
+				return errors.Errorf(
+					ctx,
+					"secret %s needs a username (access key id) and a password (secret access key)",
+					key,
+				)
+			}
+			output := awsCredentialProcessOutput{
+				Version:         1,
+				AccessKeyId:     accessKeyID.String(),
+				SecretAccessKey: secretAccessKey.String(),
+			}
+			if sessionToken != "" {
+				file, err := conn.File(ctx, sessionToken)
+				if err != nil {
+					return errors.Wrap(ctx, err, "get session token file failed")
+				}
+				content, err := file.Content()
+				if err != nil {
+					return errors.Wrapf(ctx, err, "decode session token file failed")
+				}
+				output.SessionToken = strings.TrimSpace(string(content))
+			}
+			encoded, err := json.Marshal(output)
+			if err != nil {
+				return errors.Wrapf(ctx, err, "marshal json failed")
+			}
+			if _, err := fmt.Fprintf(cmd.OutOrStdout(), "%s\n", encoded); err != nil {
+				return errors.Wrapf(ctx, err, "write credentials failed")
+			}
+			return nil
+		},
+	}
+
+	var key string
+	cmd.Flags().
+		StringVar(&key, "teamvault-key", "", "teamvault key (alternative to positional argument)")
+	cmd.Flags().StringVar(
+		&sessionTokenKey,
+		"session-token-key",
+		"",
+		"teamvault key of a file secret holding the session token",
+	)
+
+	return cmd
+}
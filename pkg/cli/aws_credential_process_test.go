@@ -0,0 +1,111 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	teamvault "github.com/Seibert-Data/teamvault-cli/v5/pkg"
+	"github.com/Seibert-Data/teamvault-cli/v5/pkg/cli"
+	"github.com/Seibert-Data/teamvault-cli/v5/pkg/mocks"
+)
+
+var _ = Describe("aws-credential-process", func() {
+	var ctx context.Context
+	var fakeConn *mocks.Connector
+	var outBuf bytes.Buffer
+	var resetConnector func()
+
+	execute := func(args ...string) error {
+		outBuf.Reset()
+		cmd := cli.NewRootCommand(ctx)
+		cmd.SetArgs(append([]string{"aws-credential-process"}, args...))
+		cmd.SetOut(&outBuf)
+		cmd.SetErr(&bytes.Buffer{})
+		return cmd.Execute()
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		os.Setenv("STAGING", "true")
+		fakeConn = &mocks.Connector{}
+		fakeConn.UserReturns(teamvault.User("AKIAEXAMPLE"), nil)
+		fakeConn.PasswordReturns(teamvault.Password("wJalrXUtnFEMI"), nil)
+		resetConnector = cli.SetNewConnectorForTest(
+			func(sf *cli.SharedFlags) func(context.Context) (teamvault.Connector, error) {
+				return func(ctx context.Context) (teamvault.Connector, error) {
+					return fakeConn, nil
+				}
+			},
+		)
+	})
+
+	AfterEach(func() {
+		resetConnector()
+		os.Unsetenv("STAGING")
+	})
+
+	It("prints a Version 1 document without a session token", func() {
+		Expect(execute("AWS001")).To(Succeed())
+
+		Expect(outBuf.String()).To(Equal(
+			`{"Version":1,"AccessKeyId":"AKIAEXAMPLE","SecretAccessKey":"wJalrXUtnFEMI"}` + "\n",
+		))
+		_, key := fakeConn.UserArgsForCall(0)
+		Expect(key).To(Equal(teamvault.Key("AWS001")))
+	})
+
+	It("adds the session token from a file secret", func() {
+		fakeConn.FileReturns(
+			teamvault.File(base64.StdEncoding.EncodeToString([]byte("FwoGZXIvYXdz\n"))),
+			nil,
+		)
+
+		Expect(execute("AWS001", "--session-token-key", "TOKEN1")).To(Succeed())
+
+		var doc map[string]any
+		Expect(json.Unmarshal(outBuf.Bytes(), &doc)).To(Succeed())
+		Expect(doc["SessionToken"]).To(Equal("FwoGZXIvYXdz"))
+		_, fileKey := fakeConn.FileArgsForCall(0)
+		Expect(fileKey).To(Equal(teamvault.Key("TOKEN1")))
+	})
+
+	It("resolves an alias given as --session-token-key", func() {
+		dir := GinkgoT().TempDir()
+		configPath := filepath.Join(dir, "config.json")
+		Expect(teamvault.AliasesPath(filepath.Join(dir, "config.aliases.json")).
+			Write(ctx, teamvault.Aliases{"aws-session": "TOKEN1"})).To(Succeed())
+		fakeConn.FileReturns(
+			teamvault.File(base64.StdEncoding.EncodeToString([]byte("FwoGZXIvYXdz"))),
+			nil,
+		)
+
+		Expect(execute(
+			"AWS001",
+			"--session-token-key",
+			"aws-session",
+			"--teamvault-config",
+			configPath,
+		)).To(Succeed())
+
+		_, fileKey := fakeConn.FileArgsForCall(0)
+		Expect(fileKey).To(Equal(teamvault.Key("TOKEN1")))
+	})
+
+	It("fails when the secret lacks an access key id", func() {
+		fakeConn.UserReturns(teamvault.User(""), nil)
+
+		Expect(execute("AWS001")).To(HaveOccurred())
+		Expect(outBuf.String()).To(BeEmpty())
+	})
+})
@@ -0,0 +1,435 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"filippo.io/age"
+	"github.com/bborbe/errors"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	teamvault "github.com/Seibert-Data/teamvault-cli/v5/pkg"
+)
+
+// backupPassphraseEnv names the variable a backup passphrase can be read
+// from, for unattended backups.
+const backupPassphraseEnv = "TEAMVAULT_BACKUP_PASSPHRASE"
+
+// createBackupCommand creates the backup command.
+func createBackupCommand(ctx context.Context, sf *SharedFlags) *cobra.Command {
+	var output string
+	var recipients []string
+	var recipientsFiles []string
+	var passphrase bool
+	var passphraseFile string
+
+	cmd := &cobra.Command{
+		Use:   "backup",
+		Short: "Write an encrypted archive of every secret you can access",
+		Long: `Write an encrypted archive of every secret you can access.
+
+All secrets found by an empty search are read with their metadata and
+password or file content, compressed and encrypted with age
+(https://age-encryption.org) to the given recipients, or to a passphrase
+from --passphrase-file, $` + backupPassphraseEnv + ` or the terminal.
+Secrets of other content types (e.g. credit cards) are listed as skipped.
+Secrets that cannot be read are skipped too; the archive is still written,
+but the command then exits non-zero.
+
+The disk cache (--cache) is never used: values are neither written to it
+unencrypted nor taken from it when the server fails.
+
+Check an archive with "restore --verify" and recreate its secrets with
+"restore".`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ageRecipients, err := backupRecipients(
+				ctx,
+				cmd,
+				recipients,
+				recipientsFiles,
+				passphrase || passphraseFile != "",
+				passphraseFile,
+			)
+			if err != nil {
+				return err
+			}
+			conn, err := newConnector(sf.withoutCache())(ctx)
+			if err != nil {
+				return errors.Wrap(ctx, err, "create connector failed")
+			}
+			backup, err := teamvault.NewBackupCreator(conn, newCurrentDateTime()).Create(ctx)
+			if err != nil {
+				return err
+			}
+			if err := writeBackupFile(ctx, output, backup, ageRecipients); err != nil {
+				return err
+			}
+			fmt.Fprintf(
+				cmd.ErrOrStderr(),
+				"Backed up %d secrets to %s.\n",
+				len(backup.Secrets),
+				output,
+			)
+			for _, skipped := range backup.Skipped {
+				fmt.Fprintf(
+					cmd.ErrOrStderr(),
+					"Skipped %s (%s): content type %s is not supported.\n",
+					skipped.Key,
+					skipped.Name,
+					skipped.ContentType,
+				)
+			}
+			for _, failed := range backup.Failed {
+				fmt.Fprintf(
+					cmd.ErrOrStderr(),
+					"Skipped %s (%s): %s.\n",
+					failed.Key,
+					failed.Name,
+					failed.Error,
+				)
+			}
+			if len(backup.Failed) > 0 {
+				return errors.Errorf(
+					ctx,
+					"backup incomplete: %d secrets could not be read",
+					len(backup.Failed),
+				)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&output, "output", "o", "", "archive to write")
+	cmd.Flags().StringArrayVarP(
+		&recipients,
+		"recipient",
+		"r",
+		nil,
+		"age public key (age1...) to encrypt to; repeatable",
+	)
+	cmd.Flags().StringArrayVarP(
+		&recipientsFiles,
+		"recipients-file",
+		"R",
+		nil,
+		"file with age public keys, one per line; repeatable",
+	)
+	cmd.Flags().BoolVar(&passphrase, "passphrase", false, "encrypt with a passphrase instead")
+	cmd.Flags().StringVar(
+		&passphraseFile,
+		"passphrase-file",
+		"",
+		"read the passphrase from this file (implies --passphrase)",
+	)
+	_ = cmd.MarkFlagRequired("output")
+	return cmd
+}
+
+// createRestoreCommand creates the restore command.
+func createRestoreCommand(ctx context.Context, sf *SharedFlags) *cobra.Command {
+	var identityFiles []string
+	var passphraseFile string
+	var verify bool
+
+	cmd := &cobra.Command{
+		Use:   "restore <archive>",
+		Short: "Recreate the secrets of a backup archive, or verify it",
+		Long: `Recreate the secrets of a backup archive, or verify it.
+
+The archive is decrypted with the age identity files given by --identity,
+or with a passphrase from --passphrase-file, $` + backupPassphraseEnv + ` or the
+terminal. Secrets whose name exists in TeamVault are left alone; the others
+are created under new keys, listed next to their old ones. Re-running a
+restore skips what was already restored.
+
+--verify decrypts and checks the archive and lists its secrets without
+connecting to TeamVault.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			identities, err := backupIdentities(ctx, cmd, identityFiles, passphraseFile)
+			if err != nil {
+				return err
+			}
+			file, err := os.Open(args[0])
+			if err != nil {
+				return errors.Wrapf(ctx, err, "open %s failed", args[0])
+			}
+			defer file.Close()
+			backup, err := teamvault.ReadBackup(ctx, file, identities...)
+			if err != nil {
+				return err
+			}
+			if verify {
+				return writeBackupContents(ctx, cmd.OutOrStdout(), backup)
+			}
+
+			conn, err := newConnector(sf.withoutCache())(ctx)
+			if err != nil {
+				return errors.Wrap(ctx, err, "create connector failed")
+			}
+			writer, err := newWriter(sf)(ctx)
+			if err != nil {
+				return errors.Wrap(ctx, err, "create writer failed")
+			}
+			results, restoreErr := teamvault.NewBackupRestorer(conn, writer).Restore(ctx, backup)
+			if err := writeRestoreResults(ctx, cmd.OutOrStdout(), results); err != nil {
+				return err
+			}
+			return restoreErr
+		},
+	}
+	cmd.Flags().StringArrayVarP(
+		&identityFiles,
+		"identity",
+		"i",
+		nil,
+		"age identity file to decrypt with; repeatable",
+	)
+	cmd.Flags().StringVar(
+		&passphraseFile,
+		"passphrase-file",
+		"",
+		"read the passphrase from this file",
+	)
+	cmd.Flags().BoolVar(&verify, "verify", false, "check the archive and list it without restoring")
+	return cmd
+}
+
+// backupRecipients collects the age recipients to encrypt to. age cannot
+// mix a passphrase with public keys, so exactly one kind is allowed.
+func backupRecipients(
+	ctx context.Context,
+	cmd *cobra.Command,
+	recipients []string,
+	recipientsFiles []string,
+	passphrase bool,
+	passphraseFile string,
+) ([]age.Recipient, error) {
+	hasKeys := len(recipients) > 0 || len(recipientsFiles) > 0
+	switch {
+	case hasKeys && passphrase:
+		return nil, errors.New(ctx, "--passphrase cannot be combined with --recipient")
+	case !hasKeys && !passphrase:
+		return nil, errors.New(ctx, "--recipient, --recipients-file or --passphrase is required")
+	case passphrase:
+		value, err := readBackupPassphrase(ctx, cmd, passphraseFile, true)
+		if err != nil {
+			return nil, err
+		}
+		recipient, err := age.NewScryptRecipient(value)
+		if err != nil {
+			return nil, errors.Wrapf(ctx, err, "create passphrase recipient failed")
+		}
+		return []age.Recipient{recipient}, nil
+	}
+	var result []age.Recipient
+	if len(recipients) > 0 {
+		parsed, err := age.ParseRecipients(strings.NewReader(strings.Join(recipients, "\n")))
+		if err != nil {
+			return nil, errors.Wrapf(ctx, err, "parse --recipient failed")
+		}
+		result = append(result, parsed...)
+	}
+	for _, path := range recipientsFiles {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, errors.Wrapf(ctx, err, "read recipients file %s failed", path)
+		}
+		parsed, err := age.ParseRecipients(strings.NewReader(string(content)))
+		if err != nil {
+			return nil, errors.Wrapf(ctx, err, "parse recipients file %s failed", path)
+		}
+		result = append(result, parsed...)
+	}
+	return result, nil
+}
+
+// backupIdentities reads the identity files, or asks for the passphrase
+// when none are given.
+func backupIdentities(
+	ctx context.Context,
+	cmd *cobra.Command,
+	identityFiles []string,
+	passphraseFile string,
+) ([]age.Identity, error) {
+	if len(identityFiles) == 0 {
+		value, err := readBackupPassphrase(ctx, cmd, passphraseFile, false)
+		if err != nil {
+			return nil, err
+		}
+		identity, err := age.NewScryptIdentity(value)
+		if err != nil {
+			return nil, errors.Wrapf(ctx, err, "create passphrase identity failed")
+		}
+		return []age.Identity{identity}, nil
+	}
+	var result []age.Identity
+	for _, path := range identityFiles {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, errors.Wrapf(ctx, err, "read identity file %s failed", path)
+		}
+		parsed, err := age.ParseIdentities(strings.NewReader(string(content)))
+		if err != nil {
+			return nil, errors.Wrapf(ctx, err, "parse identity file %s failed", path)
+		}
+		result = append(result, parsed...)
+	}
+	return result, nil
+}
+
+// readBackupPassphrase reads the passphrase from file, the environment or,
+// asking twice when confirm is set, from the terminal.
+func readBackupPassphrase(
+	ctx context.Context,
+	cmd *cobra.Command,
+	file string,
+	confirm bool,
+) (string, error) {
+	var value string
+	switch {
+	case file != "":
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return "", errors.Wrapf(ctx, err, "read passphrase file %s failed", file)
+		}
+		value = strings.TrimRight(string(content), "\r\n")
+	case os.Getenv(backupPassphraseEnv) != "":
+		value = os.Getenv(backupPassphraseEnv)
+	default:
+		fd := int(os.Stdin.Fd()) // #nosec G115 -- stdin fd is always a small non-negative integer
+		if !term.IsTerminal(fd) {
+			return "", errors.Errorf(
+				ctx,
+				"passphrase required: use --passphrase-file or $%s",
+				backupPassphraseEnv,
+			)
+		}
+		fmt.Fprint(cmd.ErrOrStderr(), "Passphrase: ")
+		first, err := term.ReadPassword(fd)
+		fmt.Fprintln(cmd.ErrOrStderr())
+		if err != nil {
+			return "", errors.Wrapf(ctx, err, "read passphrase failed")
+		}
+		if confirm {
+			fmt.Fprint(cmd.ErrOrStderr(), "Confirm passphrase: ")
+			second, err := term.ReadPassword(fd)
+			fmt.Fprintln(cmd.ErrOrStderr())
+			if err != nil {
+				return "", errors.Wrapf(ctx, err, "read passphrase failed")
+			}
+			if string(first) != string(second) {
+				return "", errors.New(ctx, "passphrases do not match")
+			}
+		}
+		value = string(first)
+	}
+	if value == "" {
+		return "", errors.New(ctx, "passphrase empty")
+	}
+	return value, nil
+}
+
+// writeBackupFile writes the archive next to path and renames it into place,
+// so a failed backup never replaces a good one.
+func writeBackupFile(
+	ctx context.Context,
+	path string,
+	backup teamvault.Backup,
+	recipients []age.Recipient,
+) error {
+	file, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return errors.Wrapf(ctx, err, "create %s failed", path)
+	}
+	defer os.Remove(file.Name())
+	if err := teamvault.WriteBackup(ctx, file, backup, recipients...); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return errors.Wrapf(ctx, err, "write %s failed", path)
+	}
+	if err := os.Rename(file.Name(), path); err != nil {
+		return errors.Wrapf(ctx, err, "write %s failed", path)
+	}
+	return nil
+}
+
+// writeBackupContents lists the secrets of a verified backup.
+func writeBackupContents(ctx context.Context, out io.Writer, backup teamvault.Backup) error {
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "KEY\tNAME\tTYPE\tLAST MODIFIED")
+	for _, secret := range backup.Secrets {
+		lastModified := ""
+		if !secret.LastModified.IsZero() {
+			lastModified = secret.LastModified.Format("2006-01-02 15:04")
+		}
+		fmt.Fprintf(
+			tw,
+			"%s\t%s\t%s\t%s\n",
+			secret.Key,
+			secret.Name,
+			secret.ContentType,
+			lastModified,
+		)
+	}
+	if err := tw.Flush(); err != nil {
+		return errors.Wrapf(ctx, err, "flush backup contents failed")
+	}
+	if _, err := fmt.Fprintf(
+		out,
+		"Backup of %s is valid: %d secrets.\n",
+		backup.Created.Format("2006-01-02 15:04 MST"),
+		len(backup.Secrets),
+	); err != nil {
+		return errors.Wrapf(ctx, err, "write backup contents failed")
+	}
+	return nil
+}
+
+// writeRestoreResults prints one line per secret followed by a summary.
+func writeRestoreResults(
+	ctx context.Context,
+	out io.Writer,
+	results []teamvault.RestoreResult,
+) error {
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "OLD KEY\tNAME\tSTATUS\tKEY\tREASON")
+	counts := map[teamvault.RestoreStatus]int{}
+	for _, result := range results {
+		counts[result.Status]++
+		fmt.Fprintf(
+			tw,
+			"%s\t%s\t%s\t%s\t%s\n",
+			result.Secret.Key,
+			result.Secret.Name,
+			result.Status,
+			result.Key,
+			result.Reason,
+		)
+	}
+	if err := tw.Flush(); err != nil {
+		return errors.Wrapf(ctx, err, "flush results failed")
+	}
+	if _, err := fmt.Fprintf(
+		out,
+		"Restore: %d created, %d exists, %d failed.\n",
+		counts[teamvault.RestoreCreated],
+		counts[teamvault.RestoreExists],
+		counts[teamvault.RestoreFailed],
+	); err != nil {
+		return errors.Wrapf(ctx, err, "write results failed")
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	stderrors "errors"
+	"os"
+	"path/filepath"
+
+	"filippo.io/age"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	teamvault "github.com/Seibert-Data/teamvault-cli/v5/pkg"
+	"github.com/Seibert-Data/teamvault-cli/v5/pkg/cli"
+	"github.com/Seibert-Data/teamvault-cli/v5/pkg/mocks"
+)
+
+var _ = Describe("backup and restore", func() {
+	var ctx context.Context
+	var fakeConn metadataConnector
+	var fakeWriter *mocks.Writer
+	var dir string
+	var archive string
+	var identityFile string
+	var recipient string
+	var stdout bytes.Buffer
+	var uncached []bool
+	var resets []func()
+
+	run := func(args ...string) error {
+		stdout.Reset()
+		cmd := cli.NewRootCommand(ctx)
+		cmd.SetArgs(args)
+		cmd.SetOut(&stdout)
+		cmd.SetErr(&bytes.Buffer{})
+		return cmd.Execute()
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		os.Setenv("STAGING", "true")
+		dir = GinkgoT().TempDir()
+		archive = filepath.Join(dir, "teamvault.age")
+		identity, err := age.GenerateX25519Identity()
+		Expect(err).To(BeNil())
+		recipient = identity.Recipient().String()
+		identityFile = filepath.Join(dir, "key.txt")
+		Expect(os.WriteFile(identityFile, []byte(identity.String()+"\n"), 0600)).To(Succeed())
+
+		fakeConn = metadataConnector{
+			Connector:      &mocks.Connector{},
+			MetadataReader: &mocks.MetadataReader{},
+		}
+		fakeConn.SearchStub = func(ctx context.Context, name string) ([]teamvault.SearchResult, error) {
+			if name == "" {
+				return []teamvault.SearchResult{{Key: "AbC123", Name: "payment-db"}}, nil
+			}
+			return nil, nil
+		}
+		fakeConn.MetadataReader.MetadataReturns(teamvault.SecretMetadata{
+			Key:         "AbC123",
+			Name:        "payment-db",
+			Username:    "payment",
+			ContentType: teamvault.ContentTypePassword,
+		}, nil)
+		fakeConn.PasswordReturns("s3cret", nil)
+		fakeWriter = &mocks.Writer{}
+		fakeWriter.CreateReturns("NeW456", "", nil)
+		uncached = nil
+		resets = []func(){
+			cli.SetNewConnectorForTest(
+				func(sf *cli.SharedFlags) func(context.Context) (teamvault.Connector, error) {
+					uncached = append(uncached, sf.Uncached())
+					return func(ctx context.Context) (teamvault.Connector, error) {
+						return fakeConn, nil
+					}
+				},
+			),
+			cli.SetNewWriterForTest(
+				func(sf *cli.SharedFlags) func(context.Context) (teamvault.Writer, error) {
+					return func(ctx context.Context) (teamvault.Writer, error) {
+						return fakeWriter, nil
+					}
+				},
+			),
+		}
+	})
+
+	AfterEach(func() {
+		for _, reset := range resets {
+			reset()
+		}
+		os.Unsetenv("STAGING")
+	})
+
+	It("verifies a backup encrypted to a recipient", func() {
+		Expect(run("backup", "-o", archive, "-r", recipient)).To(Succeed())
+		content, err := os.ReadFile(archive)
+		Expect(err).To(BeNil())
+		Expect(string(content)).To(HavePrefix("age-encryption.org/v1"))
+
+		Expect(run("restore", archive, "-i", identityFile, "--verify")).To(Succeed())
+		Expect(stdout.String()).To(MatchRegexp(`AbC123 +payment-db +password`))
+		Expect(stdout.String()).To(ContainSubstring("is valid: 1 secrets."))
+		Expect(fakeWriter.Invocations()).To(BeEmpty())
+	})
+
+	It("restores a passphrase-encrypted backup", func() {
+		passphraseFile := filepath.Join(dir, "passphrase")
+		Expect(os.WriteFile(passphraseFile, []byte("correct horse\n"), 0600)).To(Succeed())
+		Expect(run("backup", "-o", archive, "--passphrase-file", passphraseFile)).To(Succeed())
+
+		Expect(run("restore", archive, "--passphrase-file", passphraseFile)).To(Succeed())
+		Expect(stdout.String()).To(MatchRegexp(`AbC123 +payment-db +created +NeW456`))
+		_, secret := fakeWriter.CreateArgsForCall(0)
+		Expect(secret).To(Equal(teamvault.CreateSecret{
+			ContentType: teamvault.ContentTypePassword,
+			Name:        "payment-db",
+			Username:    "payment",
+			Password:    "s3cret",
+		}))
+	})
+
+	It("reads without the disk cache", func() {
+		Expect(run("backup", "-o", archive, "-r", recipient, "--cache")).To(Succeed())
+		Expect(run("restore", archive, "-i", identityFile, "--cache")).To(Succeed())
+
+		Expect(uncached).To(Equal([]bool{true, true}))
+	})
+
+	It("writes the archive but fails if a secret cannot be read", func() {
+		fakeConn.PasswordReturns("", stderrors.New("forbidden"))
+
+		err := run("backup", "-o", archive, "-r", recipient)
+
+		Expect(err).NotTo(BeNil())
+		Expect(err.Error()).To(ContainSubstring("1 secrets could not be read"))
+		Expect(archive).To(BeAnExistingFile())
+	})
+
+	It("requires a recipient or a passphrase", func() {
+		err := run("backup", "-o", archive)
+		Expect(err).NotTo(BeNil())
+		Expect(err.Error()).To(ContainSubstring("--passphrase is required"))
+		_, statErr := os.Stat(archive)
+		Expect(os.IsNotExist(statErr)).To(BeTrue())
+	})
+})
@@ -0,0 +1,618 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/bborbe/errors"
+	"github.com/spf13/cobra"
+
+	teamvault "github.com/Seibert-Data/teamvault-cli/v5/pkg"
+)
+
+// browseSearchLimit caps the results shown for one query; refine the query
+// to see others.
+const browseSearchLimit = 200
+
+const browseMask = "••••••••"
+
+// browseMode is the part of the browser receiving key presses.
+type browseMode int
+
+const (
+	browseModeSearch browseMode = iota
+	browseModeList
+	browseModeEditField
+	browseModeEditValue
+	browseModeConfirm
+)
+
+// browseEditFields are the fields the edit flow can change, by the key
+// selecting them.
+var browseEditFields = []struct {
+	key   rune
+	field string
+}{
+	{key: 'n', field: "name"},
+	{key: 'u', field: "username"},
+	{key: 'l', field: "url"},
+	{key: 'd', field: "description"},
+	{key: 'p', field: "password"},
+}
+
+// createBrowseCommand creates the `browse` subcommand.
+func createBrowseCommand(ctx context.Context, sf *SharedFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "browse [query]",
+		Short: "Browse secrets in an interactive full-screen terminal UI",
+		Long: `Browse secrets in an interactive full-screen terminal UI.
+
+Type to search; the results update as you type. Enter, Tab or Down moves to
+the result list, where these keys act on the selected secret:
+
+  j/k, Up/Down  move
+  r             reveal or hide the password
+  c             copy the password (OSC 52)
+  y             copy the username (OSC 52)
+  o             open the secret in the web browser
+  e             edit a field, then confirm the update
+  /, Tab        back to the search field
+  q, Esc        quit
+
+Copied values are cleared from the clipboard after --clip-timeout.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			timeout, err := clipTimeout(ctx, cmd)
+			if err != nil {
+				return err
+			}
+			conn, err := newConnector(sf)(ctx)
+			if err != nil {
+				return errors.Wrap(ctx, err, "create connector failed")
+			}
+			vaultUrl, err := sf.vaultUrl(ctx)
+			if err != nil {
+				return err
+			}
+			terminal, err := openBrowseTerminal()
+			if err != nil {
+				return errors.Wrapf(ctx, err, "browse needs a terminal")
+			}
+			defer terminal.Close()
+
+			b := &browser{
+				ctx:         ctx,
+				source:      conn,
+				conn:        teamvault.NewCacheConnector(conn),
+				newWriter:   newWriter(sf),
+				vaultUrl:    vaultUrl,
+				clipTimeout: timeout,
+				out:         terminal,
+				width:       80,
+				height:      24,
+			}
+			if width, height, err := terminal.Size(); err == nil && width > 0 && height > 0 {
+				b.width, b.height = width, height
+			}
+			if len(args) > 0 {
+				b.setQuery(args[0])
+			}
+			return b.run(terminal)
+		},
+	}
+	cmd.Flags().String(
+		"clip-timeout",
+		envOrDefault("TEAMVAULT_CLIP_TIMEOUT", defaultClipTimeout.String()),
+		"clear the clipboard after this duration (0 = never)",
+	)
+	return cmd
+}
+
+// browser is the state of the `browse` UI. Key presses are applied by
+// handle; after each batch of input the search is refreshed once and the
+// whole screen is redrawn.
+type browser struct {
+	ctx context.Context
+	// source is the connector; conn caches it for repeated reveal/copy.
+	source      teamvault.Connector
+	conn        teamvault.Connector
+	newWriter   func(context.Context) (teamvault.Writer, error)
+	vaultUrl    teamvault.Url
+	clipTimeout time.Duration
+	out         io.Writer
+	width       int
+	height      int
+
+	mode     browseMode
+	query    string
+	stale    bool
+	results  []teamvault.SearchResult
+	selected int
+	offset   int
+	// revealed is the password shown for the selected secret, if any.
+	revealed *teamvault.Password
+	status   string
+
+	editField string
+	editValue []rune
+	quit      bool
+}
+
+// run enters the alternate screen and processes input until quit or EOF.
+func (b *browser) run(in io.Reader) error {
+	fmt.Fprint(b.out, "\x1b[?1049h\x1b[?25l")
+	defer fmt.Fprint(b.out, "\x1b[?25h\x1b[?1049l")
+
+	b.refresh()
+	b.render()
+	buf := make([]byte, 256)
+	for !b.quit {
+		n, err := in.Read(buf)
+		if n > 0 {
+			for _, key := range decodeKeys(buf[:n]) {
+				b.handle(key)
+				if b.quit {
+					return nil
+				}
+			}
+			b.refresh()
+			b.render()
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrapf(b.ctx, err, "read terminal failed")
+		}
+	}
+	return nil
+}
+
+func (b *browser) setQuery(query string) {
+	b.query = query
+	b.stale = true
+}
+
+// refresh runs the search for the current query if it changed. Typing a
+// burst of characters therefore costs a single search.
+func (b *browser) refresh() {
+	if !b.stale {
+		return
+	}
+	b.stale = false
+	b.results = nil
+	b.selected, b.offset = 0, 0
+	b.revealed = nil
+	if strings.TrimSpace(b.query) == "" {
+		b.status = ""
+		return
+	}
+	for result, err := range teamvault.SearchSeq(
+		b.ctx,
+		b.conn,
+		teamvault.SearchQuery{Name: b.query, Limit: browseSearchLimit},
+	) {
+		if err != nil {
+			b.status = "Search failed: " + err.Error()
+			return
+		}
+		b.results = append(b.results, result)
+	}
+	b.status = fmt.Sprintf("%d results", len(b.results))
+}
+
+func (b *browser) current() (teamvault.SearchResult, bool) {
+	if b.selected < 0 || b.selected >= len(b.results) {
+		return teamvault.SearchResult{}, false
+	}
+	return b.results[b.selected], true
+}
+
+func (b *browser) handle(key browseKey) {
+	if key.code == keyCtrlC {
+		b.quit = true
+		return
+	}
+	switch b.mode {
+	case browseModeSearch:
+		b.handleSearch(key)
+	case browseModeList:
+		b.refresh()
+		b.handleList(key)
+	case browseModeEditField:
+		b.handleEditField(key)
+	case browseModeEditValue:
+		b.handleEditValue(key)
+	case browseModeConfirm:
+		b.handleConfirm(key)
+	}
+}
+
+func (b *browser) handleSearch(key browseKey) {
+	switch key.code {
+	case keyRune:
+		b.setQuery(b.query + string(key.r))
+	case keyBackspace:
+		if b.query != "" {
+			_, size := utf8.DecodeLastRuneInString(b.query)
+			b.setQuery(b.query[:len(b.query)-size])
+		}
+	case keyEnter, keyTab, keyDown:
+		b.mode = browseModeList
+	case keyEsc:
+		b.quit = true
+	}
+}
+
+func (b *browser) handleList(key browseKey) {
+	switch {
+	case key.code == keyUp || key.code == keyRune && key.r == 'k':
+		b.move(-1)
+	case key.code == keyDown || key.code == keyRune && key.r == 'j':
+		b.move(1)
+	case key.code == keyTab || key.code == keyRune && key.r == '/':
+		b.mode = browseModeSearch
+	case key.code == keyEsc || key.code == keyRune && key.r == 'q':
+		b.quit = true
+	case key.code == keyRune:
+		result, ok := b.current()
+		if !ok {
+			b.status = "No secret selected."
+			return
+		}
+		switch key.r {
+		case 'r':
+			b.toggleReveal(result)
+		case 'c':
+			b.copyPassword(result)
+		case 'y':
+			b.copy("username of "+result.Key.String(), result.Username)
+		case 'o':
+			b.open(result)
+		case 'e':
+			b.mode = browseModeEditField
+			b.status = "Edit which field? [n]ame [u]sername ur[l] [d]escription [p]assword (Esc cancels)"
+		}
+	}
+}
+
+func (b *browser) move(delta int) {
+	if len(b.results) == 0 {
+		return
+	}
+	b.selected = min(max(b.selected+delta, 0), len(b.results)-1)
+	b.revealed = nil
+}
+
+func (b *browser) toggleReveal(result teamvault.SearchResult) {
+	if b.revealed != nil {
+		b.revealed = nil
+		return
+	}
+	password, err := b.conn.Password(b.ctx, result.Key)
+	if err != nil {
+		b.status = "Read password failed: " + err.Error()
+		return
+	}
+	b.revealed = &password
+}
+
+func (b *browser) copyPassword(result teamvault.SearchResult) {
+	password, err := b.conn.Password(b.ctx, result.Key)
+	if err != nil {
+		b.status = "Read password failed: " + err.Error()
+		return
+	}
+	b.copy("password of "+result.Key.String(), password.String())
+}
+
+// copy writes value to the clipboard via OSC 52 on the browse terminal and
+// schedules the clear like --clip does.
+func (b *browser) copy(what, value string) {
+	if _, err := io.WriteString(b.out, osc52Sequence(value, inTmux())); err != nil {
+		b.status = "Copy failed: " + err.Error()
+		return
+	}
+	if b.clipTimeout == 0 {
+		b.status = fmt.Sprintf("Copied %s to clipboard.", what)
+		return
+	}
+	if err := scheduleClipClear(b.clipTimeout); err != nil {
+		b.status = "Schedule clipboard clear failed: " + err.Error()
+		return
+	}
+	b.status = fmt.Sprintf("Copied %s to clipboard, clearing in %v.", what, b.clipTimeout)
+}
+
+func (b *browser) open(result teamvault.SearchResult) {
+	if b.vaultUrl == "" {
+		b.status = "No TeamVault URL configured."
+		return
+	}
+	url := secretWebURL(b.vaultUrl, result.Key)
+	if err := openURL(url); err != nil {
+		b.status = "Open browser failed: " + err.Error()
+		return
+	}
+	b.status = "Opened " + url
+}
+
+// secretWebURL returns the web UI page of a secret.
+func secretWebURL(vaultUrl teamvault.Url, key teamvault.Key) string {
+	return fmt.Sprintf("%s/secrets/%s/", vaultUrl.Normalize(), key)
+}
+
+func (b *browser) handleEditField(key browseKey) {
+	if key.code == keyEsc {
+		b.mode = browseModeList
+		b.status = "Edit cancelled."
+		return
+	}
+	if key.code != keyRune {
+		return
+	}
+	result, ok := b.current()
+	if !ok {
+		b.mode = browseModeList
+		return
+	}
+	for _, f := range browseEditFields {
+		if f.key != key.r {
+			continue
+		}
+		b.editField = f.field
+		b.editValue = []rune(editableValue(result, f.field))
+		b.mode = browseModeEditValue
+		b.status = ""
+		return
+	}
+}
+
+// editableValue returns the current value the edit prompt starts with. The
+// password starts empty so it is never shown unasked.
+func editableValue(result teamvault.SearchResult, field string) string {
+	switch field {
+	case "name":
+		return result.Name
+	case "username":
+		return result.Username
+	case "url":
+		return result.Url.String()
+	case "description":
+		return result.Description
+	default:
+		return ""
+	}
+}
+
+func (b *browser) handleEditValue(key browseKey) {
+	switch key.code {
+	case keyRune:
+		b.editValue = append(b.editValue, key.r)
+	case keyBackspace:
+		if len(b.editValue) > 0 {
+			b.editValue = b.editValue[:len(b.editValue)-1]
+		}
+	case keyEnter:
+		b.mode = browseModeConfirm
+	case keyEsc:
+		b.mode = browseModeList
+		b.status = "Edit cancelled."
+	}
+}
+
+func (b *browser) handleConfirm(key browseKey) {
+	b.mode = browseModeList
+	if key.code != keyRune || key.r != 'y' && key.r != 'Y' {
+		b.status = "Edit cancelled."
+		return
+	}
+	result, ok := b.current()
+	if !ok {
+		return
+	}
+	value := string(b.editValue)
+	if value == "" && (b.editField == "name" || b.editField == "password") {
+		b.status = fmt.Sprintf("Empty %s not allowed; edit cancelled.", b.editField)
+		return
+	}
+	var secret teamvault.UpdateSecret
+	switch b.editField {
+	case "name":
+		secret.Name = &value
+	case "username":
+		secret.Username = &value
+	case "url":
+		secret.Url = &value
+	case "description":
+		secret.Description = &value
+	case "password":
+		password := teamvault.Password(value)
+		secret.Password = &password
+	}
+	writer, err := b.newWriter(b.ctx)
+	if err != nil {
+		b.status = "Create writer failed: " + err.Error()
+		return
+	}
+	if _, _, err := writer.Update(b.ctx, result.Key, secret); err != nil {
+		b.status = "Update failed: " + err.Error()
+		return
+	}
+	switch b.editField {
+	case "name":
+		b.results[b.selected].Name = value
+	case "username":
+		b.results[b.selected].Username = value
+	case "url":
+		b.results[b.selected].Url = teamvault.Url(value)
+	case "description":
+		b.results[b.selected].Description = value
+	case "password":
+		b.revealed = nil
+		// Reads go through a cache; start over so the new value is fetched.
+		b.conn = teamvault.NewCacheConnector(b.source)
+	}
+	b.status = fmt.Sprintf("Updated %s of %s.", b.editField, result.Key)
+}
+
+// screenLine is one row of the screen; highlighted rows are shown in
+// reverse video.
+type screenLine struct {
+	text      string
+	highlight bool
+}
+
+// render redraws the whole screen: search line, result list, metadata pane,
+// status line and key help. Every row is stripped of control characters, as
+// names and values come from other users' secrets, and cut to the width.
+func (b *browser) render() {
+	const metadataLines = 9
+	listRows := max(b.height-metadataLines-6, 3)
+	if b.selected < b.offset {
+		b.offset = b.selected
+	}
+	if b.selected >= b.offset+listRows {
+		b.offset = b.selected - listRows + 1
+	}
+
+	var lines []screenLine
+	add := func(texts ...string) {
+		for _, text := range texts {
+			lines = append(lines, screenLine{text: text})
+		}
+	}
+	cursor := ""
+	if b.mode == browseModeSearch {
+		cursor = "█"
+	}
+	add("Search: "+b.query+cursor, strings.Repeat("─", b.width))
+	for row := 0; row < listRows; row++ {
+		i := b.offset + row
+		if i >= len(b.results) {
+			add("")
+			continue
+		}
+		marker := " "
+		if i == b.selected {
+			marker = ">"
+		}
+		lines = append(lines, screenLine{
+			text:      fmt.Sprintf("%s %-12s %s", marker, b.results[i].Key, b.results[i].Name),
+			highlight: i == b.selected,
+		})
+	}
+	add(strings.Repeat("─", b.width))
+	add(b.metadata()...)
+	add(strings.Repeat("─", b.width), b.prompt(), b.help())
+
+	var screen strings.Builder
+	screen.WriteString("\x1b[H\x1b[2J")
+	for i, line := range lines {
+		if i > 0 {
+			screen.WriteString("\r\n")
+		}
+		text := truncate(stripControl(line.text), b.width)
+		if line.highlight {
+			text = "\x1b[7m" + text + "\x1b[0m"
+		}
+		screen.WriteString(text)
+	}
+	_, _ = io.WriteString(b.out, screen.String())
+}
+
+// stripControl replaces C0 and C1 control characters, which could carry
+// terminal escape sequences, with a space (line breaks and tabs) or '?'.
+// Invalid UTF-8 becomes U+FFFD.
+func stripControl(text string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '\n' || r == '\r' || r == '\t':
+			return ' '
+		case unicode.IsControl(r):
+			return '?'
+		default:
+			return r
+		}
+	}, text)
+}
+
+func (b *browser) metadata() []string {
+	result, ok := b.current()
+	if !ok {
+		return make([]string, 9)
+	}
+	password := browseMask + "  (r to reveal)"
+	if b.revealed != nil {
+		password = b.revealed.String()
+	}
+	modified := ""
+	if !result.LastModified.IsZero() {
+		modified = result.LastModified.Local().Format(time.DateTime)
+	}
+	return []string{
+		"Key:         " + result.Key.String(),
+		"Name:        " + result.Name,
+		"Username:    " + result.Username,
+		"URL:         " + result.Url.String(),
+		"Description: " + strings.ReplaceAll(result.Description, "\n", " "),
+		"Type:        " + result.ContentType,
+		"Status:      " + result.Status,
+		"Modified:    " + modified,
+		"Password:    " + password,
+	}
+}
+
+func (b *browser) prompt() string {
+	switch b.mode {
+	case browseModeEditValue:
+		value := string(b.editValue)
+		if b.editField == "password" {
+			value = strings.Repeat("•", len(b.editValue))
+		}
+		return fmt.Sprintf("New %s: %s█", b.editField, value)
+	case browseModeConfirm:
+		result, _ := b.current()
+		value := string(b.editValue)
+		if b.editField == "password" {
+			value = browseMask
+		}
+		return fmt.Sprintf("Update %s of %s to %q? [y/N]", b.editField, result.Key, value)
+	default:
+		return b.status
+	}
+}
+
+func (b *browser) help() string {
+	switch b.mode {
+	case browseModeSearch:
+		return "type to search · Enter/Tab: results · Esc: quit"
+	case browseModeList:
+		return "j/k: move · r: reveal · c: copy password · y: copy username · o: open · e: edit · /: search · q: quit"
+	case browseModeEditValue:
+		return "Enter: confirm · Esc: cancel"
+	default:
+		return ""
+	}
+}
+
+// truncate cuts line to at most width runes.
+func truncate(line string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	if utf8.RuneCountInString(line) <= width {
+		return line
+	}
+	runes := []rune(line)
+	return string(runes[:width])
+}
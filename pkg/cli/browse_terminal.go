@@ -0,0 +1,170 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cli
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+)
+
+// browseTerminal is the full-screen terminal `browse` draws on.
+type browseTerminal interface {
+	io.ReadWriter
+	// Size returns the terminal's width and height in cells.
+	Size() (width, height int, err error)
+	// Close restores the terminal mode.
+	Close() error
+}
+
+// openBrowseTerminal opens the controlling terminal in raw mode.
+// Overridden by tests via SetBrowseTerminalForTest.
+var openBrowseTerminal = func() (browseTerminal, error) {
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return nil, err
+	}
+	state, err := term.MakeRaw(int(tty.Fd()))
+	if err != nil {
+		tty.Close()
+		return nil, err
+	}
+	return &ttyBrowseTerminal{File: tty, state: state}, nil
+}
+
+type ttyBrowseTerminal struct {
+	*os.File
+	state *term.State
+}
+
+func (t *ttyBrowseTerminal) Size() (int, int, error) {
+	return term.GetSize(int(t.Fd()))
+}
+
+func (t *ttyBrowseTerminal) Close() error {
+	restoreErr := term.Restore(int(t.Fd()), t.state)
+	closeErr := t.File.Close()
+	if restoreErr != nil {
+		return restoreErr
+	}
+	return closeErr
+}
+
+// SetBrowseTerminalForTest makes `browse` read keys from in and draw to out
+// on a terminal of the given size. Returns a function to call in AfterEach
+// to reset.
+func SetBrowseTerminalForTest(in io.Reader, out io.Writer, width, height int) func() {
+	prev := openBrowseTerminal
+	openBrowseTerminal = func() (browseTerminal, error) {
+		return &fakeBrowseTerminal{Reader: in, Writer: out, width: width, height: height}, nil
+	}
+	return func() { openBrowseTerminal = prev }
+}
+
+type fakeBrowseTerminal struct {
+	io.Reader
+	io.Writer
+	width, height int
+}
+
+func (f *fakeBrowseTerminal) Size() (int, int, error) { return f.width, f.height, nil }
+
+func (f *fakeBrowseTerminal) Close() error { return nil }
+
+// openURL opens a URL in the desktop browser. Overridden by tests via
+// SetOpenURLForTest.
+var openURL = func(url string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+	// #nosec G204 -- fixed opener binary, the URL is passed as one argument
+	return cmd.Start()
+}
+
+// SetOpenURLForTest replaces the browser opener used by `browse`.
+// Returns a function to call in AfterEach to reset.
+func SetOpenURLForTest(f func(url string) error) func() {
+	prev := openURL
+	openURL = f
+	return func() { openURL = prev }
+}
+
+// keyCode identifies a decoded key press.
+type keyCode int
+
+const (
+	keyRune keyCode = iota
+	keyEnter
+	keyTab
+	keyBackspace
+	keyEsc
+	keyUp
+	keyDown
+	keyCtrlC
+)
+
+// browseKey is one key press; r is set for keyRune.
+type browseKey struct {
+	code keyCode
+	r    rune
+}
+
+// decodeKeys splits raw terminal input into key presses. Escape sequences
+// other than the arrow keys are dropped; a lone ESC is the Esc key.
+func decodeKeys(input []byte) []browseKey {
+	var keys []browseKey
+	for len(input) > 0 {
+		switch b := input[0]; {
+		case b == 0x1b:
+			if len(input) >= 3 && (input[1] == '[' || input[1] == 'O') {
+				switch input[2] {
+				case 'A':
+					keys = append(keys, browseKey{code: keyUp})
+				case 'B':
+					keys = append(keys, browseKey{code: keyDown})
+				}
+				input = input[3:]
+				continue
+			}
+			keys = append(keys, browseKey{code: keyEsc})
+			input = input[1:]
+		case b == '\r' || b == '\n':
+			keys = append(keys, browseKey{code: keyEnter})
+			input = input[1:]
+		case b == '\t':
+			keys = append(keys, browseKey{code: keyTab})
+			input = input[1:]
+		case b == 0x7f || b == 0x08:
+			keys = append(keys, browseKey{code: keyBackspace})
+			input = input[1:]
+		case b == 0x03:
+			keys = append(keys, browseKey{code: keyCtrlC})
+			input = input[1:]
+		case b == 0x0e:
+			keys = append(keys, browseKey{code: keyDown})
+			input = input[1:]
+		case b == 0x10:
+			keys = append(keys, browseKey{code: keyUp})
+			input = input[1:]
+		case b < 0x20:
+			input = input[1:]
+		default:
+			r, size := utf8.DecodeRune(input)
+			keys = append(keys, browseKey{code: keyRune, r: r})
+			input = input[size:]
+		}
+	}
+	return keys
+}
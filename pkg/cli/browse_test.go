@@ -0,0 +1,230 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	teamvault "github.com/Seibert-Data/teamvault-cli/v5/pkg"
+	"github.com/Seibert-Data/teamvault-cli/v5/pkg/cli"
+	"github.com/Seibert-Data/teamvault-cli/v5/pkg/mocks"
+)
+
+// keyPresses is a fake terminal input returning one chunk per Read, like a
+// terminal delivering separate key presses.
+type keyPresses struct {
+	chunks []string
+}
+
+func (k *keyPresses) Read(p []byte) (int, error) {
+	if len(k.chunks) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(p, k.chunks[0])
+	k.chunks = k.chunks[1:]
+	return n, nil
+}
+
+var _ = Describe("browse", func() {
+	var ctx context.Context
+	var fakeConn *mocks.Connector
+	var fakeWriter *mocks.Writer
+	var screen bytes.Buffer
+	var scheduled []time.Duration
+	var opened []string
+	var configPath string
+	var resets []func()
+
+	browse := func(chunks ...string) error {
+		screen.Reset()
+		resets = append(
+			resets,
+			cli.SetBrowseTerminalForTest(&keyPresses{chunks: chunks}, &screen, 100, 30),
+		)
+		cmd := cli.NewRootCommand(ctx)
+		cmd.SetArgs([]string{"browse", "--teamvault-config", configPath})
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(&bytes.Buffer{})
+		return cmd.Execute()
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		configPath = filepath.Join(GinkgoT().TempDir(), "config.json")
+		Expect(os.WriteFile(
+			configPath,
+			[]byte(`{"url": "https://vault.example.com", "user": "u", "pass": "p"}`),
+			0600,
+		)).To(Succeed())
+		scheduled = nil
+		opened = nil
+
+		fakeConn = &mocks.Connector{}
+		fakeConn.SearchReturns([]teamvault.SearchResult{
+			{Key: "AbC123", Name: "prod db", Username: "admin", ContentType: "password"},
+			{Key: "XyZ789", Name: "staging db", Username: "deploy"},
+		}, nil)
+		fakeConn.PasswordStub = func(ctx context.Context, key teamvault.Key) (teamvault.Password, error) {
+			return teamvault.Password("pass-" + key.String()), nil
+		}
+		fakeWriter = &mocks.Writer{}
+		resets = []func(){
+			cli.SetNewConnectorForTest(
+				func(sf *cli.SharedFlags) func(context.Context) (teamvault.Connector, error) {
+					return func(ctx context.Context) (teamvault.Connector, error) {
+						return fakeConn, nil
+					}
+				},
+			),
+			cli.SetNewWriterForTest(
+				func(sf *cli.SharedFlags) func(context.Context) (teamvault.Writer, error) {
+					return func(ctx context.Context) (teamvault.Writer, error) {
+						return fakeWriter, nil
+					}
+				},
+			),
+			cli.SetClipboardForTest(&bytes.Buffer{}, func(after time.Duration) error {
+				scheduled = append(scheduled, after)
+				return nil
+			}),
+			cli.SetOpenURLForTest(func(url string) error {
+				opened = append(opened, url)
+				return nil
+			}),
+		}
+	})
+
+	AfterEach(func() {
+		for _, reset := range resets {
+			reset()
+		}
+	})
+
+	It("searches once per burst of typed characters", func() {
+		Expect(browse("db")).To(Succeed())
+
+		Expect(fakeConn.SearchCallCount()).To(Equal(1))
+		_, query := fakeConn.SearchArgsForCall(0)
+		Expect(query).To(Equal("db"))
+		Expect(screen.String()).To(ContainSubstring("prod db"))
+		Expect(screen.String()).To(ContainSubstring("staging db"))
+	})
+
+	It("searches again as the query changes", func() {
+		Expect(browse("d", "b", "\x7f")).To(Succeed())
+
+		Expect(fakeConn.SearchCallCount()).To(Equal(3))
+		_, query := fakeConn.SearchArgsForCall(2)
+		Expect(query).To(Equal("d"))
+	})
+
+	It("masks the password until revealed", func() {
+		Expect(browse("db", "\r")).To(Succeed())
+		Expect(screen.String()).To(ContainSubstring("Username:    admin"))
+		Expect(screen.String()).To(ContainSubstring("Password:    ••••••••"))
+		Expect(screen.String()).NotTo(ContainSubstring("pass-AbC123"))
+		Expect(fakeConn.PasswordCallCount()).To(Equal(0))
+
+		Expect(browse("db", "\r", "j", "r")).To(Succeed())
+		Expect(screen.String()).To(ContainSubstring("Password:    pass-XyZ789"))
+	})
+
+	It("copies the password via OSC 52 and schedules the clear", func() {
+		Expect(browse("db", "\r", "c")).To(Succeed())
+
+		encoded := base64.StdEncoding.EncodeToString([]byte("pass-AbC123"))
+		Expect(screen.String()).To(ContainSubstring("\x1b]52;c;" + encoded + "\x07"))
+		Expect(screen.String()).To(ContainSubstring("Copied password of AbC123 to clipboard"))
+		Expect(scheduled).To(Equal([]time.Duration{45 * time.Second}))
+	})
+
+	It("copies the username", func() {
+		Expect(browse("db", "\r", "y")).To(Succeed())
+
+		encoded := base64.StdEncoding.EncodeToString([]byte("admin"))
+		Expect(screen.String()).To(ContainSubstring("\x1b]52;c;" + encoded + "\x07"))
+		Expect(fakeConn.PasswordCallCount()).To(Equal(0))
+	})
+
+	It("opens the web page of the selected secret", func() {
+		Expect(browse("db", "\r", "j", "o")).To(Succeed())
+
+		Expect(opened).To(Equal([]string{"https://vault.example.com/secrets/XyZ789/"}))
+	})
+
+	It("updates a field after confirmation", func() {
+		Expect(
+			browse("db", "\r", "e", "u", "\x7f\x7f\x7f\x7f\x7f", "root", "\r", "y"),
+		).To(Succeed())
+
+		Expect(fakeWriter.UpdateCallCount()).To(Equal(1))
+		_, key, secret := fakeWriter.UpdateArgsForCall(0)
+		Expect(key).To(Equal(teamvault.Key("AbC123")))
+		Expect(*secret.Username).To(Equal("root"))
+		Expect(secret.Password).To(BeNil())
+		Expect(screen.String()).To(ContainSubstring("Updated username of AbC123."))
+		Expect(screen.String()).To(ContainSubstring("Username:    root"))
+	})
+
+	It("masks a new password in the prompt", func() {
+		Expect(browse("db", "\r", "e", "p", "s3cr3t", "\r", "y")).To(Succeed())
+
+		Expect(screen.String()).NotTo(ContainSubstring("s3cr3t"))
+		_, _, secret := fakeWriter.UpdateArgsForCall(0)
+		Expect(*secret.Password).To(Equal(teamvault.Password("s3cr3t")))
+	})
+
+	It("does not update without confirmation", func() {
+		Expect(browse("db", "\r", "e", "n", "new name", "\r", "n")).To(Succeed())
+
+		Expect(fakeWriter.UpdateCallCount()).To(Equal(0))
+		Expect(screen.String()).To(ContainSubstring("Edit cancelled."))
+	})
+
+	It("rejects an empty password or name", func() {
+		Expect(browse("db", "\r", "e", "p", "\r", "y")).To(Succeed())
+		Expect(screen.String()).To(ContainSubstring("Empty password not allowed; edit cancelled."))
+
+		Expect(
+			browse("db", "\r", "e", "n", strings.Repeat("\x7f", len("prod db")), "\r", "y"),
+		).To(Succeed())
+		Expect(screen.String()).To(ContainSubstring("Empty name not allowed; edit cancelled."))
+
+		Expect(fakeWriter.UpdateCallCount()).To(Equal(0))
+	})
+
+	It("strips control characters from secret fields", func() {
+		fakeConn.SearchReturns([]teamvault.SearchResult{{
+			Key:      "AbC123",
+			Name:     "evil\x1b]52;c;ZXZpbA==\x07",
+			Username: "\x1b[1;1H" + strings.Repeat("x", 200),
+		}}, nil)
+
+		Expect(browse("db", "\r")).To(Succeed())
+
+		Expect(screen.String()).NotTo(ContainSubstring("\x1b]52"))
+		Expect(screen.String()).NotTo(ContainSubstring("\x1b[1;1H"))
+		Expect(screen.String()).To(ContainSubstring("Name:        evil?]52;c;ZXZpbA==?"))
+		Expect(screen.String()).To(ContainSubstring("Username:    ?[1;1H"))
+		Expect(screen.String()).NotTo(ContainSubstring(strings.Repeat("x", 100)))
+	})
+
+	It("quits on q and restores the screen", func() {
+		Expect(browse("db", "\r", "q", "c")).To(Succeed())
+
+		Expect(fakeConn.PasswordCallCount()).To(Equal(0))
+		Expect(screen.String()).To(HaveSuffix("\x1b[?25h\x1b[?1049l"))
+	})
+})
@@ -15,6 +15,7 @@ import (
 	"os/signal"
 	"path/filepath"
 	"runtime/debug"
+	"strings"
 	"syscall"
 
 	"github.com/bborbe/errors"
@@ -102,12 +103,40 @@ func Execute() {
 		slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn})),
 	)
 
-	if err := Run(ctx, os.Args[1:]); err != nil {
+	if err := Run(ctx, argsForInvocation(os.Args[0], os.Args[1:])); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 }
 
+// helperInvocations maps binary-name prefixes to subcommands. Credential
+// helpers are found by name: Docker's `"credsStore": "teamvault"` runs
+// docker-credential-teamvault and git's `credential.helper = teamvault` runs
+// git-credential-teamvault, each with only the protocol verb as argument.
+// SSH_ASKPASS/SUDO_ASKPASS take a program path without arguments, hence the
+// teamvault-askpass name.
+var helperInvocations = []struct {
+	prefix  string
+	command string
+}{
+	{prefix: "docker-credential-", command: "docker-credential"},
+	{prefix: "git-credential-", command: "git-credential"},
+	{prefix: "teamvault-askpass", command: "askpass"},
+}
+
+// argsForInvocation maps a multi-call invocation onto the matching subcommand,
+// so a teamvault-cli symlinked as e.g. docker-credential-teamvault behaves like
+// `teamvault-cli docker-credential`.
+func argsForInvocation(argv0 string, args []string) []string {
+	name := filepath.Base(argv0)
+	for _, h := range helperInvocations {
+		if strings.HasPrefix(name, h.prefix) {
+			return append([]string{h.command}, args...)
+		}
+	}
+	return args
+}
+
 // Run builds the root command and executes it with the given arguments.
 // It returns any error from command execution.
 func Run(ctx context.Context, args []string) error {
@@ -126,6 +155,20 @@ type SharedFlags struct {
 	staging    bool
 	cache      bool
 	timeout    string
+	// noCache makes buildConnector ignore --cache and the config file's
+	// cacheEnabled; see withoutCache.
+	noCache bool
+}
+
+// withoutCache returns a copy of the flags whose connectors never use the
+// disk cache. Commands reading many secrets in bulk use it, so the values
+// are not written unencrypted to ~/.teamvault-cache and a failed read is
+// reported instead of answered with a stale cached copy.
+func (sf *SharedFlags) withoutCache() *SharedFlags {
+	uncached := *sf
+	uncached.cache = false
+	uncached.noCache = true
+	return &uncached
 }
 
 // NewRootCommand creates the root cobra command with all persistent flags
@@ -160,7 +203,7 @@ func NewRootCommand(ctx context.Context) *cobra.Command {
 	)
 
 	rootCmd.AddCommand(createLoginCommand(ctx, sf))
-	rootCmd.AddCommand(createSecretCommand(
+	rootCmd.AddCommand(addClipFlags(createSecretCommand(
 		ctx,
 		sf,
 		"password",
@@ -170,8 +213,8 @@ func NewRootCommand(ctx context.Context) *cobra.Command {
 		func(ctx context.Context, conn teamvault.Connector, key teamvault.Key) (fmt.Stringer, error) {
 			return conn.Password(ctx, key)
 		},
-	))
-	rootCmd.AddCommand(createSecretCommand(
+	)))
+	rootCmd.AddCommand(addClipFlags(createSecretCommand(
 		ctx,
 		sf,
 		"username",
@@ -181,8 +224,8 @@ func NewRootCommand(ctx context.Context) *cobra.Command {
 		func(ctx context.Context, conn teamvault.Connector, key teamvault.Key) (fmt.Stringer, error) {
 			return conn.User(ctx, key)
 		},
-	))
-	rootCmd.AddCommand(createSecretCommand(
+	)))
+	rootCmd.AddCommand(addClipFlags(createSecretCommand(
 		ctx,
 		sf,
 		"url",
@@ -192,7 +235,7 @@ func NewRootCommand(ctx context.Context) *cobra.Command {
 		func(ctx context.Context, conn teamvault.Connector, key teamvault.Key) (fmt.Stringer, error) {
 			return conn.Url(ctx, key)
 		},
-	))
+	)))
 	rootCmd.AddCommand(createSecretCommand(
 		ctx,
 		sf,
@@ -204,12 +247,35 @@ func NewRootCommand(ctx context.Context) *cobra.Command {
 			return conn.File(ctx, key)
 		},
 	))
-	rootCmd.AddCommand(createInfoCommand(ctx, sf))
+	rootCmd.AddCommand(addClipFlags(createInfoCommand(ctx, sf)))
 	rootCmd.AddCommand(createConfigCommand(ctx, sf))
 	rootCmd.AddCommand(createCreateCommand(ctx, sf))
 	rootCmd.AddCommand(createUpdateCommand(ctx, sf))
+	rootCmd.AddCommand(createGenerateCommand(ctx))
+	rootCmd.AddCommand(createEditCommand(ctx, sf))
+	rootCmd.AddCommand(createRotateCommand(ctx, sf))
+	rootCmd.AddCommand(createRevisionCommand(ctx, sf))
 	rootCmd.AddCommand(createSearchCommand(ctx, sf))
+	rootCmd.AddCommand(createBrowseCommand(ctx, sf))
+	rootCmd.AddCommand(createAliasCommand(ctx, sf))
+	rootCmd.AddCommand(createInventoryCommand(ctx, sf))
+	rootCmd.AddCommand(createImportCommand(ctx, sf))
+	rootCmd.AddCommand(createBackupCommand(ctx, sf))
+	rootCmd.AddCommand(createRestoreCommand(ctx, sf))
+	rootCmd.AddCommand(createMirrorCommand(ctx, sf))
+	rootCmd.AddCommand(createAuditCommand(ctx, sf))
 	rootCmd.AddCommand(createHtpasswdCommand(ctx, sf))
+	rootCmd.AddCommand(createOtpCommand(ctx, sf))
+	rootCmd.AddCommand(createQRCommand(ctx, sf))
+	rootCmd.AddCommand(createDockerCredentialCommand(ctx, sf))
+	rootCmd.AddCommand(createGitCredentialCommand(ctx, sf))
+	rootCmd.AddCommand(createSSHAddCommand(ctx, sf))
+	rootCmd.AddCommand(createAskpassCommand(ctx, sf))
+	rootCmd.AddCommand(createAWSCredentialProcessCommand(ctx, sf))
+	rootCmd.AddCommand(createTerraformExternalCommand(ctx, sf))
+	rootCmd.AddCommand(createClipClearCommand(ctx))
+	registerKeyFlagCompletion(ctx, sf, rootCmd)
+	addNameFlags(rootCmd)
 
 	return rootCmd
 }
@@ -222,21 +288,74 @@ func envBool(name string) bool {
 // resolveKey resolves the TeamVault key from a positional argument or the
 // --teamvault-key flag, positional taking precedence. It returns an error
 // naming both forms when neither is given, since the flag is no longer
-// required and cobra can't enforce "one of" on its own.
+// required and cobra can't enforce "one of" on its own. When --name is given
+// instead it returns an empty key; lookupKey then looks the name up once a
+// connector is available.
 func resolveKey(cmd *cobra.Command, args []string) (teamvault.Key, error) {
+	name, _ := cmd.Flags().GetString("name")
+	flagKey, _ := cmd.Flags().GetString("teamvault-key")
 	if len(args) > 0 && args[0] != "" {
+		if name != "" {
+			return "", errors.New(cmd.Context(), "pass either a key or --name, not both")
+		}
 		return teamvault.Key(args[0]), nil
 	}
-	flagKey, _ := cmd.Flags().GetString("teamvault-key")
 	if flagKey != "" {
+		if name != "" {
+			return "", errors.New(cmd.Context(), "pass either a key or --name, not both")
+		}
 		return teamvault.Key(flagKey), nil
 	}
+	if name != "" {
+		return "", nil
+	}
 	return "", errors.Errorf(
 		cmd.Context(),
 		"teamvault key required: pass it as a positional argument or via --teamvault-key",
 	)
 }
 
+// lookupKey finishes what resolveKey started once a connector is
+// available: an alias is replaced by its key, a key given as a web UI or
+// API URL is reduced to its hashid (the host must match the configured
+// vault), and an empty key means --name was given and is looked up via
+// Search.
+func lookupKey(
+	ctx context.Context,
+	cmd *cobra.Command,
+	sf *SharedFlags,
+	conn teamvault.Connector,
+	key teamvault.Key,
+) (teamvault.Key, error) {
+	if key != "" {
+		keyResolver, err := sf.keyResolver(ctx)
+		if err != nil {
+			return "", err
+		}
+		return keyResolver.ResolveKey(ctx, key.String())
+	}
+	name, _ := cmd.Flags().GetString("name")
+	fuzzy, _ := cmd.Flags().GetBool("fuzzy")
+	key, err := teamvault.NewNameResolver(conn, fuzzy).Resolve(ctx, name)
+	if err != nil {
+		return "", errors.Wrap(ctx, err, "resolve name failed")
+	}
+	return key, nil
+}
+
+// addNameFlags adds --name and --fuzzy to every subcommand reading a
+// secret by key.
+func addNameFlags(rootCmd *cobra.Command) {
+	for _, cmd := range rootCmd.Commands() {
+		if cmd.Flags().Lookup("teamvault-key") == nil {
+			continue
+		}
+		cmd.Flags().String("name", "", "secret name (exact match) instead of a key")
+		cmd.Flags().
+			Bool("fuzzy", false, "with --name, accept the single close match when none is exact")
+	}
+}
+
 // createSecretCommand builds a secret-reader subcommand. The four secret
 // readers (password/username/url/file) differ only in their Use/Short strings,
 // the JSON field name, the connector method invoked, and the error message;
@@ -249,9 +368,10 @@ func createSecretCommand(
 	fetch func(context.Context, teamvault.Connector, teamvault.Key) (fmt.Stringer, error),
 ) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   use + " [key]",
-		Short: short,
-		Args:  cobra.MaximumNArgs(1),
+		Use:               use + " [key]",
+		Short:             short,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeKeyArg(ctx, sf),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			key, err := resolveKey(cmd, args)
 			if err != nil {
@@ -262,10 +382,17 @@ func createSecretCommand(
 			if err != nil {
 				return err
 			}
+			key, err = lookupKey(ctx, cmd, sf, conn, key)
+			if err != nil {
+				return err
+			}
 			result, err := fetch(ctx, conn, key)
 			if err != nil {
 				return errors.Wrap(ctx, err, errMsg)
 			}
+			if clipRequested(cmd) {
+				return copyToClipboard(ctx, cmd, jsonField+" of "+key.String(), result.String())
+			}
 			return writeSecret(ctx, cmd.OutOrStdout(), jsonField, result, asJSON)
 		},
 	}
@@ -313,9 +440,10 @@ func writeSecret(
 // TeamVault secret populates every field.
 func createInfoCommand(ctx context.Context, sf *SharedFlags) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "info [key]",
-		Short: "Retrieve username, url, password, and file for a TeamVault secret",
-		Args:  cobra.MaximumNArgs(1),
+		Use:               "info [key]",
+		Short:             "Retrieve username, url, password, and file for a TeamVault secret",
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeKeyArg(ctx, sf),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			key, err := resolveKey(cmd, args)
 			if err != nil {
@@ -326,6 +454,10 @@ func createInfoCommand(ctx context.Context, sf *SharedFlags) *cobra.Command {
 			if err != nil {
 				return err
 			}
+			key, err = lookupKey(ctx, cmd, sf, conn, key)
+			if err != nil {
+				return err
+			}
 
 			username, err := conn.User(ctx, key)
 			if err != nil {
@@ -343,6 +475,9 @@ func createInfoCommand(ctx context.Context, sf *SharedFlags) *cobra.Command {
 			if err != nil {
 				return errors.Wrap(ctx, err, "get file failed")
 			}
+			if clipRequested(cmd) {
+				return copyToClipboard(ctx, cmd, "password of "+key.String(), password.String())
+			}
 
 			return writeInfo(ctx, cmd.OutOrStdout(), username, url, password, file, asJSON)
 		},
@@ -396,6 +531,53 @@ func writeInfo(
 	return nil
 }
 
+// readConfig parses the --teamvault-config file. It returns (nil, nil) when
+// the file does not exist, so settings that only live in the config file
+// (e.g. helper mappings) are optional.
+func (sf *SharedFlags) readConfig(ctx context.Context) (*teamvault.Config, error) {
+	configPath := teamvault.TeamvaultConfigPath(sf.configPath)
+	if !configPath.Exists() {
+		return nil, nil
+	}
+	config, err := configPath.Parse()
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "parse teamvault config failed")
+	}
+	return config, nil
+}
+
+// vaultUrl returns the TeamVault URL the connector talks to: the config
+// file's url when the file exists (as in the factory), else --teamvault-url.
+// It is empty in staging mode.
+func (sf *SharedFlags) vaultUrl(ctx context.Context) (teamvault.Url, error) {
+	if sf.staging {
+		return "", nil
+	}
+	config, err := sf.readConfig(ctx)
+	if err != nil {
+		return "", err
+	}
+	if config != nil {
+		return config.Url, nil
+	}
+	return teamvault.Url(sf.url), nil
+}
+
+// keyResolver returns the KeyResolver used for keys given on the command
+// line and in templates: aliases first, then bare keys and URLs of the
+// configured vault.
+func (sf *SharedFlags) keyResolver(ctx context.Context) (teamvault.KeyResolver, error) {
+	vaultUrl, err := sf.vaultUrl(ctx)
+	if err != nil {
+		return nil, err
+	}
+	aliases, err := sf.readAliases(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return teamvault.NewAliasKeyResolver(aliases.merged(), teamvault.NewKeyResolver(vaultUrl)), nil
+}
+
 // buildConnector creates a TeamVault connector using the shared flags.
 func (sf *SharedFlags) buildConnector(ctx context.Context) (teamvault.Connector, error) {
 	httpClient, err := factory.CreateHttpClient(ctx)
@@ -412,6 +594,24 @@ func (sf *SharedFlags) buildConnector(ctx context.Context) (teamvault.Connector,
 		timeout = *d
 	}
 
+	if sf.noCache {
+		conn, err := factory.CreateUncachedConnectorWithConfigAndTimeout(
+			ctx,
+			httpClient,
+			teamvault.TeamvaultConfigPath(sf.configPath),
+			teamvault.Url(sf.url),
+			teamvault.User(sf.user),
+			teamvault.Password(sf.pass),
+			teamvault.Staging(sf.staging),
+			libtime.NewCurrentDateTime(),
+			teamvault.NewKeychain(),
+			timeout,
+		)
+		if err != nil {
+			return nil, errors.Wrapf(ctx, err, "create connector failed")
+		}
+		return conn, nil
+	}
 	conn, err := factory.CreateConnectorWithConfigAndTimeout(
 		ctx,
 		httpClient,
@@ -0,0 +1,161 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cli
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/bborbe/errors"
+	libtime "github.com/bborbe/time"
+	"github.com/spf13/cobra"
+)
+
+const defaultClipTimeout = 45 * time.Second
+
+// openClipTerminal opens the terminal OSC 52 sequences are written to. The
+// controlling terminal is used instead of stdout so --clip also works when
+// stdout is redirected. Overridden by tests via SetClipboardForTest.
+var openClipTerminal = func() (io.WriteCloser, error) {
+	return os.OpenFile("/dev/tty", os.O_WRONLY, 0)
+}
+
+// scheduleClipClear starts a detached `clip-clear` process that clears the
+// clipboard after the given delay. Overridden by tests via SetClipboardForTest.
+var scheduleClipClear = spawnClipClear
+
+// SetClipboardForTest redirects OSC 52 output to out and replaces the
+// background clear with schedule. Returns a function to call in AfterEach
+// to reset.
+func SetClipboardForTest(out io.Writer, schedule func(after time.Duration) error) func() {
+	prevOpen, prevSchedule := openClipTerminal, scheduleClipClear
+	openClipTerminal = func() (io.WriteCloser, error) { return nopWriteCloser{out}, nil }
+	scheduleClipClear = schedule
+	return func() {
+		openClipTerminal, scheduleClipClear = prevOpen, prevSchedule
+	}
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+// addClipFlags adds --clip and --clip-timeout to a secret reader.
+func addClipFlags(cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().Bool("clip", false, "copy to the clipboard via OSC 52 instead of printing")
+	cmd.Flags().String(
+		"clip-timeout",
+		envOrDefault("TEAMVAULT_CLIP_TIMEOUT", defaultClipTimeout.String()),
+		"clear the clipboard after this duration (0 = never)",
+	)
+	cmd.MarkFlagsMutuallyExclusive("clip", "json")
+	return cmd
+}
+
+func envOrDefault(name, fallback string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// clipRequested reports whether --clip was given. Commands without the
+// flag always report false.
+func clipRequested(cmd *cobra.Command) bool {
+	clip, _ := cmd.Flags().GetBool("clip")
+	return clip
+}
+
+// copyToClipboard writes value to the clipboard, schedules the clear and
+// prints a single confirmation line to stderr. The value itself never
+// reaches stdout or the scrollback.
+func copyToClipboard(ctx context.Context, cmd *cobra.Command, what, value string) error {
+	timeout, err := clipTimeout(ctx, cmd)
+	if err != nil {
+		return err
+	}
+
+	tty, err := openClipTerminal()
+	if err != nil {
+		return errors.Wrapf(ctx, err, "--clip needs a terminal")
+	}
+	defer tty.Close()
+	if _, err := io.WriteString(tty, osc52Sequence(value, inTmux())); err != nil {
+		return errors.Wrapf(ctx, err, "write clipboard sequence failed")
+	}
+
+	if timeout == 0 {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Copied %s to clipboard.\n", what)
+		return nil
+	}
+	if err := scheduleClipClear(timeout); err != nil {
+		return errors.Wrapf(ctx, err, "schedule clipboard clear failed")
+	}
+	fmt.Fprintf(cmd.ErrOrStderr(), "Copied %s to clipboard, clearing in %v.\n", what, timeout)
+	return nil
+}
+
+// clipTimeout parses --clip-timeout.
+func clipTimeout(ctx context.Context, cmd *cobra.Command) (time.Duration, error) {
+	rawTimeout, _ := cmd.Flags().GetString("clip-timeout")
+	timeout, err := libtime.ParseDuration(ctx, rawTimeout)
+	if err != nil {
+		return 0, errors.Wrapf(ctx, err, "parse clip-timeout %q failed", rawTimeout)
+	}
+	if timeout.Duration() < 0 {
+		return 0, errors.Errorf(ctx, "invalid clip-timeout %v: must be >= 0", timeout.Duration())
+	}
+	return timeout.Duration(), nil
+}
+
+// osc52Sequence returns the OSC 52 "set clipboard" sequence. Inside tmux it
+// is wrapped in a DCS passthrough so it reaches the outer terminal. An
+// empty value clears the clipboard.
+func osc52Sequence(value string, tmux bool) string {
+	seq := "\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte(value)) + "\x07"
+	if !tmux {
+		return seq
+	}
+	return "\x1bPtmux;" + strings.ReplaceAll(seq, "\x1b", "\x1b\x1b") + "\x1b\\"
+}
+
+func inTmux() bool {
+	return os.Getenv("TMUX") != ""
+}
+
+// createClipClearCommand builds the hidden `clip-clear` subcommand started
+// in the background by --clip. It waits, then writes the clearing OSC 52
+// sequence to stdout, which the parent wires to the terminal.
+func createClipClearCommand(ctx context.Context) *cobra.Command {
+	var after time.Duration
+
+	cmd := &cobra.Command{
+		Use:    "clip-clear",
+		Short:  "Clear the clipboard after a delay (used by --clip)",
+		Hidden: true,
+		Args:   cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(after):
+			}
+			if _, err := io.WriteString(cmd.OutOrStdout(), osc52Sequence("", inTmux())); err != nil {
+				return errors.Wrapf(ctx, err, "write clipboard sequence failed")
+			}
+			return nil
+		},
+	}
+	cmd.Flags().DurationVar(&after, "after", defaultClipTimeout, "delay before clearing")
+
+	return cmd
+}
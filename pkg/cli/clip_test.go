@@ -0,0 +1,117 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	teamvault "github.com/Seibert-Data/teamvault-cli/v5/pkg"
+	"github.com/Seibert-Data/teamvault-cli/v5/pkg/cli"
+)
+
+var _ = Describe("--clip", func() {
+	var ctx context.Context
+	var ttyBuf bytes.Buffer
+	var outBuf bytes.Buffer
+	var errBuf bytes.Buffer
+	var scheduled []time.Duration
+	var resetClipboard func()
+
+	execute := func(args ...string) error {
+		ttyBuf.Reset()
+		outBuf.Reset()
+		errBuf.Reset()
+		cmd := cli.NewRootCommand(ctx)
+		cmd.SetArgs(args)
+		cmd.SetOut(&outBuf)
+		cmd.SetErr(&errBuf)
+		return cmd.Execute()
+	}
+
+	osc52 := func(value string) string {
+		return "\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte(value)) + "\x07"
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		os.Setenv("STAGING", "true")
+		os.Unsetenv("TMUX")
+		os.Unsetenv("TEAMVAULT_CLIP_TIMEOUT")
+		scheduled = nil
+		resetClipboard = cli.SetClipboardForTest(&ttyBuf, func(after time.Duration) error {
+			scheduled = append(scheduled, after)
+			return nil
+		})
+	})
+
+	AfterEach(func() {
+		resetClipboard()
+		os.Unsetenv("STAGING")
+		os.Unsetenv("TMUX")
+	})
+
+	It("copies the value instead of printing it and schedules the clear", func() {
+		Expect(execute("username", "clipkey", "--clip")).To(Succeed())
+
+		Expect(ttyBuf.String()).To(Equal(osc52("clipkey")))
+		Expect(outBuf.String()).To(BeEmpty())
+		Expect(errBuf.String()).
+			To(Equal("Copied username of clipkey to clipboard, clearing in 45s.\n"))
+		Expect(scheduled).To(Equal([]time.Duration{45 * time.Second}))
+	})
+
+	It("wraps the sequence for tmux", func() {
+		os.Setenv("TMUX", "/tmp/tmux-1000/default,1,0")
+
+		Expect(execute("username", "clipkey", "--clip")).To(Succeed())
+
+		Expect(ttyBuf.String()).To(Equal(
+			"\x1bPtmux;\x1b\x1b]52;c;" + base64.StdEncoding.EncodeToString(
+				[]byte("clipkey"),
+			) + "\x07\x1b\\",
+		))
+	})
+
+	It("honors --clip-timeout and never clears with 0", func() {
+		Expect(execute("url", "clipkey", "--clip", "--clip-timeout", "2m")).To(Succeed())
+		Expect(scheduled).To(Equal([]time.Duration{2 * time.Minute}))
+
+		Expect(execute("url", "clipkey", "--clip", "--clip-timeout", "0")).To(Succeed())
+		Expect(scheduled).To(HaveLen(1))
+		Expect(errBuf.String()).To(Equal("Copied url of clipkey to clipboard.\n"))
+	})
+
+	It("copies the password from info", func() {
+		password, err := teamvault.NewDummyConnector().Password(ctx, "clipkey")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(execute("info", "clipkey", "--clip")).To(Succeed())
+
+		Expect(ttyBuf.String()).To(Equal(osc52(password.String())))
+		Expect(outBuf.String()).To(BeEmpty())
+	})
+
+	It("rejects --clip together with --json", func() {
+		Expect(execute("password", "clipkey", "--clip", "--json")).To(HaveOccurred())
+		Expect(ttyBuf.String()).To(BeEmpty())
+	})
+
+	It("is not offered for file", func() {
+		Expect(execute("file", "clipkey", "--clip")).To(HaveOccurred())
+	})
+
+	It("clip-clear writes the clearing sequence", func() {
+		Expect(execute("clip-clear", "--after", "0s")).To(Succeed())
+
+		Expect(outBuf.String()).To(Equal("\x1b]52;c;\x07"))
+	})
+})
@@ -0,0 +1,37 @@
+//go:build !windows
+
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cli
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+	"time"
+)
+
+// spawnClipClear re-executes the binary as `clip-clear` in its own session,
+// with the terminal as stdout, so it outlives this process and is not hit by
+// the shell's job control signals.
+func spawnClipClear(after time.Duration) error {
+	tty, err := os.OpenFile("/dev/tty", os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	defer tty.Close()
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	// #nosec G204 -- re-executes this binary with fixed arguments
+	cmd := exec.Command(executable, "clip-clear", "--after", after.String())
+	cmd.Stdout = tty
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	return cmd.Process.Release()
+}
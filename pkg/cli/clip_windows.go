@@ -0,0 +1,18 @@
+//go:build windows
+
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cli
+
+import (
+	stderrors "errors"
+	"time"
+)
+
+// spawnClipClear is not supported on Windows, which has no /dev/tty; use
+// --clip-timeout 0 there.
+func spawnClipClear(after time.Duration) error {
+	return stderrors.New("clearing the clipboard is not supported on windows")
+}
@@ -0,0 +1,160 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cli
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+
+	teamvault "github.com/Seibert-Data/teamvault-cli/v5/pkg"
+)
+
+// completionTimeout bounds the TeamVault lookup behind <TAB>, so an
+// unreachable server degrades to cached keys instead of a hanging shell.
+var completionTimeout = 2 * time.Second
+
+// SetCompletionTimeoutForTest overrides the completion lookup timeout.
+// Returns a function to call in AfterEach to reset.
+func SetCompletionTimeoutForTest(timeout time.Duration) func() {
+	prev := completionTimeout
+	completionTimeout = timeout
+	return func() { completionTimeout = prev }
+}
+
+// completeKeyArg is the ValidArgsFunction for commands taking a single key.
+func completeKeyArg(ctx context.Context, sf *SharedFlags) cobra.CompletionFunc {
+	complete := completeKeys(ctx, sf)
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return complete(cmd, args, toComplete)
+	}
+}
+
+// completeKeyArgs is the ValidArgsFunction for commands taking several
+// keys. Every position is completed; keys already given are left out.
+func completeKeyArgs(ctx context.Context, sf *SharedFlags) cobra.CompletionFunc {
+	complete := completeKeys(ctx, sf)
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		completions, directive := complete(cmd, args, toComplete)
+		given := make(map[string]bool, len(args))
+		for _, arg := range args {
+			given[arg] = true
+		}
+		remaining := completions[:0]
+		for _, completion := range completions {
+			value, _, _ := strings.Cut(completion, "\t")
+			if !given[value] {
+				remaining = append(remaining, completion)
+			}
+		}
+		return remaining, directive
+	}
+}
+
+// registerKeyFlagCompletion completes --teamvault-key on every subcommand
+// that offers key completion for its positional argument.
+func registerKeyFlagCompletion(ctx context.Context, sf *SharedFlags, rootCmd *cobra.Command) {
+	for _, cmd := range rootCmd.Commands() {
+		if cmd.ValidArgsFunction == nil || cmd.Flags().Lookup("teamvault-key") == nil {
+			continue
+		}
+		if err := cmd.RegisterFlagCompletionFunc("teamvault-key", completeKeys(ctx, sf)); err != nil {
+			glog.V(2).Infof("register teamvault-key completion for %s failed: %v", cmd.Name(), err)
+		}
+	}
+}
+
+// completeKeys offers aliases, `key<TAB>name` pairs from Connector.Search
+// on the typed text, plus keys from the local disk cache. Search hits
+// matched by name only are left out: the shell drops candidates that do not
+// start with the typed text. When the search fails or times out only
+// aliases and cached keys are offered.
+func completeKeys(ctx context.Context, sf *SharedFlags) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		var completions []cobra.Completion
+		files, err := sf.readAliases(ctx)
+		if err != nil {
+			glog.V(2).Infof("read aliases failed: %v", err)
+		} else {
+			aliases := files.merged()
+			for _, name := range sortedAliasNames(aliases) {
+				if strings.HasPrefix(name, toComplete) {
+					completions = append(
+						completions,
+						cobra.CompletionWithDesc(name, "alias for "+aliases[name].String()),
+					)
+				}
+			}
+		}
+		seen := make(map[teamvault.Key]bool)
+		if toComplete != "" {
+			for _, result := range searchForCompletion(ctx, sf, toComplete) {
+				if seen[result.Key] || !strings.HasPrefix(result.Key.String(), toComplete) {
+					continue
+				}
+				seen[result.Key] = true
+				completions = append(
+					completions,
+					cobra.CompletionWithDesc(result.Key.String(), result.Name),
+				)
+			}
+		}
+		cached, err := teamvault.ListCachedKeys(ctx)
+		if err != nil {
+			glog.V(2).Infof("list cached keys failed: %v", err)
+		}
+		for _, key := range cached {
+			if seen[key] || !strings.HasPrefix(key.String(), toComplete) {
+				continue
+			}
+			seen[key] = true
+			completions = append(completions, cobra.Completion(key.String()))
+		}
+		return completions, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+func searchForCompletion(
+	ctx context.Context,
+	sf *SharedFlags,
+	query string,
+) []teamvault.SearchResult {
+	ctx, cancel := context.WithTimeout(ctx, completionTimeout)
+	defer cancel()
+
+	type outcome struct {
+		results []teamvault.SearchResult
+		err     error
+	}
+	// The connector may block outside of ctx (e.g. keychain access), so the
+	// deadline is enforced here as well.
+	done := make(chan outcome, 1)
+	go func() {
+		conn, err := newConnector(sf)(ctx)
+		if err != nil {
+			done <- outcome{err: err}
+			return
+		}
+		results, err := conn.Search(ctx, query)
+		done <- outcome{results: results, err: err}
+	}()
+	select {
+	case <-ctx.Done():
+		glog.V(2).Infof("search %q for completion timed out", query)
+		return nil
+	case o := <-done:
+		if o.err != nil {
+			glog.V(2).Infof("search %q for completion failed: %v", query, o.err)
+			return nil
+		}
+		return o.results
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	stderrors "errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	teamvault "github.com/Seibert-Data/teamvault-cli/v5/pkg"
+	"github.com/Seibert-Data/teamvault-cli/v5/pkg/cli"
+	"github.com/Seibert-Data/teamvault-cli/v5/pkg/mocks"
+)
+
+var _ = Describe("key completion", func() {
+	var ctx context.Context
+	var fakeConn *mocks.Connector
+	var outBuf bytes.Buffer
+	var resets []func()
+
+	complete := func(args ...string) string {
+		outBuf.Reset()
+		cmd := cli.NewRootCommand(ctx)
+		cmd.SetArgs(append([]string{"__complete"}, args...))
+		cmd.SetOut(&outBuf)
+		cmd.SetErr(&bytes.Buffer{})
+		Expect(cmd.Execute()).To(Succeed())
+		return outBuf.String()
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		home := GinkgoT().TempDir()
+		prevHome := os.Getenv("HOME")
+		os.Setenv("HOME", home)
+		DeferCleanup(func() { os.Setenv("HOME", prevHome) })
+		Expect(os.MkdirAll(filepath.Join(home, ".teamvault-cache", "AbCach"), 0700)).To(Succeed())
+		Expect(os.MkdirAll(filepath.Join(home, ".teamvault-cache", "XyCach"), 0700)).To(Succeed())
+
+		fakeConn = &mocks.Connector{}
+		fakeConn.SearchReturns([]teamvault.SearchResult{
+			{Key: "AbC123", Name: "prod-database"},
+			{Key: "AbD456", Name: "staging-database"},
+		}, nil)
+		resets = []func(){
+			cli.SetNewConnectorForTest(
+				func(sf *cli.SharedFlags) func(context.Context) (teamvault.Connector, error) {
+					return func(ctx context.Context) (teamvault.Connector, error) {
+						return fakeConn, nil
+					}
+				},
+			),
+			cli.SetCompletionTimeoutForTest(100 * time.Millisecond),
+		}
+	})
+
+	AfterEach(func() {
+		for _, reset := range resets {
+			reset()
+		}
+	})
+
+	It("completes keys from search with the name as description", func() {
+		out := complete("password", "Ab")
+
+		Expect(out).To(Equal("AbC123\tprod-database\nAbD456\tstaging-database\nAbCach\n:4\n"))
+		_, query := fakeConn.SearchArgsForCall(0)
+		Expect(query).To(Equal("Ab"))
+	})
+
+	It("leaves out search hits whose key does not start with the typed text", func() {
+		fakeConn.SearchReturns([]teamvault.SearchResult{
+			{Key: "AbC123", Name: "prod-database"},
+			{Key: "prodXy", Name: "prod-cache"},
+		}, nil)
+
+		Expect(complete("password", "prod")).To(Equal("prodXy\tprod-cache\n:4\n"))
+	})
+
+	It("completes --teamvault-key", func() {
+		out := complete("info", "--teamvault-key", "Ab")
+
+		Expect(out).To(ContainSubstring("AbC123\tprod-database\n"))
+	})
+
+	It("falls back to cached keys when search fails", func() {
+		fakeConn.SearchReturns(nil, stderrors.New("offline"))
+
+		Expect(complete("otp", "Xy")).To(Equal("XyCach\n:4\n"))
+	})
+
+	It("gives up on a hanging search after the timeout", func() {
+		fakeConn.SearchStub = func(ctx context.Context, query string) ([]teamvault.SearchResult, error) {
+			time.Sleep(time.Second)
+			return nil, nil
+		}
+
+		start := time.Now()
+		Expect(complete("username", "Ab")).To(Equal("AbCach\n:4\n"))
+		Expect(time.Since(start)).To(BeNumerically("<", 500*time.Millisecond))
+	})
+
+	It("only offers cached keys for an empty prefix", func() {
+		Expect(complete("htpasswd", "")).To(Equal("AbCach\nXyCach\n:4\n"))
+		Expect(fakeConn.SearchCallCount()).To(Equal(0))
+	})
+
+	It("completes nothing after the key", func() {
+		Expect(complete("password", "AbC123", "")).To(Equal(":4\n"))
+	})
+
+	It("completes every key of mirror, leaving out keys already given", func() {
+		out := complete("mirror", "AbC123", "Ab")
+
+		Expect(out).To(Equal("AbD456\tstaging-database\nAbCach\n:4\n"))
+	})
+})
@@ -40,7 +40,11 @@ func createConfigParseCommand(ctx context.Context, sf *SharedFlags) *cobra.Comma
 			if err != nil {
 				return errors.Wrapf(ctx, err, "read stdin failed")
 			}
-			output, err := teamvault.NewConfigParser(conn).Parse(ctx, content)
+			parser, err := sf.newConfigParser(ctx, conn)
+			if err != nil {
+				return err
+			}
+			output, err := parser.Parse(ctx, content)
 			if err != nil {
 				return errors.Wrapf(ctx, err, "parse config failed")
 			}
@@ -67,7 +71,11 @@ func createConfigGenerateCommand(ctx context.Context, sf *SharedFlags) *cobra.Co
 			if err != nil {
 				return err
 			}
-			gen := teamvault.NewConfigGenerator(teamvault.NewConfigParser(conn))
+			parser, err := sf.newConfigParser(ctx, conn)
+			if err != nil {
+				return err
+			}
+			gen := teamvault.NewConfigGenerator(parser)
 			if err := gen.Generate(ctx, teamvault.SourceDirectory(src), teamvault.TargetDirectory(dst)); err != nil {
 				return errors.Wrapf(ctx, err, "generate failed")
 			}
@@ -82,3 +90,17 @@ func createConfigGenerateCommand(ctx context.Context, sf *SharedFlags) *cobra.Co
 
 	return cmd
 }
+
+// newConfigParser creates a ConfigParser whose template functions resolve
+// keys like the commands do: aliases, and secret URLs of the configured
+// vault only.
+func (sf *SharedFlags) newConfigParser(
+	ctx context.Context,
+	conn teamvault.Connector,
+) (teamvault.ConfigParser, error) {
+	keyResolver, err := sf.keyResolver(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return teamvault.NewConfigParserWithKeyResolver(conn, keyResolver), nil
+}
@@ -23,30 +23,41 @@ func createCreateCommand(ctx context.Context, sf *SharedFlags) *cobra.Command {
 		description   string
 		passwordStdin bool
 		generate      bool
+		generateLocal bool
 		password      string
 		filePath      string
 		asJSON        bool
 	)
 
+	var gf *generatorFlags
 	cmd := &cobra.Command{
 		Use:   "create",
 		Short: "Create a new secret in TeamVault",
 		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// --- Value-source resolution (before any network call) ---
-			valueFlags := countValueFlags(passwordStdin, generate, password != "", filePath != "")
+			valueFlags := countValueFlags(
+				passwordStdin,
+				generate,
+				generateLocal,
+				password != "",
+				filePath != "",
+			)
 			if valueFlags == 0 {
 				return errors.New(
 					ctx,
-					"one of --password-stdin, --generate, --password, or --file is required",
+					"one of --password-stdin, --generate, --generate-local, --password, or --file is required",
 				)
 			}
 			if valueFlags > 1 {
 				return errors.New(
 					ctx,
-					"--password-stdin, --generate, --password, and --file are mutually exclusive",
+					"--password-stdin, --generate, --generate-local, --password, and --file are mutually exclusive",
 				)
 			}
+			if !generateLocal && gf.changed(cmd) {
+				return errors.New(ctx, "password policy flags require --generate-local")
+			}
 
 			// Infer content type
 			var contentType teamvault.ContentType
@@ -96,6 +107,13 @@ func createCreateCommand(ctx context.Context, sf *SharedFlags) *cobra.Command {
 				}
 				secret.Password = pw
 
+			case generateLocal:
+				pw, err := gf.generate(ctx, cmd)
+				if err != nil {
+					return errors.Wrap(ctx, err, "generate password failed")
+				}
+				secret.Password = pw
+
 			case password != "":
 				secret.Password = teamvault.Password(password)
 			}
@@ -120,6 +138,13 @@ func createCreateCommand(ctx context.Context, sf *SharedFlags) *cobra.Command {
 	cmd.Flags().StringVar(&description, "description", "", "description")
 	cmd.Flags().BoolVar(&passwordStdin, "password-stdin", false, "read password from stdin")
 	cmd.Flags().BoolVar(&generate, "generate", false, "generate a secure password via the server")
+	cmd.Flags().BoolVar(
+		&generateLocal,
+		"generate-local",
+		false,
+		"generate a password locally with the policy flags (see the generate command)",
+	)
+	gf = addGeneratorFlags(cmd)
 	cmd.Flags().StringVar(
 		&password,
 		"password",
@@ -132,8 +157,8 @@ func createCreateCommand(ctx context.Context, sf *SharedFlags) *cobra.Command {
 	return cmd
 }
 
-// countValueFlags returns how many of the four value-source flags are set.
-func countValueFlags(passwordStdin, generate, hasPassword, hasFile bool) int {
+// countValueFlags returns how many of the five value-source flags are set.
+func countValueFlags(passwordStdin, generate, generateLocal, hasPassword, hasFile bool) int {
 	n := 0
 	if passwordStdin {
 		n++
@@ -141,6 +166,9 @@ func countValueFlags(passwordStdin, generate, hasPassword, hasFile bool) int {
 	if generate {
 		n++
 	}
+	if generateLocal {
+		n++
+	}
 	if hasPassword {
 		n++
 	}
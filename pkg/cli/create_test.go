@@ -83,6 +83,16 @@ var _ = Describe("create", func() {
 			Expect(err).NotTo(BeNil())
 			Expect(err.Error()).To(ContainSubstring("mutually exclusive"))
 		})
+
+		It("counts --generate-local as a value source", func() {
+			cmd := cli.NewRootCommand(ctx)
+			cmd.SetArgs([]string{"create", "--name", "x", "--generate", "--generate-local"})
+			cmd.SetOut(&bytes.Buffer{})
+			cmd.SetErr(&bytes.Buffer{})
+			err := cmd.Execute()
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(ContainSubstring("mutually exclusive"))
+		})
 	})
 
 	Describe("content-type inference", func() {
@@ -0,0 +1,331 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/bborbe/errors"
+	"github.com/spf13/cobra"
+
+	teamvault "github.com/Seibert-Data/teamvault-cli/v5/pkg"
+)
+
+// errDockerCredentialsNotFound carries the exact message the Docker client
+// matches on to treat a lookup miss as "no credentials" rather than a failure.
+var errDockerCredentialsNotFound = stderrors.New("credentials not found in native keychain")
+
+// dockerCredentials is the JSON document exchanged with Docker on get/store.
+type dockerCredentials struct {
+	ServerURL string `json:"ServerURL"`
+	Username  string `json:"Username"`
+	Secret    string `json:"Secret"`
+}
+
+// createDockerCredentialCommand builds the `docker-credential` subcommand,
+// which speaks the docker-credential-* helper protocol: the verb is the only
+// argument, the payload arrives on stdin and the answer (or error message)
+// leaves on stdout. Docker finds helpers by binary name, so the intended
+// setup is a docker-credential-teamvault symlink to teamvault-cli plus
+// `"credsStore": "teamvault"` in ~/.docker/config.json.
+func createDockerCredentialCommand(ctx context.Context, sf *SharedFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "docker-credential <get|list|store|erase>",
+		Short: "Docker credential helper backed by TeamVault",
+		Long: `Docker credential helper backed by TeamVault.
+
+Registries resolve to TeamVault keys through the "dockerRegistries" map in the
+config file, or else through the one secret found by searching for the registry
+host whose url field points at that host.
+
+Symlink the binary as docker-credential-teamvault and set
+"credsStore": "teamvault" (or a "credHelpers" entry) in ~/.docker/config.json.
+
+store updates the mapped secret, or creates one named after the registry host.
+erase never deletes anything: TeamVault secrets are shared, so docker logout
+leaves them untouched.`,
+		Args:      cobra.ExactArgs(1),
+		ValidArgs: []string{"get", "list", "store", "erase"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := runDockerCredential(
+				ctx,
+				sf,
+				args[0],
+				cmd.InOrStdin(),
+				cmd.OutOrStdout(),
+				cmd.ErrOrStderr(),
+			)
+			if err == nil {
+				return nil
+			}
+			// The protocol reports errors on stdout; Docker only recognises a
+			// miss by the bare not-found message, so never wrap that one.
+			if errors.Is(err, errDockerCredentialsNotFound) {
+				fmt.Fprintln(cmd.OutOrStdout(), errDockerCredentialsNotFound.Error())
+			} else {
+				fmt.Fprintln(cmd.OutOrStdout(), err.Error())
+			}
+			return err
+		},
+	}
+	return cmd
+}
+
+// runDockerCredential dispatches one helper protocol verb.
+func runDockerCredential(
+	ctx context.Context,
+	sf *SharedFlags,
+	action string,
+	in io.Reader,
+	out io.Writer,
+	errOut io.Writer,
+) error {
+	switch action {
+	case "get":
+		return dockerCredentialGet(ctx, sf, in, out)
+	case "list":
+		return dockerCredentialList(ctx, sf, out)
+	case "store":
+		return dockerCredentialStore(ctx, sf, in)
+	case "erase":
+		return dockerCredentialErase(ctx, in, errOut)
+	default:
+		return errors.Errorf(
+			ctx,
+			"unknown docker-credential action %q (want get, list, store or erase)",
+			action,
+		)
+	}
+}
+
+func dockerCredentialGet(ctx context.Context, sf *SharedFlags, in io.Reader, out io.Writer) error {
+	data, err := io.ReadAll(in)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "read server url from stdin failed")
+	}
+	serverURL := strings.TrimSpace(string(data))
+	if serverURL == "" {
+		return errors.New(ctx, "server url required on stdin")
+	}
+	conn, err := newConnector(sf)(ctx)
+	if err != nil {
+		return errors.Wrap(ctx, err, "create connector failed")
+	}
+	registries, err := sf.dockerRegistries(ctx)
+	if err != nil {
+		return err
+	}
+	keyResolver, err := sf.keyResolver(ctx)
+	if err != nil {
+		return err
+	}
+	key, found, err := lookupDockerRegistry(
+		ctx,
+		conn,
+		keyResolver,
+		registries,
+		registryHost(serverURL),
+	)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return errDockerCredentialsNotFound
+	}
+	user, err := conn.User(ctx, key)
+	if err != nil {
+		return errors.Wrap(ctx, err, "get user failed")
+	}
+	pass, err := conn.Password(ctx, key)
+	if err != nil {
+		return errors.Wrap(ctx, err, "get password failed")
+	}
+	return writeDockerJSON(ctx, out, dockerCredentials{
+		ServerURL: serverURL,
+		Username:  user.String(),
+		Secret:    pass.String(),
+	})
+}
+
+// dockerCredentialList reports the configured registries only: secrets found
+// through the search fallback cannot be enumerated without listing the vault.
+func dockerCredentialList(ctx context.Context, sf *SharedFlags, out io.Writer) error {
+	registries, err := sf.dockerRegistries(ctx)
+	if err != nil {
+		return err
+	}
+	result := make(map[string]string, len(registries))
+	if len(registries) > 0 {
+		conn, err := newConnector(sf)(ctx)
+		if err != nil {
+			return errors.Wrap(ctx, err, "create connector failed")
+		}
+		keyResolver, err := sf.keyResolver(ctx)
+		if err != nil {
+			return err
+		}
+		for serverURL, value := range registries {
+			key, err := keyResolver.ResolveKey(ctx, value.String())
+			if err != nil {
+				return errors.Wrapf(ctx, err, "invalid key for registry %s", serverURL)
+			}
+			user, err := conn.User(ctx, key)
+			if err != nil {
+				return errors.Wrapf(ctx, err, "get user for registry %s failed", serverURL)
+			}
+			result[serverURL] = user.String()
+		}
+	}
+	return writeDockerJSON(ctx, out, result)
+}
+
+func dockerCredentialStore(ctx context.Context, sf *SharedFlags, in io.Reader) error {
+	var creds dockerCredentials
+	if err := json.NewDecoder(in).Decode(&creds); err != nil {
+		return errors.Wrapf(ctx, err, "decode credentials from stdin failed")
+	}
+	if creds.ServerURL == "" {
+		return errors.New(ctx, "ServerURL required")
+	}
+	host := registryHost(creds.ServerURL)
+	conn, err := newConnector(sf)(ctx)
+	if err != nil {
+		return errors.Wrap(ctx, err, "create connector failed")
+	}
+	registries, err := sf.dockerRegistries(ctx)
+	if err != nil {
+		return err
+	}
+	keyResolver, err := sf.keyResolver(ctx)
+	if err != nil {
+		return err
+	}
+	key, found, err := lookupDockerRegistry(ctx, conn, keyResolver, registries, host)
+	if err != nil {
+		return err
+	}
+	writer, err := newWriter(sf)(ctx)
+	if err != nil {
+		return errors.Wrap(ctx, err, "create writer failed")
+	}
+	pass := teamvault.Password(creds.Secret)
+	if found {
+		if _, _, err := writer.Update(ctx, key, teamvault.UpdateSecret{
+			Username: &creds.Username,
+			Password: &pass,
+		}); err != nil {
+			return errors.Wrapf(ctx, err, "update secret %s failed", key)
+		}
+		return nil
+	}
+	if _, _, err := writer.Create(ctx, teamvault.CreateSecret{
+		ContentType: teamvault.ContentTypePassword,
+		Name:        host,
+		Username:    creds.Username,
+		Url:         creds.ServerURL,
+		Password:    pass,
+	}); err != nil {
+		return errors.Wrap(ctx, err, "create secret failed")
+	}
+	return nil
+}
+
+func dockerCredentialErase(ctx context.Context, in io.Reader, errOut io.Writer) error {
+	data, err := io.ReadAll(in)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "read server url from stdin failed")
+	}
+	fmt.Fprintf(
+		errOut,
+		"teamvault-cli: not erasing credentials for %s; TeamVault secrets are left untouched\n",
+		strings.TrimSpace(string(data)),
+	)
+	return nil
+}
+
+// dockerRegistries returns the dockerRegistries map from the config file, or
+// nil when there is no config.
+func (sf *SharedFlags) dockerRegistries(ctx context.Context) (map[string]teamvault.Key, error) {
+	config, err := sf.readConfig(ctx)
+	if err != nil || config == nil {
+		return nil, err
+	}
+	return config.DockerRegistries, nil
+}
+
+// lookupDockerRegistry resolves the TeamVault key for a registry host. An
+// explicit dockerRegistries entry wins, the first in sort order when several
+// name the host, and may name an alias or secret URL; otherwise exactly one
+// secret whose url points at the host must exist. found is false when
+// nothing matched.
+func lookupDockerRegistry(
+	ctx context.Context,
+	conn teamvault.Connector,
+	keyResolver teamvault.KeyResolver,
+	registries map[string]teamvault.Key,
+	host string,
+) (teamvault.Key, bool, error) {
+	serverURLs := make([]string, 0, len(registries))
+	for serverURL := range registries {
+		serverURLs = append(serverURLs, serverURL)
+	}
+	sort.Strings(serverURLs)
+	for _, serverURL := range serverURLs {
+		if registryHost(serverURL) == host {
+			key, err := keyResolver.ResolveKey(ctx, registries[serverURL].String())
+			if err != nil {
+				return "", false, errors.Wrapf(ctx, err, "invalid key for registry %s", serverURL)
+			}
+			return key, true, nil
+		}
+	}
+	matches, err := searchByURL(ctx, conn, host, func(u *url.URL) bool {
+		return u.Host == host
+	})
+	if err != nil {
+		return "", false, err
+	}
+	switch len(matches) {
+	case 0:
+		return "", false, nil
+	case 1:
+		return matches[0].Key, true, nil
+	default:
+		return "", false, errors.Errorf(
+			ctx,
+			"multiple TeamVault secrets match registry %s (%s); add a dockerRegistries entry to pick one",
+			host,
+			joinKeys(matches),
+		)
+	}
+}
+
+// registryHost normalises the server URL Docker passes ("https://index.docker.io/v1/",
+// "registry.example.com:5000", …) down to its lower-cased host[:port].
+func registryHost(serverURL string) string {
+	u, err := parseLooseURL(serverURL)
+	if err != nil || u.Host == "" {
+		return strings.ToLower(strings.TrimSpace(serverURL))
+	}
+	return u.Host
+}
+
+func writeDockerJSON(ctx context.Context, out io.Writer, v any) error {
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "marshal json failed")
+	}
+	if _, err := fmt.Fprintf(out, "%s\n", encoded); err != nil {
+		return errors.Wrapf(ctx, err, "write credentials failed")
+	}
+	return nil
+}
@@ -0,0 +1,222 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	teamvault "github.com/Seibert-Data/teamvault-cli/v5/pkg"
+	"github.com/Seibert-Data/teamvault-cli/v5/pkg/cli"
+	"github.com/Seibert-Data/teamvault-cli/v5/pkg/mocks"
+)
+
+var _ = Describe("docker-credential", func() {
+	var ctx context.Context
+	var fakeConn *mocks.Connector
+	var mockWriter *mocks.Writer
+	var configPath string
+	var outBuf bytes.Buffer
+	var resetConnector, resetWriter func()
+
+	execute := func(stdin string, args ...string) error {
+		outBuf.Reset()
+		cmd := cli.NewRootCommand(ctx)
+		cmd.SetArgs(
+			append([]string{"--teamvault-config", configPath, "docker-credential"}, args...),
+		)
+		cmd.SetIn(strings.NewReader(stdin))
+		cmd.SetOut(&outBuf)
+		cmd.SetErr(&bytes.Buffer{})
+		return cmd.Execute()
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		os.Setenv("STAGING", "true")
+		os.Unsetenv("TEAMVAULT_URL")
+		os.Unsetenv("TEAMVAULT_USER")
+		os.Unsetenv("TEAMVAULT_PASS")
+		os.Unsetenv("TEAMVAULT_CONFIG")
+		os.Unsetenv("TEAMVAULT_TIMEOUT")
+
+		configPath = filepath.Join(GinkgoT().TempDir(), "config.json")
+		Expect(os.WriteFile(configPath, []byte(`{
+			"url": "https://vault.example.com",
+			"user": "alice",
+			"pass": "pw",
+			"dockerRegistries": {"registry.example.com": "REG123"}
+		}`), 0600)).To(Succeed())
+
+		fakeConn = &mocks.Connector{}
+		fakeConn.UserReturns(teamvault.User("robot"), nil)
+		fakeConn.PasswordReturns(teamvault.Password("t0ken"), nil)
+		mockWriter = &mocks.Writer{}
+
+		resetConnector = cli.SetNewConnectorForTest(
+			func(sf *cli.SharedFlags) func(context.Context) (teamvault.Connector, error) {
+				return func(ctx context.Context) (teamvault.Connector, error) {
+					return fakeConn, nil
+				}
+			},
+		)
+		resetWriter = cli.SetNewWriterForTest(
+			func(sf *cli.SharedFlags) func(context.Context) (teamvault.Writer, error) {
+				return func(ctx context.Context) (teamvault.Writer, error) {
+					return mockWriter, nil
+				}
+			},
+		)
+	})
+
+	AfterEach(func() {
+		resetConnector()
+		resetWriter()
+		os.Unsetenv("STAGING")
+	})
+
+	Describe("get", func() {
+		It("returns credentials for a configured registry", func() {
+			Expect(execute("https://registry.example.com/v2/\n", "get")).To(Succeed())
+
+			var creds map[string]string
+			Expect(json.Unmarshal(outBuf.Bytes(), &creds)).To(Succeed())
+			Expect(creds).To(Equal(map[string]string{
+				"ServerURL": "https://registry.example.com/v2/",
+				"Username":  "robot",
+				"Secret":    "t0ken",
+			}))
+			_, key := fakeConn.PasswordArgsForCall(0)
+			Expect(key).To(Equal(teamvault.Key("REG123")))
+			Expect(fakeConn.SearchCallCount()).To(Equal(0))
+		})
+
+		It("picks the first configured entry in sort order for a host", func() {
+			Expect(os.WriteFile(configPath, []byte(`{
+				"url": "https://vault.example.com",
+				"dockerRegistries": {
+					"registry.example.com": "REG123",
+					"https://registry.example.com/v2/": "REG456",
+					"https://registry.example.com": "REG789"
+				}
+			}`), 0600)).To(Succeed())
+
+			for i := 0; i < 20; i++ {
+				Expect(execute("registry.example.com", "get")).To(Succeed())
+				_, key := fakeConn.PasswordArgsForCall(i)
+				Expect(key).To(Equal(teamvault.Key("REG789")))
+			}
+		})
+
+		It("resolves an alias configured for a registry", func() {
+			Expect(os.WriteFile(configPath, []byte(`{
+				"url": "https://vault.example.com",
+				"dockerRegistries": {"registry.example.com": "ci-registry"}
+			}`), 0600)).To(Succeed())
+			Expect(
+				teamvault.AliasesPath(filepath.Join(filepath.Dir(configPath), "config.aliases.json")).
+					Write(ctx, teamvault.Aliases{"ci-registry": "REG999"}),
+			).To(Succeed())
+
+			Expect(execute("registry.example.com", "get")).To(Succeed())
+
+			_, key := fakeConn.PasswordArgsForCall(0)
+			Expect(key).To(Equal(teamvault.Key("REG999")))
+		})
+
+		It("falls back to a search on the url field", func() {
+			fakeConn.SearchReturns([]teamvault.SearchResult{
+				{Key: "OTHER1", Url: "https://other.example.com"},
+				{Key: "HUB123", Url: "https://ghcr.io/"},
+			}, nil)
+
+			Expect(execute("ghcr.io", "get")).To(Succeed())
+
+			_, query := fakeConn.SearchArgsForCall(0)
+			Expect(query).To(Equal("ghcr.io"))
+			_, key := fakeConn.PasswordArgsForCall(0)
+			Expect(key).To(Equal(teamvault.Key("HUB123")))
+		})
+
+		It("prints the protocol not-found message when nothing matches", func() {
+			fakeConn.SearchReturns(nil, nil)
+
+			err := execute("unknown.example.com", "get")
+			Expect(err).To(HaveOccurred())
+			Expect(outBuf.String()).To(Equal("credentials not found in native keychain\n"))
+		})
+
+		It("fails on ambiguous search matches", func() {
+			fakeConn.SearchReturns([]teamvault.SearchResult{
+				{Key: "A1", Url: "https://ghcr.io"},
+				{Key: "B2", Url: "ghcr.io/org"},
+			}, nil)
+
+			err := execute("ghcr.io", "get")
+			Expect(err).To(HaveOccurred())
+			Expect(outBuf.String()).To(ContainSubstring("A1, B2"))
+		})
+	})
+
+	Describe("list", func() {
+		It("lists configured registries with their usernames", func() {
+			Expect(execute("", "list")).To(Succeed())
+
+			var list map[string]string
+			Expect(json.Unmarshal(outBuf.Bytes(), &list)).To(Succeed())
+			Expect(list).To(Equal(map[string]string{"registry.example.com": "robot"}))
+		})
+	})
+
+	Describe("store", func() {
+		It("updates the mapped secret", func() {
+			Expect(execute(
+				`{"ServerURL":"registry.example.com","Username":"ci","Secret":"new"}`,
+				"store",
+			)).To(Succeed())
+
+			Expect(mockWriter.UpdateCallCount()).To(Equal(1))
+			_, key, secret := mockWriter.UpdateArgsForCall(0)
+			Expect(key).To(Equal(teamvault.Key("REG123")))
+			Expect(*secret.Username).To(Equal("ci"))
+			Expect(*secret.Password).To(Equal(teamvault.Password("new")))
+		})
+
+		It("creates a secret for an unknown registry", func() {
+			fakeConn.SearchReturns(nil, nil)
+
+			Expect(execute(
+				`{"ServerURL":"https://quay.io","Username":"bot","Secret":"s"}`,
+				"store",
+			)).To(Succeed())
+
+			Expect(mockWriter.CreateCallCount()).To(Equal(1))
+			_, secret := mockWriter.CreateArgsForCall(0)
+			Expect(secret.Name).To(Equal("quay.io"))
+			Expect(secret.Url).To(Equal("https://quay.io"))
+			Expect(secret.Username).To(Equal("bot"))
+			Expect(secret.Password).To(Equal(teamvault.Password("s")))
+		})
+	})
+
+	Describe("erase", func() {
+		It("leaves TeamVault untouched", func() {
+			Expect(execute("registry.example.com", "erase")).To(Succeed())
+			Expect(mockWriter.UpdateCallCount()).To(Equal(0))
+			Expect(mockWriter.CreateCallCount()).To(Equal(0))
+		})
+	})
+
+	It("rejects unknown actions", func() {
+		Expect(execute("", "bogus")).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,360 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/bborbe/errors"
+	"github.com/spf13/cobra"
+	"go.yaml.in/yaml/v3"
+
+	teamvault "github.com/Seibert-Data/teamvault-cli/v5/pkg"
+)
+
+// editFrontMatter is the YAML front matter `edit --metadata` puts above the
+// value. The fields are pointers so an emptied field (`username:`) decodes
+// to nil and clears the value rather than keeping it.
+type editFrontMatter struct {
+	Name        *string `yaml:"name"`
+	Username    *string `yaml:"username"`
+	Url         *string `yaml:"url"`
+	Description *string `yaml:"description"`
+}
+
+// runEditor opens path in the user's editor and waits for it to exit.
+// Overridden by tests via SetEditorForTest.
+var runEditor = func(path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+	// EDITOR may carry arguments, e.g. "code --wait".
+	args := append(strings.Fields(editor), path)
+	// #nosec G204 -- runs the editor the user configured
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// SetEditorForTest replaces the editor `edit` runs on the temp file.
+// Returns a function to call in AfterEach to reset.
+func SetEditorForTest(f func(path string) error) func() {
+	prev := runEditor
+	runEditor = f
+	return func() { runEditor = prev }
+}
+
+// editTempDir returns where `edit` puts its temp file: /dev/shm when
+// available, so the secret stays in memory, else the system temp dir.
+func editTempDir() string {
+	if info, err := os.Stat("/dev/shm"); err == nil && info.IsDir() {
+		return "/dev/shm"
+	}
+	return os.TempDir()
+}
+
+// createEditCommand creates the `edit` subcommand.
+func createEditCommand(ctx context.Context, sf *SharedFlags) *cobra.Command {
+	var withMetadata bool
+
+	cmd := &cobra.Command{
+		Use:   "edit [key]",
+		Short: "Edit a secret's password or file in $EDITOR",
+		Long: `Edit a secret's password or file in $EDITOR.
+
+The password (or the decoded file, for file secrets) is written to a 0600 temp
+file, on /dev/shm when available, and opened in $VISUAL or $EDITOR (default
+vi). With --metadata the name, username, url and description are added as
+YAML front matter. After the editor exits only the changed fields are
+updated; the temp file is overwritten and removed in any case.
+
+The update is aborted when the secret's revision changed on the server while
+editing.`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeKeyArg(ctx, sf),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key, err := resolveKey(cmd, args)
+			if err != nil {
+				return err
+			}
+			conn, err := newConnector(sf)(ctx)
+			if err != nil {
+				return errors.Wrap(ctx, err, "create connector failed")
+			}
+			key, err = lookupKey(ctx, cmd, sf, conn, key)
+			if err != nil {
+				return err
+			}
+			before, err := teamvault.ReadMetadata(ctx, conn, key)
+			if err != nil {
+				return errors.Wrap(ctx, err, "read metadata failed")
+			}
+			content, err := readEditableContent(ctx, conn, before)
+			if err != nil {
+				return err
+			}
+
+			document, err := renderEditDocument(ctx, before, content, withMetadata)
+			if err != nil {
+				return err
+			}
+			edited, err := editInTempFile(ctx, document)
+			if err != nil {
+				return err
+			}
+			secret, changed, err := editedUpdate(ctx, before, content, edited, withMetadata)
+			if err != nil {
+				return err
+			}
+			if len(changed) == 0 {
+				fmt.Fprintln(cmd.ErrOrStderr(), "No changes.")
+				return nil
+			}
+
+			after, err := teamvault.ReadMetadata(ctx, conn, key)
+			if err != nil {
+				return errors.Wrap(ctx, err, "read metadata failed")
+			}
+			if after.CurrentRevision != before.CurrentRevision {
+				return errors.Wrapf(
+					ctx,
+					teamvault.ErrRevisionConflict,
+					"secret %s changed on the server while editing; update aborted",
+					key,
+				)
+			}
+			secret.ExpectedRevision = before.CurrentRevision
+			writer, err := newWriter(sf)(ctx)
+			if err != nil {
+				return errors.Wrap(ctx, err, "create writer failed")
+			}
+			if _, _, err := writer.Update(ctx, key, secret); err != nil {
+				return errors.Wrap(ctx, err, "update secret failed")
+			}
+			fmt.Fprintf(
+				cmd.ErrOrStderr(),
+				"Updated %s of %s.\n",
+				strings.Join(changed, ", "),
+				key,
+			)
+			return nil
+		},
+	}
+
+	var key string
+	cmd.Flags().
+		StringVar(&key, "teamvault-key", "", "teamvault key (alternative to positional argument)")
+	cmd.Flags().BoolVar(
+		&withMetadata,
+		"metadata",
+		false,
+		"also edit name, username, url and description as YAML front matter",
+	)
+	return cmd
+}
+
+// readEditableContent reads the value `edit` opens: the decoded file for
+// file secrets, the password otherwise.
+func readEditableContent(
+	ctx context.Context,
+	conn teamvault.Connector,
+	metadata teamvault.SecretMetadata,
+) ([]byte, error) {
+	if metadata.ContentType == teamvault.ContentTypeFile {
+		file, err := conn.File(ctx, metadata.Key)
+		if err != nil {
+			return nil, errors.Wrap(ctx, err, "get file failed")
+		}
+		content, err := file.Content()
+		if err != nil {
+			return nil, errors.Wrapf(ctx, err, "decode file of %s failed", metadata.Key)
+		}
+		return content, nil
+	}
+	password, err := conn.Password(ctx, metadata.Key)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "get password failed")
+	}
+	return []byte(password.String()), nil
+}
+
+// editInTempFile writes document to a private temp file, runs the editor on
+// it and returns the saved content. The file is shredded afterwards, also
+// when the editor fails.
+func editInTempFile(ctx context.Context, document []byte) ([]byte, error) {
+	dir, err := os.MkdirTemp(editTempDir(), "teamvault-edit-")
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "create temp dir failed")
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "secret")
+	defer shredFile(path)
+
+	if err := os.WriteFile(path, document, 0600); err != nil {
+		return nil, errors.Wrapf(ctx, err, "write temp file failed")
+	}
+	if err := runEditor(path); err != nil {
+		return nil, errors.Wrapf(ctx, err, "editor failed")
+	}
+	edited, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "read temp file failed")
+	}
+	return edited, nil
+}
+
+// shredFile overwrites the file with zeros before removing it. Editors
+// that save by replacing the file leave the old content to the file
+// system, which is why a tmpfs is preferred.
+func shredFile(path string) {
+	if info, err := os.Stat(path); err == nil {
+		if f, err := os.OpenFile(path, os.O_WRONLY, 0); err == nil {
+			_, _ = f.Write(make([]byte, info.Size()))
+			_ = f.Sync()
+			_ = f.Close()
+		}
+	}
+	_ = os.Remove(path)
+}
+
+// renderEditDocument builds the temp file content: the value, preceded by
+// the metadata front matter when requested.
+func renderEditDocument(
+	ctx context.Context,
+	metadata teamvault.SecretMetadata,
+	content []byte,
+	withMetadata bool,
+) ([]byte, error) {
+	if !withMetadata {
+		return content, nil
+	}
+	frontMatter, err := yaml.Marshal(metadataFrontMatter(metadata))
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "marshal front matter failed")
+	}
+	var buf bytes.Buffer
+	buf.WriteString("---\n")
+	buf.Write(frontMatter)
+	buf.WriteString("---\n")
+	buf.Write(content)
+	return buf.Bytes(), nil
+}
+
+func metadataFrontMatter(metadata teamvault.SecretMetadata) editFrontMatter {
+	url := metadata.Url.String()
+	return editFrontMatter{
+		Name:        &metadata.Name,
+		Username:    &metadata.Username,
+		Url:         &url,
+		Description: &metadata.Description,
+	}
+}
+
+// editedUpdate compares the saved document with the original and returns
+// the update for the changed fields, plus their names.
+func editedUpdate(
+	ctx context.Context,
+	before teamvault.SecretMetadata,
+	content []byte,
+	edited []byte,
+	withMetadata bool,
+) (teamvault.UpdateSecret, []string, error) {
+	var secret teamvault.UpdateSecret
+	var changed []string
+	if withMetadata {
+		original := metadataFrontMatter(before)
+		frontMatter := metadataFrontMatter(before)
+		body, err := splitFrontMatter(ctx, edited, &frontMatter)
+		if err != nil {
+			return secret, nil, err
+		}
+		edited = body
+		for _, field := range []struct {
+			name          string
+			before, after *string
+			update        **string
+		}{
+			{"name", original.Name, frontMatter.Name, &secret.Name},
+			{"username", original.Username, frontMatter.Username, &secret.Username},
+			{"url", original.Url, frontMatter.Url, &secret.Url},
+			{"description", original.Description, frontMatter.Description, &secret.Description},
+		} {
+			value := ""
+			if field.after != nil {
+				value = *field.after
+			}
+			if value == *field.before {
+				continue
+			}
+			*field.update = &value
+			changed = append(changed, field.name)
+		}
+	}
+
+	if before.ContentType == teamvault.ContentTypeFile {
+		// Editors commonly add a final newline; that alone is no change.
+		if !bytes.Equal(edited, content) && !bytes.Equal(edited, append(content, '\n')) {
+			secret.FileContent = edited
+			changed = append(changed, "file")
+		}
+		return secret, changed, nil
+	}
+	password := strings.TrimSuffix(strings.TrimSuffix(string(edited), "\n"), "\r")
+	if password != string(content) {
+		if password == "" {
+			return secret, nil, errors.New(ctx, "password empty; update aborted")
+		}
+		value := teamvault.Password(password)
+		secret.Password = &value
+		changed = append(changed, "password")
+	}
+	return secret, changed, nil
+}
+
+// splitFrontMatter decodes the `---` delimited front matter written by
+// renderEditDocument into frontMatter and returns the rest exactly as saved,
+// so CRLF line endings are only normalized in the front matter. Fields
+// removed from it keep their value in frontMatter.
+func splitFrontMatter(
+	ctx context.Context,
+	document []byte,
+	frontMatter *editFrontMatter,
+) ([]byte, error) {
+	first, rest, _ := bytes.Cut(document, []byte("\n"))
+	if strings.TrimSuffix(string(first), "\r") != "---" {
+		return nil, errors.New(ctx, "front matter missing: the file must start with ---")
+	}
+	var header strings.Builder
+	for len(rest) > 0 {
+		line, next, _ := bytes.Cut(rest, []byte("\n"))
+		text := strings.TrimSuffix(string(line), "\r")
+		if text == "---" {
+			decoder := yaml.NewDecoder(strings.NewReader(header.String()))
+			decoder.KnownFields(true)
+			if err := decoder.Decode(frontMatter); err != nil && !stderrors.Is(err, io.EOF) {
+				return nil, errors.Wrapf(ctx, err, "parse front matter failed")
+			}
+			return next, nil
+		}
+		header.WriteString(text)
+		header.WriteString("\n")
+		rest = next
+	}
+	return nil, errors.New(ctx, "front matter not closed by ---")
+}
@@ -0,0 +1,247 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"os"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	teamvault "github.com/Seibert-Data/teamvault-cli/v5/pkg"
+	"github.com/Seibert-Data/teamvault-cli/v5/pkg/cli"
+	"github.com/Seibert-Data/teamvault-cli/v5/pkg/mocks"
+)
+
+// metadataConnector is a fake connector that also reads metadata.
+type metadataConnector struct {
+	*mocks.Connector
+	*mocks.MetadataReader
+}
+
+var _ = Describe("edit", func() {
+	var ctx context.Context
+	var fakeConn metadataConnector
+	var fakeWriter *mocks.Writer
+	var metadata teamvault.SecretMetadata
+	var edit func(content string) string
+	var editedPath string
+	var stderr bytes.Buffer
+	var resets []func()
+
+	run := func(args ...string) error {
+		stderr.Reset()
+		cmd := cli.NewRootCommand(ctx)
+		cmd.SetArgs(append([]string{"edit"}, args...))
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(&stderr)
+		return cmd.Execute()
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		os.Setenv("STAGING", "true")
+		metadata = teamvault.SecretMetadata{
+			Key:             "AbC123",
+			Name:            "prod db",
+			Username:        "admin",
+			Url:             "https://db.example.com",
+			Description:     "primary: eu",
+			ContentType:     teamvault.ContentTypePassword,
+			CurrentRevision: "https://vault.example.com/api/secret-revisions/rev1/",
+		}
+		fakeConn = metadataConnector{
+			Connector:      &mocks.Connector{},
+			MetadataReader: &mocks.MetadataReader{},
+		}
+		fakeConn.MetadataReader.MetadataStub = func(
+			ctx context.Context,
+			key teamvault.Key,
+		) (teamvault.SecretMetadata, error) {
+			return metadata, nil
+		}
+		fakeConn.PasswordReturns("old-pass", nil)
+		fakeWriter = &mocks.Writer{}
+		edit = func(content string) string { return content }
+		editedPath = ""
+		resets = []func(){
+			cli.SetNewConnectorForTest(
+				func(sf *cli.SharedFlags) func(context.Context) (teamvault.Connector, error) {
+					return func(ctx context.Context) (teamvault.Connector, error) {
+						return fakeConn, nil
+					}
+				},
+			),
+			cli.SetNewWriterForTest(
+				func(sf *cli.SharedFlags) func(context.Context) (teamvault.Writer, error) {
+					return func(ctx context.Context) (teamvault.Writer, error) {
+						return fakeWriter, nil
+					}
+				},
+			),
+			cli.SetEditorForTest(func(path string) error {
+				editedPath = path
+				info, err := os.Stat(path)
+				Expect(err).To(BeNil())
+				Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+				content, err := os.ReadFile(path)
+				Expect(err).To(BeNil())
+				return os.WriteFile(path, []byte(edit(string(content))), 0600)
+			}),
+		}
+	})
+
+	AfterEach(func() {
+		for _, reset := range resets {
+			reset()
+		}
+		os.Unsetenv("STAGING")
+	})
+
+	It("updates only the password when it changed", func() {
+		edit = func(content string) string {
+			Expect(content).To(Equal("old-pass"))
+			return "new-pass\n"
+		}
+		Expect(run("AbC123")).To(Succeed())
+
+		Expect(fakeWriter.UpdateCallCount()).To(Equal(1))
+		_, key, secret := fakeWriter.UpdateArgsForCall(0)
+		Expect(key).To(Equal(teamvault.Key("AbC123")))
+		Expect(*secret.Password).To(Equal(teamvault.Password("new-pass")))
+		Expect(secret.Name).To(BeNil())
+		Expect(secret.FileContent).To(BeNil())
+		Expect(secret.ExpectedRevision).To(Equal(metadata.CurrentRevision))
+		Expect(stderr.String()).To(ContainSubstring("Updated password of AbC123."))
+	})
+
+	It("shreds the temp file afterwards", func() {
+		Expect(run("AbC123")).To(Succeed())
+
+		Expect(editedPath).NotTo(BeEmpty())
+		_, err := os.Stat(editedPath)
+		Expect(os.IsNotExist(err)).To(BeTrue())
+	})
+
+	It("does not update when nothing changed", func() {
+		edit = func(content string) string { return content + "\n" }
+		Expect(run("AbC123")).To(Succeed())
+
+		Expect(fakeWriter.UpdateCallCount()).To(Equal(0))
+		Expect(stderr.String()).To(ContainSubstring("No changes."))
+	})
+
+	It("edits the decoded content of file secrets", func() {
+		metadata.ContentType = teamvault.ContentTypeFile
+		fakeConn.FileReturns(
+			teamvault.File(base64.StdEncoding.EncodeToString([]byte("line1\n"))),
+			nil,
+		)
+		edit = func(content string) string {
+			Expect(content).To(Equal("line1\n"))
+			return "line1\nline2\n"
+		}
+		Expect(run("AbC123")).To(Succeed())
+
+		Expect(fakeConn.PasswordCallCount()).To(Equal(0))
+		Expect(fakeWriter.UpdateCallCount()).To(Equal(1))
+		_, _, secret := fakeWriter.UpdateArgsForCall(0)
+		Expect(string(secret.FileContent)).To(Equal("line1\nline2\n"))
+		Expect(secret.Password).To(BeNil())
+	})
+
+	It("keeps CRLF line endings of a file secret edited with --metadata", func() {
+		metadata.ContentType = teamvault.ContentTypeFile
+		fakeConn.FileReturns(
+			teamvault.File(base64.StdEncoding.EncodeToString([]byte("line1\r\nline2\r\n"))),
+			nil,
+		)
+		edit = func(content string) string {
+			Expect(content).To(HaveSuffix("---\nline1\r\nline2\r\n"))
+			return "---\r\n" +
+				"name: prod db\r\n" +
+				"username: root\r\n" +
+				"url: https://db.example.com\r\n" +
+				"description: 'primary: eu'\r\n" +
+				"---\r\n" +
+				"line1\r\nline2\r\n"
+		}
+		Expect(run("AbC123", "--metadata")).To(Succeed())
+
+		_, _, secret := fakeWriter.UpdateArgsForCall(0)
+		Expect(*secret.Username).To(Equal("root"))
+		Expect(secret.FileContent).To(BeNil())
+	})
+
+	It("edits the metadata as front matter with --metadata", func() {
+		edit = func(content string) string {
+			Expect(content).To(Equal("---\n" +
+				"name: prod db\n" +
+				"username: admin\n" +
+				"url: https://db.example.com\n" +
+				"description: 'primary: eu'\n" +
+				"---\n" +
+				"old-pass"))
+			return strings.Replace(content, "username: admin", "username: 'root'", 1)
+		}
+		Expect(run("AbC123", "--metadata")).To(Succeed())
+
+		Expect(fakeWriter.UpdateCallCount()).To(Equal(1))
+		_, _, secret := fakeWriter.UpdateArgsForCall(0)
+		Expect(*secret.Username).To(Equal("root"))
+		Expect(secret.Name).To(BeNil())
+		Expect(secret.Description).To(BeNil())
+		Expect(secret.Password).To(BeNil())
+	})
+
+	It("rejects unknown front matter fields", func() {
+		edit = func(content string) string {
+			return strings.Replace(content, "name:", "title:", 1)
+		}
+		err := run("AbC123", "--metadata")
+
+		Expect(err).NotTo(BeNil())
+		Expect(err.Error()).To(ContainSubstring("field title not found"))
+		Expect(fakeWriter.UpdateCallCount()).To(Equal(0))
+	})
+
+	It("reads multi-line, commented and cleared front matter values", func() {
+		edit = func(content string) string {
+			return "---\n" +
+				"# owned by the db team\n" +
+				"name: prod db # unchanged\n" +
+				"username:\n" +
+				"description: |\n" +
+				"  primary: eu\n" +
+				"  replica: us\n" +
+				"---\n" +
+				"old-pass"
+		}
+		Expect(run("AbC123", "--metadata")).To(Succeed())
+
+		_, _, secret := fakeWriter.UpdateArgsForCall(0)
+		Expect(secret.Name).To(BeNil())
+		Expect(*secret.Username).To(Equal(""))
+		Expect(secret.Url).To(BeNil())
+		Expect(*secret.Description).To(Equal("primary: eu\nreplica: us\n"))
+		Expect(secret.Password).To(BeNil())
+	})
+
+	It("aborts when the revision changed while editing", func() {
+		edit = func(content string) string {
+			metadata.CurrentRevision = "https://vault.example.com/api/secret-revisions/rev2/"
+			return "new-pass"
+		}
+		err := run("AbC123")
+
+		Expect(err).NotTo(BeNil())
+		Expect(err.Error()).To(ContainSubstring("changed on the server while editing"))
+		Expect(fakeWriter.UpdateCallCount()).To(Equal(0))
+	})
+})
@@ -0,0 +1,16 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cli
+
+// ConfigPath returns the config file the flags read credentials from.
+func (sf *SharedFlags) ConfigPath() string {
+	return sf.configPath
+}
+
+// Uncached reports whether connectors built from the flags bypass the disk
+// cache.
+func (sf *SharedFlags) Uncached() bool {
+	return sf.noCache
+}
@@ -0,0 +1,161 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cli
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+
+	"github.com/bborbe/errors"
+	"github.com/spf13/cobra"
+
+	teamvault "github.com/Seibert-Data/teamvault-cli/v5/pkg"
+)
+
+// passwordPolicyFlagNames are the flags that only apply to character
+// passwords, and passphraseFlagNames those that only apply to --diceware.
+var (
+	passwordPolicyFlagNames = []string{"length", "classes", "exclude", "min"}
+	passphraseFlagNames     = []string{"words", "separator", "capitalize"}
+)
+
+// generatorFlags holds the policy flags shared by generate, create and
+// update.
+type generatorFlags struct {
+	length     int
+	classes    []string
+	exclude    string
+	minCounts  map[string]int
+	diceware   bool
+	words      int
+	separator  string
+	capitalize bool
+}
+
+// addGeneratorFlags registers the policy flags on cmd.
+func addGeneratorFlags(cmd *cobra.Command) *generatorFlags {
+	passwordPolicy := teamvault.DefaultPasswordPolicy()
+	passphrasePolicy := teamvault.DefaultPassphrasePolicy()
+	classes := make([]string, 0, len(passwordPolicy.Classes))
+	for _, class := range passwordPolicy.Classes {
+		classes = append(classes, string(class))
+	}
+
+	gf := &generatorFlags{}
+	cmd.Flags().IntVar(&gf.length, "length", passwordPolicy.Length, "password length")
+	cmd.Flags().StringSliceVar(
+		&gf.classes,
+		"classes",
+		classes,
+		"character classes to use (lower, upper, digits, symbols)",
+	)
+	cmd.Flags().StringVar(&gf.exclude, "exclude", "", "characters never to use, e.g. '\"`\\'")
+	cmd.Flags().StringToIntVar(
+		&gf.minCounts,
+		"min",
+		nil,
+		"minimum characters per class, e.g. digits=2,symbols=1 (default 1 each)",
+	)
+	cmd.Flags().BoolVar(&gf.diceware, "diceware", false, "generate a diceware passphrase instead")
+	cmd.Flags().IntVar(&gf.words, "words", passphrasePolicy.Words, "number of passphrase words")
+	cmd.Flags().StringVar(
+		&gf.separator,
+		"separator",
+		passphrasePolicy.Separator,
+		"separator between passphrase words",
+	)
+	cmd.Flags().BoolVar(&gf.capitalize, "capitalize", false, "capitalize passphrase words")
+	return gf
+}
+
+// changed reports whether any policy flag was given.
+func (gf *generatorFlags) changed(cmd *cobra.Command) bool {
+	names := append([]string{"diceware"}, passwordPolicyFlagNames...)
+	for _, name := range append(names, passphraseFlagNames...) {
+		if cmd.Flags().Changed(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// generate returns a password or, with --diceware, a passphrase generated
+// locally with crypto/rand.
+func (gf *generatorFlags) generate(
+	ctx context.Context,
+	cmd *cobra.Command,
+) (teamvault.Password, error) {
+	generator := teamvault.NewLocalPasswordGenerator(rand.Reader)
+	notApplicable := passphraseFlagNames
+	if gf.diceware {
+		notApplicable = passwordPolicyFlagNames
+	}
+	for _, name := range notApplicable {
+		if cmd.Flags().Changed(name) {
+			if gf.diceware {
+				return "", errors.Errorf(ctx, "--%s does not apply to --diceware", name)
+			}
+			return "", errors.Errorf(ctx, "--%s requires --diceware", name)
+		}
+	}
+
+	if gf.diceware {
+		return generator.Passphrase(ctx, teamvault.PassphrasePolicy{
+			Words:      gf.words,
+			Separator:  gf.separator,
+			Capitalize: gf.capitalize,
+		})
+	}
+	policy := teamvault.PasswordPolicy{
+		Length:  gf.length,
+		Exclude: gf.exclude,
+	}
+	for _, class := range gf.classes {
+		policy.Classes = append(policy.Classes, teamvault.PasswordCharacterClass(class))
+	}
+	if len(gf.minCounts) > 0 {
+		policy.MinCounts = make(map[teamvault.PasswordCharacterClass]int, len(gf.minCounts))
+		for class, count := range gf.minCounts {
+			policy.MinCounts[teamvault.PasswordCharacterClass(class)] = count
+		}
+	}
+	return generator.Password(ctx, policy)
+}
+
+// createGenerateCommand creates the generate command.
+func createGenerateCommand(ctx context.Context) *cobra.Command {
+	var gf *generatorFlags
+	cmd := &cobra.Command{
+		Use:   "generate",
+		Short: "Generate a password or passphrase locally",
+		Long: `Generate a password or passphrase locally.
+
+Unlike create/update --generate, which use the TeamVault server's password
+policy, the password is generated on this machine with crypto/rand. Choose
+the length, the character classes, characters to exclude and minimum counts
+per class; every selected class appears at least once unless --min sets it
+to 0. --diceware generates words from the EFF large wordlist instead.
+
+The same flags apply to create and update with --generate-local:
+
+  teamvault-cli generate --length 32 --exclude '"\'
+  teamvault-cli generate --classes lower,upper,digits --min digits=4
+  teamvault-cli update <KEY> --generate-local --diceware --words 7`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			password, err := gf.generate(ctx, cmd)
+			if err != nil {
+				return errors.Wrap(ctx, err, "generate password failed")
+			}
+			if _, err := fmt.Fprintln(cmd.OutOrStdout(), password); err != nil {
+				return errors.Wrapf(ctx, err, "write password failed")
+			}
+			return nil
+		},
+	}
+	gf = addGeneratorFlags(cmd)
+	return cmd
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"regexp"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/Seibert-Data/teamvault-cli/v5/pkg/cli"
+)
+
+var _ = Describe("generate", func() {
+	var ctx context.Context
+	var stdout bytes.Buffer
+
+	run := func(args ...string) error {
+		stdout.Reset()
+		cmd := cli.NewRootCommand(ctx)
+		cmd.SetArgs(append([]string{"generate"}, args...))
+		cmd.SetOut(&stdout)
+		cmd.SetErr(&bytes.Buffer{})
+		return cmd.Execute()
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	It("prints a password of the default policy", func() {
+		Expect(run()).To(Succeed())
+		Expect(strings.TrimSuffix(stdout.String(), "\n")).To(HaveLen(24))
+	})
+
+	It("applies classes, exclusions and minimum counts", func() {
+		Expect(run(
+			"--length", "20",
+			"--classes", "upper,digits",
+			"--exclude", "0O1I",
+			"--min", "digits=10",
+		)).To(Succeed())
+
+		password := strings.TrimSuffix(stdout.String(), "\n")
+		Expect(password).To(MatchRegexp(`^[A-HJ-NP-Z2-9]{20}$`))
+		digits := regexp.MustCompile(`[0-9]`).FindAllString(password, -1)
+		Expect(len(digits)).To(BeNumerically(">=", 10))
+	})
+
+	It("prints a diceware passphrase", func() {
+		Expect(run("--diceware", "--words", "4", "--separator", " ", "--capitalize")).To(Succeed())
+		Expect(strings.Fields(stdout.String())).To(HaveLen(4))
+		Expect(stdout.String()).To(MatchRegexp(`^([A-Z][a-z-]+ ?){4}\n$`))
+	})
+
+	It("rejects password flags with --diceware", func() {
+		err := run("--diceware", "--length", "10")
+		Expect(err).NotTo(BeNil())
+		Expect(err.Error()).To(ContainSubstring("--length does not apply to --diceware"))
+	})
+
+	It("rejects an unsatisfiable policy", func() {
+		err := run("--classes", "digits", "--exclude", "0123456789")
+		Expect(err).NotTo(BeNil())
+		Expect(err.Error()).To(ContainSubstring("no characters left"))
+	})
+})
@@ -0,0 +1,266 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/bborbe/errors"
+	"github.com/spf13/cobra"
+
+	teamvault "github.com/Seibert-Data/teamvault-cli/v5/pkg"
+)
+
+// gitCredentialRequest is the attribute block git writes to a credential
+// helper's stdin (protocol/host/path, optionally username or url).
+type gitCredentialRequest struct {
+	Protocol string
+	Host     string
+	Path     string
+	Username string
+}
+
+// createGitCredentialCommand builds the `git-credential` subcommand, which
+// implements git's credential helper protocol. Configure it with
+// `git config --global credential.helper '!teamvault-cli git-credential'`
+// (or symlink the binary as git-credential-teamvault and use
+// `credential.helper teamvault`).
+func createGitCredentialCommand(ctx context.Context, sf *SharedFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "git-credential <get|store|erase>",
+		Short: "git credential helper backed by TeamVault",
+		Long: `git credential helper backed by TeamVault.
+
+get matches the requested protocol/host/path against the "gitCredentials" map in
+the config file (URL prefix -> key, longest prefix wins), or else against the
+url field of the secrets a search for the host returns, and answers with the
+secret's username and password. A prefix or secret url matches when it has the
+same host (and protocol, if it states one) and its path is a prefix of the
+requested path; set credential.useHttpPath=true for per-repository secrets. No
+match produces no output, so git falls through to its next helper or prompt.
+
+store and erase are accepted and ignored: credentials are managed in TeamVault.`,
+		Args:      cobra.ExactArgs(1),
+		ValidArgs: []string{"get", "store", "erase"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			req, err := readGitCredentialRequest(ctx, cmd.InOrStdin())
+			if err != nil {
+				return err
+			}
+			switch args[0] {
+			case "get":
+				return gitCredentialGet(ctx, sf, req, cmd.OutOrStdout())
+			case "store", "erase":
+				return nil
+			default:
+				return errors.Errorf(
+					ctx,
+					"unknown git-credential action %q (want get, store or erase)",
+					args[0],
+				)
+			}
+		},
+	}
+	return cmd
+}
+
+func gitCredentialGet(
+	ctx context.Context,
+	sf *SharedFlags,
+	req gitCredentialRequest,
+	out io.Writer,
+) error {
+	if req.Host == "" {
+		return nil
+	}
+	config, err := sf.readConfig(ctx)
+	if err != nil {
+		return err
+	}
+	var mappings map[string]teamvault.Key
+	if config != nil {
+		mappings = config.GitCredentials
+	}
+	conn, err := newConnector(sf)(ctx)
+	if err != nil {
+		return errors.Wrap(ctx, err, "create connector failed")
+	}
+	keyResolver, err := sf.keyResolver(ctx)
+	if err != nil {
+		return err
+	}
+	key, found, err := lookupGitCredential(ctx, conn, keyResolver, mappings, req)
+	if err != nil || !found {
+		return err
+	}
+	user, err := conn.User(ctx, key)
+	if err != nil {
+		return errors.Wrap(ctx, err, "get user failed")
+	}
+	pass, err := conn.Password(ctx, key)
+	if err != nil {
+		return errors.Wrap(ctx, err, "get password failed")
+	}
+	if _, err := fmt.Fprintf(out, "username=%s\npassword=%s\n", user, pass); err != nil {
+		return errors.Wrapf(ctx, err, "write credentials failed")
+	}
+	return nil
+}
+
+// readGitCredentialRequest parses key=value lines up to the first blank line
+// or EOF. A url attribute fills in whichever of protocol/host/path git did
+// not send separately.
+func readGitCredentialRequest(ctx context.Context, in io.Reader) (gitCredentialRequest, error) {
+	attrs := make(map[string]string)
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			break
+		}
+		if k, v, ok := strings.Cut(line, "="); ok {
+			attrs[k] = v
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return gitCredentialRequest{}, errors.Wrapf(ctx, err, "read credential request failed")
+	}
+	req := gitCredentialRequest{
+		Protocol: attrs["protocol"],
+		Host:     strings.ToLower(attrs["host"]),
+		Path:     attrs["path"],
+		Username: attrs["username"],
+	}
+	if raw := attrs["url"]; raw != "" {
+		u, err := url.Parse(raw)
+		if err != nil {
+			return gitCredentialRequest{}, errors.Wrapf(ctx, err, "parse url %q failed", raw)
+		}
+		if req.Protocol == "" {
+			req.Protocol = u.Scheme
+		}
+		if req.Host == "" {
+			req.Host = strings.ToLower(u.Host)
+		}
+		if req.Path == "" {
+			req.Path = u.Path
+		}
+		if req.Username == "" && u.User != nil {
+			req.Username = u.User.Username()
+		}
+	}
+	return req, nil
+}
+
+// lookupGitCredential resolves the TeamVault key for a request: the longest
+// matching gitCredentials prefix, else the most specific secret url among the
+// search results for the host. A mapping may name an alias or secret URL.
+// found is false when nothing matched.
+//
+// Mapping prefixes of the same length are ranked by whether they state a
+// protocol, then by their sort order, so the choice does not depend on map
+// iteration.
+func lookupGitCredential(
+	ctx context.Context,
+	conn teamvault.Connector,
+	keyResolver teamvault.KeyResolver,
+	mappings map[string]teamvault.Key,
+	req gitCredentialRequest,
+) (teamvault.Key, bool, error) {
+	prefixes := make([]string, 0, len(mappings))
+	for prefix := range mappings {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Strings(prefixes)
+	best, bestHasScheme := -1, false
+	var bestPrefix string
+	for _, prefix := range prefixes {
+		u, err := parseLooseURL(prefix)
+		if err != nil || !gitURLMatches(u, req) {
+			continue
+		}
+		n, hasScheme := len(gitPath(u.Path)), u.Scheme != ""
+		if n > best || n == best && hasScheme && !bestHasScheme {
+			best, bestHasScheme, bestPrefix = n, hasScheme, prefix
+		}
+	}
+	if best >= 0 {
+		key, err := keyResolver.ResolveKey(ctx, mappings[bestPrefix].String())
+		if err != nil {
+			return "", false, errors.Wrapf(ctx, err, "invalid key for %s", bestPrefix)
+		}
+		return key, true, nil
+	}
+
+	matches, err := searchByURL(ctx, conn, req.Host, func(u *url.URL) bool {
+		return gitURLMatches(u, req)
+	})
+	if err != nil {
+		return "", false, err
+	}
+	if req.Username != "" {
+		var sameUser []teamvault.SearchResult
+		for _, m := range matches {
+			if m.Username == req.Username {
+				sameUser = append(sameUser, m)
+			}
+		}
+		if len(sameUser) > 0 {
+			matches = sameUser
+		}
+	}
+	var candidates []teamvault.SearchResult
+	for _, m := range matches {
+		u, _ := parseLooseURL(m.Url.String())
+		switch n := len(gitPath(u.Path)); {
+		case n > best:
+			best, candidates = n, []teamvault.SearchResult{m}
+		case n == best:
+			candidates = append(candidates, m)
+		}
+	}
+	switch len(candidates) {
+	case 0:
+		return "", false, nil
+	case 1:
+		return candidates[0].Key, true, nil
+	default:
+		return "", false, errors.Errorf(
+			ctx,
+			"multiple TeamVault secrets match %s://%s/%s (%s); add a gitCredentials entry to pick one",
+			req.Protocol,
+			req.Host,
+			gitPath(req.Path),
+			joinKeys(candidates),
+		)
+	}
+}
+
+// gitURLMatches reports whether a secret or mapping URL covers the request:
+// same host, same protocol when the URL states one, and a path that is a
+// segment-wise prefix of the requested path.
+func gitURLMatches(u *url.URL, req gitCredentialRequest) bool {
+	if u.Host != req.Host {
+		return false
+	}
+	if req.Protocol != "" && u.Scheme != "" && u.Scheme != req.Protocol {
+		return false
+	}
+	prefix := gitPath(u.Path)
+	path := gitPath(req.Path)
+	return prefix == "" || path == prefix || strings.HasPrefix(path, prefix+"/")
+}
+
+// gitPath normalises a repository path for comparison: no surrounding slashes
+// and no ".git" suffix, so "/org/repo.git" and "org/repo" compare equal.
+func gitPath(p string) string {
+	return strings.TrimSuffix(strings.Trim(p, "/"), ".git")
+}
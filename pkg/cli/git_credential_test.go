@@ -0,0 +1,212 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	teamvault "github.com/Seibert-Data/teamvault-cli/v5/pkg"
+	"github.com/Seibert-Data/teamvault-cli/v5/pkg/cli"
+	"github.com/Seibert-Data/teamvault-cli/v5/pkg/mocks"
+)
+
+var _ = Describe("git-credential", func() {
+	var ctx context.Context
+	var fakeConn *mocks.Connector
+	var configPath string
+	var outBuf bytes.Buffer
+	var resetConnector func()
+
+	execute := func(stdin string, action string) error {
+		outBuf.Reset()
+		cmd := cli.NewRootCommand(ctx)
+		cmd.SetArgs([]string{"--teamvault-config", configPath, "git-credential", action})
+		cmd.SetIn(strings.NewReader(stdin))
+		cmd.SetOut(&outBuf)
+		cmd.SetErr(&bytes.Buffer{})
+		return cmd.Execute()
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		os.Setenv("STAGING", "true")
+		os.Unsetenv("TEAMVAULT_URL")
+		os.Unsetenv("TEAMVAULT_USER")
+		os.Unsetenv("TEAMVAULT_PASS")
+		os.Unsetenv("TEAMVAULT_CONFIG")
+		os.Unsetenv("TEAMVAULT_TIMEOUT")
+
+		configPath = filepath.Join(GinkgoT().TempDir(), "config.json")
+		Expect(os.WriteFile(configPath, []byte(`{
+			"url": "https://vault.example.com",
+			"user": "alice",
+			"pass": "pw",
+			"gitCredentials": {
+				"https://git.example.com": "HOST01",
+				"https://git.example.com/team/app": "REPO01"
+			}
+		}`), 0600)).To(Succeed())
+
+		fakeConn = &mocks.Connector{}
+		fakeConn.UserReturns(teamvault.User("deploy"), nil)
+		fakeConn.PasswordReturns(teamvault.Password("tok3n"), nil)
+		resetConnector = cli.SetNewConnectorForTest(
+			func(sf *cli.SharedFlags) func(context.Context) (teamvault.Connector, error) {
+				return func(ctx context.Context) (teamvault.Connector, error) {
+					return fakeConn, nil
+				}
+			},
+		)
+	})
+
+	AfterEach(func() {
+		resetConnector()
+		os.Unsetenv("STAGING")
+	})
+
+	Describe("get", func() {
+		It("answers with username and password from the longest mapping prefix", func() {
+			Expect(execute(
+				"protocol=https\nhost=git.example.com\npath=team/app.git\n\n",
+				"get",
+			)).To(Succeed())
+
+			Expect(outBuf.String()).To(Equal("username=deploy\npassword=tok3n\n"))
+			_, key := fakeConn.PasswordArgsForCall(0)
+			Expect(key).To(Equal(teamvault.Key("REPO01")))
+		})
+
+		It("uses the host mapping when no path is sent", func() {
+			Expect(execute("protocol=https\nhost=git.example.com\n", "get")).To(Succeed())
+
+			_, key := fakeConn.PasswordArgsForCall(0)
+			Expect(key).To(Equal(teamvault.Key("HOST01")))
+		})
+
+		It("matches a mapping without protocol for any protocol", func() {
+			Expect(os.WriteFile(configPath, []byte(`{
+				"url": "https://vault.example.com",
+				"gitCredentials": {"git.example.org/org": "LOOSE1"}
+			}`), 0600)).To(Succeed())
+
+			for _, protocol := range []string{"https", "http", "ssh"} {
+				Expect(execute(
+					"protocol="+protocol+"\nhost=git.example.org\npath=org/repo.git\n",
+					"get",
+				)).To(Succeed())
+			}
+
+			Expect(fakeConn.PasswordCallCount()).To(Equal(3))
+			for i := 0; i < 3; i++ {
+				_, key := fakeConn.PasswordArgsForCall(i)
+				Expect(key).To(Equal(teamvault.Key("LOOSE1")))
+			}
+		})
+
+		It("resolves an alias configured for a prefix", func() {
+			Expect(os.WriteFile(configPath, []byte(`{
+				"url": "https://vault.example.com",
+				"gitCredentials": {"https://git.example.org": "ci-git"}
+			}`), 0600)).To(Succeed())
+			Expect(
+				teamvault.AliasesPath(filepath.Join(filepath.Dir(configPath), "config.aliases.json")).
+					Write(ctx, teamvault.Aliases{"ci-git": "GIT999"}),
+			).To(Succeed())
+
+			Expect(execute("protocol=https\nhost=git.example.org\n", "get")).To(Succeed())
+
+			_, key := fakeConn.PasswordArgsForCall(0)
+			Expect(key).To(Equal(teamvault.Key("GIT999")))
+		})
+
+		It("breaks ties between mappings in a fixed order", func() {
+			Expect(os.WriteFile(configPath, []byte(`{
+				"url": "https://vault.example.com",
+				"gitCredentials": {
+					"git.example.org/org": "LOOSE1",
+					"https://git.example.org/org/": "SLASH1",
+					"https://git.example.org/org": "EXACT1",
+					"https://git.example.org/org.git": "SUFFIX"
+				}
+			}`), 0600)).To(Succeed())
+
+			for i := 0; i < 20; i++ {
+				Expect(execute(
+					"protocol=https\nhost=git.example.org\npath=org/repo.git\n",
+					"get",
+				)).To(Succeed())
+				_, key := fakeConn.PasswordArgsForCall(i)
+				Expect(key).To(Equal(teamvault.Key("EXACT1")))
+			}
+		})
+
+		It("matches secret urls from a search when no mapping applies", func() {
+			fakeConn.SearchReturns([]teamvault.SearchResult{
+				{Key: "ORG001", Url: "https://code.example.org/org"},
+				{Key: "REPO02", Url: "https://code.example.org/org/repo"},
+				{Key: "HTTP01", Url: "http://code.example.org/org/repo"},
+				{Key: "OTHER1", Url: "https://code.example.org/other"},
+			}, nil)
+
+			Expect(execute(
+				"protocol=https\nhost=code.example.org\npath=org/repo.git\n",
+				"get",
+			)).To(Succeed())
+
+			_, query := fakeConn.SearchArgsForCall(0)
+			Expect(query).To(Equal("code.example.org"))
+			_, key := fakeConn.PasswordArgsForCall(0)
+			Expect(key).To(Equal(teamvault.Key("REPO02")))
+		})
+
+		It("accepts a url attribute", func() {
+			fakeConn.SearchReturns([]teamvault.SearchResult{
+				{Key: "REPO02", Url: "https://code.example.org/org/repo"},
+			}, nil)
+
+			Expect(execute("url=https://code.example.org/org/repo.git\n", "get")).To(Succeed())
+
+			Expect(outBuf.String()).To(ContainSubstring("password=tok3n"))
+		})
+
+		It("prints nothing when no secret matches", func() {
+			fakeConn.SearchReturns(nil, nil)
+
+			Expect(execute("protocol=https\nhost=unknown.example.org\n", "get")).To(Succeed())
+			Expect(outBuf.String()).To(BeEmpty())
+		})
+
+		It("fails when several secrets match equally well", func() {
+			fakeConn.SearchReturns([]teamvault.SearchResult{
+				{Key: "A1", Url: "https://code.example.org/org"},
+				{Key: "B2", Url: "code.example.org/org/"},
+			}, nil)
+
+			err := execute("protocol=https\nhost=code.example.org\npath=org/repo\n", "get")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("A1, B2"))
+		})
+	})
+
+	DescribeTable("store and erase are no-ops",
+		func(action string) {
+			Expect(execute(
+				"protocol=https\nhost=git.example.com\nusername=x\npassword=y\n",
+				action,
+			)).To(Succeed())
+			Expect(outBuf.String()).To(BeEmpty())
+			Expect(fakeConn.PasswordCallCount()).To(Equal(0))
+		},
+		Entry("store", "store"),
+		Entry("erase", "erase"),
+	)
+})
@@ -20,9 +20,10 @@ import (
 // so no pre-computed hash needs to live in git.
 func createHtpasswdCommand(ctx context.Context, sf *SharedFlags) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "htpasswd [key]",
-		Short: "Print an htpasswd-format credential (user:bcrypt) for a TeamVault secret",
-		Args:  cobra.MaximumNArgs(1),
+		Use:               "htpasswd [key]",
+		Short:             "Print an htpasswd-format credential (user:bcrypt) for a TeamVault secret",
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeKeyArg(ctx, sf),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			key, err := resolveKey(cmd, args)
 			if err != nil {
@@ -32,6 +33,10 @@ func createHtpasswdCommand(ctx context.Context, sf *SharedFlags) *cobra.Command
 			if err != nil {
 				return errors.Wrap(ctx, err, "create connector failed")
 			}
+			key, err = lookupKey(ctx, cmd, sf, conn, key)
+			if err != nil {
+				return err
+			}
 			// Reuse the shared HtpasswdGenerator (same bcrypt logic the
 			// teamvaultHtpasswd config template func uses) — no duplicated hashing.
 			content, err := teamvault.NewHtpasswdGenerator(conn).Generate(ctx, key)
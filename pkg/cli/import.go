@@ -0,0 +1,219 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/bborbe/errors"
+	"github.com/spf13/cobra"
+
+	teamvault "github.com/Seibert-Data/teamvault-cli/v5/pkg"
+)
+
+// importFormats are the supported --format values.
+var importFormats = []string{"csv", "json", "keepass"}
+
+// createImportCommand creates the import command.
+func createImportCommand(ctx context.Context, sf *SharedFlags) *cobra.Command {
+	var format string
+	var columns string
+	var dryRun bool
+	var stateFile string
+	var continueOnError bool
+
+	cmd := &cobra.Command{
+		Use:   "import <file>",
+		Short: "Create password secrets from a CSV, JSON or KeePass XML export",
+		Long: `Create password secrets from a CSV, JSON or KeePass XML export.
+
+CSV files need a header row. Columns named name, username, url, description
+and password are picked up automatically; map others with --columns, e.g.
+--columns name=Title,password=Secret. JSON files hold an array of objects
+with those keys. KeePass 2.x XML exports (File > Export > KeePass XML) are
+read including nested groups, skipping the recycle bin.
+
+Records whose name already exists in TeamVault or earlier in the file are
+reported as duplicate and skipped. The keys of created secrets are written
+to the state file after each create; re-running the same import resumes
+after a failure without creating secrets twice.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := args[0]
+			if format == "" {
+				format = importFormatOf(path)
+			}
+			records, err := readImportFile(ctx, path, format, columns)
+			if err != nil {
+				return err
+			}
+			statePath := teamvault.ImportStatePath(stateFile)
+			if stateFile == "" {
+				statePath = teamvault.ImportStatePath(path + ".import-state.json")
+			}
+			state, err := statePath.Read(ctx)
+			if err != nil {
+				return err
+			}
+
+			conn, err := newConnector(sf)(ctx)
+			if err != nil {
+				return errors.Wrap(ctx, err, "create connector failed")
+			}
+			var writer teamvault.Writer
+			if !dryRun {
+				if writer, err = newWriter(sf)(ctx); err != nil {
+					return errors.Wrap(ctx, err, "create writer failed")
+				}
+			}
+			results, importErr := teamvault.NewImporter(conn, writer).Import(
+				ctx,
+				records,
+				teamvault.ImportOptions{
+					DryRun:          dryRun,
+					ContinueOnError: continueOnError,
+					State:           state,
+					OnCreated: func(ctx context.Context, name string, key teamvault.Key) error {
+						state[name] = key
+						return statePath.Write(ctx, state)
+					},
+				},
+			)
+			if err := writeImportResults(ctx, cmd.OutOrStdout(), results); err != nil {
+				return err
+			}
+			if importErr != nil {
+				return errors.Wrapf(
+					ctx,
+					importErr,
+					"import incomplete; fix the failures and re-run to resume (state in %s)",
+					statePath,
+				)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(
+		&format,
+		"format",
+		"",
+		"input format: "+strings.Join(importFormats, ", ")+" (default: from the file extension)",
+	)
+	cmd.Flags().
+		StringVar(&columns, "columns", "", "CSV column mapping, e.g. name=Title,password=Secret")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "report what would be created without writing")
+	cmd.Flags().StringVar(
+		&stateFile,
+		"state-file",
+		"",
+		"file recording created secrets for resuming (default: <file>.import-state.json)",
+	)
+	cmd.Flags().BoolVar(
+		&continueOnError,
+		"continue-on-error",
+		false,
+		"keep importing the remaining records after a failure",
+	)
+	return cmd
+}
+
+// importFormatOf guesses the format from the file extension; .xml is KeePass.
+func importFormatOf(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return "json"
+	case ".xml":
+		return "keepass"
+	default:
+		return "csv"
+	}
+}
+
+// readImportFile parses path in the given format.
+func readImportFile(
+	ctx context.Context,
+	path string,
+	format string,
+	columns string,
+) ([]teamvault.ImportRecord, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "open %s failed", path)
+	}
+	defer file.Close()
+
+	switch format {
+	case "csv":
+		mapping, err := teamvault.ParseImportColumns(ctx, columns)
+		if err != nil {
+			return nil, err
+		}
+		return teamvault.ParseImportCSV(ctx, file, mapping)
+	case "json":
+		return teamvault.ParseImportJSON(ctx, file)
+	case "keepass":
+		return teamvault.ParseKeePassXML(ctx, file)
+	default:
+		return nil, errors.Errorf(
+			ctx,
+			"unknown format %q (%s)",
+			format,
+			strings.Join(importFormats, ", "),
+		)
+	}
+}
+
+// writeImportResults prints one line per record followed by a summary.
+func writeImportResults(
+	ctx context.Context,
+	out io.Writer,
+	results []teamvault.ImportResult,
+) error {
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ROW\tNAME\tSTATUS\tKEY\tREASON")
+	counts := map[teamvault.ImportStatus]int{}
+	for _, result := range results {
+		counts[result.Status]++
+		fmt.Fprintf(
+			tw,
+			"%d\t%s\t%s\t%s\t%s\n",
+			result.Record.Row,
+			result.Record.Name,
+			result.Status,
+			result.Key,
+			result.Reason,
+		)
+	}
+	if err := tw.Flush(); err != nil {
+		return errors.Wrapf(ctx, err, "flush results failed")
+	}
+	var summary []string
+	for _, status := range []teamvault.ImportStatus{
+		teamvault.ImportCreated,
+		teamvault.ImportWouldCreate,
+		teamvault.ImportDone,
+		teamvault.ImportDuplicate,
+		teamvault.ImportInvalid,
+		teamvault.ImportFailed,
+		teamvault.ImportPending,
+	} {
+		if counts[status] > 0 {
+			summary = append(summary, fmt.Sprintf("%d %s", counts[status], status))
+		}
+	}
+	if len(summary) == 0 {
+		summary = []string{"nothing to import"}
+	}
+	if _, err := fmt.Fprintf(out, "Import: %s.\n", strings.Join(summary, ", ")); err != nil {
+		return errors.Wrapf(ctx, err, "write results failed")
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	stderrors "errors"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	teamvault "github.com/Seibert-Data/teamvault-cli/v5/pkg"
+	"github.com/Seibert-Data/teamvault-cli/v5/pkg/cli"
+	"github.com/Seibert-Data/teamvault-cli/v5/pkg/mocks"
+)
+
+var _ = Describe("import", func() {
+	var ctx context.Context
+	var fakeConn *mocks.Connector
+	var fakeWriter *mocks.Writer
+	var input string
+	var stdout bytes.Buffer
+	var resets []func()
+
+	run := func(args ...string) error {
+		stdout.Reset()
+		cmd := cli.NewRootCommand(ctx)
+		cmd.SetArgs(append([]string{"import", input}, args...))
+		cmd.SetOut(&stdout)
+		cmd.SetErr(&bytes.Buffer{})
+		return cmd.Execute()
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		os.Setenv("STAGING", "true")
+		input = filepath.Join(GinkgoT().TempDir(), "export.csv")
+		content := "Title,Password\npayment-db,pw1\nexisting,pw2\nmail,pw3\n"
+		Expect(os.WriteFile(input, []byte(content), 0600)).To(Succeed())
+
+		fakeConn = &mocks.Connector{}
+		fakeConn.SearchStub = func(ctx context.Context, name string) ([]teamvault.SearchResult, error) {
+			if name == "existing" {
+				return []teamvault.SearchResult{{Key: "ExI123", Name: "existing"}}, nil
+			}
+			return nil, nil
+		}
+		fakeWriter = &mocks.Writer{}
+		fakeWriter.CreateReturns("NeW456", "", nil)
+		resets = []func(){
+			cli.SetNewConnectorForTest(
+				func(sf *cli.SharedFlags) func(context.Context) (teamvault.Connector, error) {
+					return func(ctx context.Context) (teamvault.Connector, error) {
+						return fakeConn, nil
+					}
+				},
+			),
+			cli.SetNewWriterForTest(
+				func(sf *cli.SharedFlags) func(context.Context) (teamvault.Writer, error) {
+					return func(ctx context.Context) (teamvault.Writer, error) {
+						return fakeWriter, nil
+					}
+				},
+			),
+		}
+	})
+
+	AfterEach(func() {
+		for _, reset := range resets {
+			reset()
+		}
+		os.Unsetenv("STAGING")
+	})
+
+	It("reports what a dry run would create", func() {
+		Expect(run("--columns", "name=Title", "--dry-run")).To(Succeed())
+
+		Expect(stdout.String()).To(MatchRegexp(`1 +payment-db +would create`))
+		Expect(stdout.String()).
+			To(MatchRegexp(`2 +existing +duplicate +ExI123 +exists in TeamVault`))
+		Expect(stdout.String()).To(ContainSubstring("Import: 2 would create, 1 duplicate."))
+		Expect(fakeWriter.Invocations()).To(BeEmpty())
+	})
+
+	It("resumes after a failure without creating secrets twice", func() {
+		fakeWriter.CreateReturnsOnCall(1, "", "", stderrors.New("boom"))
+
+		err := run("--columns", "name=Title")
+		Expect(err).NotTo(BeNil())
+		Expect(err.Error()).To(ContainSubstring("re-run to resume"))
+		Expect(stdout.String()).To(MatchRegexp(`3 +mail +failed`))
+		state, err := teamvault.ImportStatePath(input + ".import-state.json").Read(ctx)
+		Expect(err).To(BeNil())
+		Expect(state).To(Equal(teamvault.ImportState{"payment-db": "NeW456"}))
+
+		Expect(run("--columns", "name=Title")).To(Succeed())
+		Expect(stdout.String()).To(MatchRegexp(`1 +payment-db +done +NeW456`))
+		Expect(stdout.String()).To(MatchRegexp(`3 +mail +created +NeW456`))
+		Expect(fakeWriter.CreateCallCount()).To(Equal(3))
+	})
+})
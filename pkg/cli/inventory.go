@@ -0,0 +1,216 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/bborbe/errors"
+	"github.com/spf13/cobra"
+
+	teamvault "github.com/Seibert-Data/teamvault-cli/v5/pkg"
+)
+
+// inventoryFlags are the manifest and lock file locations shared by the
+// inventory subcommands.
+type inventoryFlags struct {
+	file     string
+	lockFile string
+}
+
+func (f *inventoryFlags) paths() (teamvault.InventoryPath, teamvault.InventoryLockPath) {
+	path := teamvault.InventoryPath(f.file)
+	if f.lockFile != "" {
+		return path, teamvault.InventoryLockPath(f.lockFile)
+	}
+	return path, path.LockPath()
+}
+
+// createInventoryCommand creates the inventory parent command.
+func createInventoryCommand(ctx context.Context, sf *SharedFlags) *cobra.Command {
+	flags := &inventoryFlags{}
+	cmd := &cobra.Command{
+		Use:   "inventory",
+		Short: "Converge TeamVault to a declarative secret manifest",
+		Long: `Converge TeamVault to a declarative secret manifest.
+
+The manifest lists the secrets a service needs:
+
+  secrets:
+    - name: payment-db
+      username: payment
+      url: postgres://db.example.com/payment
+      generate: true            # create with a generated password if missing
+    - name: payment-tls
+      content_type: file
+      source_file: certs/tls.pem  # relative to the manifest
+
+plan shows what apply would change. apply creates missing secrets, updates
+declared fields that differ and records every key in the lock file, which
+identifies the secrets from then on. Existing passwords are never changed,
+and secrets removed from the manifest are left in TeamVault.`,
+	}
+	cmd.PersistentFlags().
+		StringVarP(&flags.file, "file", "f", teamvault.DefaultInventoryPath, "inventory manifest")
+	cmd.PersistentFlags().StringVar(
+		&flags.lockFile,
+		"lock-file",
+		"",
+		"lock file (default: the manifest's name with .lock.yaml)",
+	)
+	cmd.AddCommand(createInventoryPlanCommand(ctx, sf, flags))
+	cmd.AddCommand(createInventoryApplyCommand(ctx, sf, flags))
+	return cmd
+}
+
+// planInventory reads manifest and lock and plans against TeamVault.
+func planInventory(
+	ctx context.Context,
+	sf *SharedFlags,
+	flags *inventoryFlags,
+) (teamvault.InventoryPlan, teamvault.InventoryLockPath, error) {
+	path, lockPath := flags.paths()
+	inventory, err := path.Read(ctx)
+	if err != nil {
+		return teamvault.InventoryPlan{}, "", err
+	}
+	lock, err := lockPath.Read(ctx)
+	if err != nil {
+		return teamvault.InventoryPlan{}, "", err
+	}
+	conn, err := newConnector(sf)(ctx)
+	if err != nil {
+		return teamvault.InventoryPlan{}, "", errors.Wrap(ctx, err, "create connector failed")
+	}
+	plan, err := teamvault.NewInventoryPlanner(conn).Plan(ctx, inventory, lock)
+	if err != nil {
+		return teamvault.InventoryPlan{}, "", err
+	}
+	return plan, lockPath, nil
+}
+
+// createInventoryPlanCommand creates the inventory plan subcommand.
+func createInventoryPlanCommand(
+	ctx context.Context,
+	sf *SharedFlags,
+	flags *inventoryFlags,
+) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "plan",
+		Short: "Show what apply would change",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			plan, _, err := planInventory(ctx, sf, flags)
+			if err != nil {
+				return err
+			}
+			return writeInventoryPlan(ctx, cmd.OutOrStdout(), plan)
+		},
+	}
+	return cmd
+}
+
+// createInventoryApplyCommand creates the inventory apply subcommand.
+func createInventoryApplyCommand(
+	ctx context.Context,
+	sf *SharedFlags,
+	flags *inventoryFlags,
+) *cobra.Command {
+	var autoApprove bool
+
+	cmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Create and update secrets to match the manifest and write the lock file",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			plan, lockPath, err := planInventory(ctx, sf, flags)
+			if err != nil {
+				return err
+			}
+			if err := writeInventoryPlan(ctx, cmd.OutOrStdout(), plan); err != nil {
+				return err
+			}
+			if plan.HasChanges() && !autoApprove {
+				fmt.Fprint(cmd.ErrOrStderr(), "Apply these changes? [y/N] ")
+				answer, _ := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+				if answer = strings.ToLower(strings.TrimSpace(answer)); answer != "y" &&
+					answer != "yes" {
+					return errors.New(ctx, "apply cancelled")
+				}
+			}
+			var writer teamvault.Writer
+			if plan.HasChanges() {
+				if writer, err = newWriter(sf)(ctx); err != nil {
+					return errors.Wrap(ctx, err, "create writer failed")
+				}
+			}
+			lock, applyErr := teamvault.NewInventoryApplier(writer).Apply(ctx, plan)
+			// Record every key known so far, also on failure, so
+			// a re-run does not create the same secrets again.
+			if err := lockPath.Write(ctx, lock); err != nil {
+				return err
+			}
+			if applyErr != nil {
+				return applyErr
+			}
+			fmt.Fprintf(
+				cmd.ErrOrStderr(),
+				"Apply complete: %d created, %d updated, %d unchanged. Keys written to %s.\n",
+				plan.Count(teamvault.InventoryCreate),
+				plan.Count(teamvault.InventoryUpdate),
+				plan.Count(teamvault.InventoryNoop),
+				lockPath,
+			)
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&autoApprove, "auto-approve", false, "apply without asking")
+	return cmd
+}
+
+// writeInventoryPlan prints one line per secret, marked + (create),
+// ~ (update) or blank (unchanged), followed by a summary.
+func writeInventoryPlan(ctx context.Context, out io.Writer, plan teamvault.InventoryPlan) error {
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	for _, change := range plan.Changes {
+		switch change.Action {
+		case teamvault.InventoryCreate:
+			fmt.Fprintf(
+				tw,
+				"+\t%s\tcreate\t\t%s\n",
+				change.Secret.Name,
+				strings.Join(change.Fields, ", "),
+			)
+		case teamvault.InventoryUpdate:
+			fmt.Fprintf(
+				tw,
+				"~\t%s\tupdate\t%s\t%s\n",
+				change.Secret.Name,
+				change.Key,
+				strings.Join(change.Fields, ", "),
+			)
+		default:
+			fmt.Fprintf(tw, " \t%s\tunchanged\t%s\t\n", change.Secret.Name, change.Key)
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return errors.Wrapf(ctx, err, "flush plan failed")
+	}
+	if _, err := fmt.Fprintf(
+		out,
+		"Plan: %d to create, %d to update, %d unchanged.\n",
+		plan.Count(teamvault.InventoryCreate),
+		plan.Count(teamvault.InventoryUpdate),
+		plan.Count(teamvault.InventoryNoop),
+	); err != nil {
+		return errors.Wrapf(ctx, err, "write plan failed")
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	teamvault "github.com/Seibert-Data/teamvault-cli/v5/pkg"
+	"github.com/Seibert-Data/teamvault-cli/v5/pkg/cli"
+	"github.com/Seibert-Data/teamvault-cli/v5/pkg/mocks"
+)
+
+var _ = Describe("inventory", func() {
+	var ctx context.Context
+	var fakeConn metadataConnector
+	var fakeWriter *mocks.Writer
+	var manifest string
+	var stdout bytes.Buffer
+	var resets []func()
+
+	run := func(stdin string, args ...string) error {
+		stdout.Reset()
+		cmd := cli.NewRootCommand(ctx)
+		cmd.SetArgs(append(append([]string{"inventory"}, args...), "-f", manifest))
+		cmd.SetIn(strings.NewReader(stdin))
+		cmd.SetOut(&stdout)
+		cmd.SetErr(&bytes.Buffer{})
+		return cmd.Execute()
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		os.Setenv("STAGING", "true")
+		manifest = filepath.Join(GinkgoT().TempDir(), "teamvault-inventory.yaml")
+		Expect(os.WriteFile(manifest, []byte(`
+secrets:
+  - name: payment-db
+    description: primary db
+  - name: payment-api
+    username: api
+    generate: true
+`), 0600)).To(Succeed())
+
+		fakeConn = metadataConnector{
+			Connector:      &mocks.Connector{},
+			MetadataReader: &mocks.MetadataReader{},
+		}
+		fakeConn.SearchStub = func(ctx context.Context, name string) ([]teamvault.SearchResult, error) {
+			if name == "payment-db" {
+				return []teamvault.SearchResult{{Key: "AbC123", Name: "payment-db"}}, nil
+			}
+			return nil, nil
+		}
+		fakeConn.MetadataReader.MetadataReturns(teamvault.SecretMetadata{
+			Key:         "AbC123",
+			Name:        "payment-db",
+			Description: "old",
+			ContentType: teamvault.ContentTypePassword,
+		}, nil)
+		fakeWriter = &mocks.Writer{}
+		fakeWriter.GeneratePasswordReturns("generated", nil)
+		fakeWriter.CreateReturns("NeW456", "", nil)
+		fakeWriter.UpdateReturns("AbC123", "", nil)
+		resets = []func(){
+			cli.SetNewConnectorForTest(
+				func(sf *cli.SharedFlags) func(context.Context) (teamvault.Connector, error) {
+					return func(ctx context.Context) (teamvault.Connector, error) {
+						return fakeConn, nil
+					}
+				},
+			),
+			cli.SetNewWriterForTest(
+				func(sf *cli.SharedFlags) func(context.Context) (teamvault.Writer, error) {
+					return func(ctx context.Context) (teamvault.Writer, error) {
+						return fakeWriter, nil
+					}
+				},
+			),
+		}
+	})
+
+	AfterEach(func() {
+		for _, reset := range resets {
+			reset()
+		}
+		os.Unsetenv("STAGING")
+	})
+
+	It("plans without writing", func() {
+		Expect(run("", "plan")).To(Succeed())
+
+		Expect(stdout.String()).To(MatchRegexp(`~ +payment-db +update +AbC123 +description`))
+		Expect(stdout.String()).To(MatchRegexp(`\+ +payment-api +create +name, username, password`))
+		Expect(stdout.String()).To(ContainSubstring("Plan: 1 to create, 1 to update, 0 unchanged."))
+		Expect(fakeWriter.Invocations()).To(BeEmpty())
+		_, err := os.Stat(strings.TrimSuffix(manifest, ".yaml") + ".lock.yaml")
+		Expect(os.IsNotExist(err)).To(BeTrue())
+	})
+
+	It("applies and writes the lock file", func() {
+		Expect(run("", "apply", "--auto-approve")).To(Succeed())
+
+		Expect(fakeWriter.UpdateCallCount()).To(Equal(1))
+		Expect(fakeWriter.CreateCallCount()).To(Equal(1))
+		lock, err := teamvault.InventoryPath(manifest).LockPath().Read(ctx)
+		Expect(err).To(BeNil())
+		Expect(lock).To(Equal(teamvault.InventoryLock{
+			"payment-db":  "AbC123",
+			"payment-api": "NeW456",
+		}))
+	})
+
+	It("asks before applying", func() {
+		err := run("n\n", "apply")
+
+		Expect(err).NotTo(BeNil())
+		Expect(err.Error()).To(ContainSubstring("apply cancelled"))
+		Expect(fakeWriter.Invocations()).To(BeEmpty())
+
+		Expect(run("y\n", "apply")).To(Succeed())
+		Expect(fakeWriter.CreateCallCount()).To(Equal(1))
+	})
+})
@@ -0,0 +1,216 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/bborbe/errors"
+	"github.com/spf13/cobra"
+
+	teamvault "github.com/Seibert-Data/teamvault-cli/v5/pkg"
+)
+
+// createMirrorCommand creates the mirror command.
+func createMirrorCommand(ctx context.Context, sf *SharedFlags) *cobra.Command {
+	var targetConfig string
+	var search string
+	var fromAliases bool
+	var keyMapFile string
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "mirror [KEY...] --target-config <FILE>",
+		Short: "Copy secrets to another TeamVault",
+		Long: `Copy secrets to another TeamVault.
+
+The source is the TeamVault of the usual flags and config file; the target
+is the one configured in --target-config. Secrets are selected by key (or
+alias), by --search, or with --aliases by every key in the alias files.
+
+Name, username, url, description and value are copied. The key map file
+records each source key with its target key and the source revision
+copied; re-runs update only secrets whose source revision changed. A
+secret whose name already exists in the target is reported as conflict;
+add it to the key map to mirror into that secret.`,
+		Args:              cobra.ArbitraryArgs,
+		ValidArgsFunction: completeKeyArgs(ctx, sf),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			selections := 0
+			for _, selected := range []bool{len(args) > 0, search != "", fromAliases} {
+				if selected {
+					selections++
+				}
+			}
+			if selections != 1 {
+				return errors.New(ctx, "select secrets by key, --search or --aliases (exactly one)")
+			}
+			if targetConfig == "" {
+				return errors.New(ctx, "--target-config is required")
+			}
+
+			// Neither side uses the disk cache: values would be written to it
+			// unencrypted, and a stale cached source value could be copied
+			// and recorded as the current revision.
+			source, err := newConnector(sf.withoutCache())(ctx)
+			if err != nil {
+				return errors.Wrap(ctx, err, "create source connector failed")
+			}
+			keys, err := mirrorKeys(ctx, sf, source, args, search, fromAliases)
+			if err != nil {
+				return err
+			}
+			targetFlags := &SharedFlags{
+				configPath: targetConfig,
+				staging:    sf.staging,
+				timeout:    sf.timeout,
+				noCache:    true,
+			}
+			target, err := newConnector(targetFlags)(ctx)
+			if err != nil {
+				return errors.Wrap(ctx, err, "create target connector failed")
+			}
+			var targetWriter teamvault.Writer
+			if !dryRun {
+				if targetWriter, err = newWriter(targetFlags)(ctx); err != nil {
+					return errors.Wrap(ctx, err, "create target writer failed")
+				}
+			}
+
+			keyMapPath := teamvault.MirrorKeyMapPath(keyMapFile)
+			keyMap, err := keyMapPath.Read(ctx)
+			if err != nil {
+				return err
+			}
+			results, mirrorErr := teamvault.NewMirror(source, target, targetWriter).Mirror(
+				ctx,
+				keys,
+				teamvault.MirrorOptions{
+					DryRun:     dryRun,
+					KeyMap:     keyMap,
+					OnMirrored: keyMapPath.Write,
+				},
+			)
+			if err := writeMirrorResults(ctx, cmd.OutOrStdout(), results, dryRun); err != nil {
+				return err
+			}
+			return mirrorErr
+		},
+	}
+	cmd.Flags().StringVar(&targetConfig, "target-config", "", "config file of the target TeamVault")
+	cmd.Flags().StringVar(&search, "search", "", "mirror the secrets matching this search")
+	cmd.Flags().BoolVar(&fromAliases, "aliases", false, "mirror every aliased secret")
+	cmd.Flags().StringVar(
+		&keyMapFile,
+		"key-map",
+		teamvault.DefaultMirrorKeyMapPath,
+		"file mapping source keys to target keys and revisions",
+	)
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "show what would be copied without writing")
+	return cmd
+}
+
+// mirrorKeys returns the selected source keys, without duplicates.
+func mirrorKeys(
+	ctx context.Context,
+	sf *SharedFlags,
+	source teamvault.Connector,
+	args []string,
+	search string,
+	fromAliases bool,
+) ([]teamvault.Key, error) {
+	var keys []teamvault.Key
+	switch {
+	case search != "":
+		for result, err := range teamvault.SearchSeq(ctx, source, teamvault.SearchQuery{Name: search}) {
+			if err != nil {
+				return nil, errors.Wrap(ctx, err, "search failed")
+			}
+			keys = append(keys, result.Key)
+		}
+	case fromAliases:
+		aliases, err := sf.readAliases(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range aliases.merged() {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	default:
+		keyResolver, err := sf.keyResolver(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, arg := range args {
+			key, err := keyResolver.ResolveKey(ctx, arg)
+			if err != nil {
+				return nil, err
+			}
+			keys = append(keys, key)
+		}
+	}
+	seen := make(map[teamvault.Key]bool, len(keys))
+	unique := keys[:0]
+	for _, key := range keys {
+		if !seen[key] {
+			seen[key] = true
+			unique = append(unique, key)
+		}
+	}
+	return unique, nil
+}
+
+// writeMirrorResults prints one line per secret followed by a summary.
+func writeMirrorResults(
+	ctx context.Context,
+	out io.Writer,
+	results []teamvault.MirrorResult,
+	dryRun bool,
+) error {
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SOURCE KEY\tNAME\tACTION\tTARGET KEY\tREASON")
+	counts := map[teamvault.MirrorAction]int{}
+	for _, result := range results {
+		counts[result.Action]++
+		action := string(result.Action)
+		if dryRun && (result.Action == teamvault.MirrorCreate ||
+			result.Action == teamvault.MirrorUpdate) {
+			action = "would " + action
+		}
+		fmt.Fprintf(
+			tw,
+			"%s\t%s\t%s\t%s\t%s\n",
+			result.SourceKey,
+			result.Name,
+			action,
+			result.TargetKey,
+			result.Reason,
+		)
+	}
+	if err := tw.Flush(); err != nil {
+		return errors.Wrapf(ctx, err, "flush results failed")
+	}
+	format := "Mirror: %d created, %d updated, %d unchanged, %d conflicts, %d failed.\n"
+	if dryRun {
+		format = "Mirror (dry run): %d to create, %d to update, %d unchanged, %d conflicts, %d failed.\n"
+	}
+	if _, err := fmt.Fprintf(
+		out,
+		format,
+		counts[teamvault.MirrorCreate],
+		counts[teamvault.MirrorUpdate],
+		counts[teamvault.MirrorUnchanged],
+		counts[teamvault.MirrorConflict],
+		counts[teamvault.MirrorFailed],
+	); err != nil {
+		return errors.Wrapf(ctx, err, "write results failed")
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	teamvault "github.com/Seibert-Data/teamvault-cli/v5/pkg"
+	"github.com/Seibert-Data/teamvault-cli/v5/pkg/cli"
+	"github.com/Seibert-Data/teamvault-cli/v5/pkg/mocks"
+)
+
+var _ = Describe("mirror", func() {
+	var ctx context.Context
+	var source metadataConnector
+	var target *mocks.Connector
+	var targetWriter *mocks.Writer
+	var targetConfig string
+	var keyMapFile string
+	var stdout bytes.Buffer
+	var uncached []bool
+	var resets []func()
+
+	run := func(args ...string) error {
+		stdout.Reset()
+		cmd := cli.NewRootCommand(ctx)
+		cmd.SetArgs(append(
+			append([]string{"mirror"}, args...),
+			"--target-config", targetConfig,
+			"--key-map", keyMapFile,
+		))
+		cmd.SetOut(&stdout)
+		cmd.SetErr(&bytes.Buffer{})
+		return cmd.Execute()
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		os.Setenv("STAGING", "true")
+		dir := GinkgoT().TempDir()
+		targetConfig = filepath.Join(dir, "target.json")
+		keyMapFile = filepath.Join(dir, "teamvault-mirror.json")
+
+		source = metadataConnector{
+			Connector:      &mocks.Connector{},
+			MetadataReader: &mocks.MetadataReader{},
+		}
+		source.SearchReturns([]teamvault.SearchResult{{Key: "AbC123", Name: "payment-db"}}, nil)
+		source.MetadataReader.MetadataReturns(teamvault.SecretMetadata{
+			Key:             "AbC123",
+			Name:            "payment-db",
+			ContentType:     teamvault.ContentTypePassword,
+			CurrentRevision: "rev1",
+		}, nil)
+		source.PasswordReturns("s3cret", nil)
+		target = &mocks.Connector{}
+		targetWriter = &mocks.Writer{}
+		targetWriter.CreateReturns("NeW456", "", nil)
+		uncached = nil
+		resets = []func(){
+			cli.SetNewConnectorForTest(
+				func(sf *cli.SharedFlags) func(context.Context) (teamvault.Connector, error) {
+					uncached = append(uncached, sf.Uncached())
+					return func(ctx context.Context) (teamvault.Connector, error) {
+						if sf.ConfigPath() == targetConfig {
+							return target, nil
+						}
+						return source, nil
+					}
+				},
+			),
+			cli.SetNewWriterForTest(
+				func(sf *cli.SharedFlags) func(context.Context) (teamvault.Writer, error) {
+					return func(ctx context.Context) (teamvault.Writer, error) {
+						Expect(sf.ConfigPath()).To(Equal(targetConfig))
+						return targetWriter, nil
+					}
+				},
+			),
+		}
+	})
+
+	AfterEach(func() {
+		for _, reset := range resets {
+			reset()
+		}
+		os.Unsetenv("STAGING")
+	})
+
+	It("reports what a dry run would copy", func() {
+		Expect(run("--search", "payment", "--dry-run")).To(Succeed())
+
+		Expect(stdout.String()).To(MatchRegexp(`AbC123 +payment-db +would create`))
+		Expect(stdout.String()).To(ContainSubstring("Mirror (dry run): 1 to create"))
+		Expect(targetWriter.Invocations()).To(BeEmpty())
+		_, err := os.Stat(keyMapFile)
+		Expect(os.IsNotExist(err)).To(BeTrue())
+	})
+
+	It("copies to the target and skips unchanged secrets on re-runs", func() {
+		Expect(run("AbC123")).To(Succeed())
+		Expect(stdout.String()).To(MatchRegexp(`AbC123 +payment-db +create +NeW456`))
+		keyMap, err := teamvault.MirrorKeyMapPath(keyMapFile).Read(ctx)
+		Expect(err).To(BeNil())
+		Expect(keyMap).To(Equal(teamvault.MirrorKeyMap{
+			"AbC123": {Key: "NeW456", Revision: "rev1"},
+		}))
+
+		Expect(run("AbC123")).To(Succeed())
+		Expect(stdout.String()).To(MatchRegexp(`AbC123 +payment-db +unchanged +NeW456`))
+		Expect(targetWriter.CreateCallCount()).To(Equal(1))
+	})
+
+	It("reads and writes without the disk cache", func() {
+		Expect(run("AbC123", "--cache")).To(Succeed())
+
+		Expect(uncached).To(Equal([]bool{true, true}))
+	})
+
+	It("requires exactly one selection", func() {
+		err := run("AbC123", "--search", "payment")
+		Expect(err).NotTo(BeNil())
+		Expect(err.Error()).To(ContainSubstring("exactly one"))
+	})
+})
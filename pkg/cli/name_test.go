@@ -0,0 +1,137 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	teamvault "github.com/Seibert-Data/teamvault-cli/v5/pkg"
+	"github.com/Seibert-Data/teamvault-cli/v5/pkg/cli"
+	"github.com/Seibert-Data/teamvault-cli/v5/pkg/mocks"
+)
+
+var _ = Describe("--name", func() {
+	var ctx context.Context
+	var fakeConn *mocks.Connector
+	var outBuf bytes.Buffer
+	var resetConnector func()
+
+	execute := func(args ...string) error {
+		outBuf.Reset()
+		cmd := cli.NewRootCommand(ctx)
+		cmd.SetArgs(args)
+		cmd.SetOut(&outBuf)
+		cmd.SetErr(&bytes.Buffer{})
+		return cmd.Execute()
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		os.Setenv("STAGING", "true")
+		fakeConn = &mocks.Connector{}
+		fakeConn.SearchReturns([]teamvault.SearchResult{
+			{Key: "AbC123", Name: "prod postgres admin"},
+			{Key: "XyZ789", Name: "staging postgres admin"},
+		}, nil)
+		fakeConn.PasswordStub = func(ctx context.Context, key teamvault.Key) (teamvault.Password, error) {
+			return teamvault.Password("pass-" + key.String()), nil
+		}
+		fakeConn.UserReturns("AKIAEXAMPLE", nil)
+		resetConnector = cli.SetNewConnectorForTest(
+			func(sf *cli.SharedFlags) func(context.Context) (teamvault.Connector, error) {
+				return func(ctx context.Context) (teamvault.Connector, error) {
+					return fakeConn, nil
+				}
+			},
+		)
+	})
+
+	AfterEach(func() {
+		resetConnector()
+		os.Unsetenv("STAGING")
+	})
+
+	It("resolves an exact name", func() {
+		Expect(execute("aws-credential-process", "--name", "prod postgres admin")).To(Succeed())
+
+		Expect(outBuf.String()).To(ContainSubstring(`"SecretAccessKey":"pass-AbC123"`))
+	})
+
+	It("fails with the candidates when there is no exact match", func() {
+		err := execute("aws-credential-process", "--name", "postgres admin")
+
+		Expect(err).To(HaveOccurred())
+		Expect(
+			err.Error(),
+		).To(ContainSubstring("AbC123 (prod postgres admin), XyZ789 (staging postgres admin)"))
+		Expect(fakeConn.PasswordCallCount()).To(Equal(0))
+	})
+
+	It("accepts a single close match with --fuzzy", func() {
+		Expect(
+			execute("aws-credential-process", "--name", "Prod-Postgres Admin", "--fuzzy"),
+		).To(Succeed())
+
+		Expect(outBuf.String()).To(ContainSubstring(`"SecretAccessKey":"pass-AbC123"`))
+	})
+
+	It("rejects a key together with --name", func() {
+		Expect(execute("aws-credential-process", "AbC123", "--name", "prod postgres admin")).
+			To(HaveOccurred())
+		Expect(fakeConn.SearchCallCount()).To(Equal(0))
+	})
+
+	Context("with a secret url as key", func() {
+		var configPath string
+
+		BeforeEach(func() {
+			os.Unsetenv("STAGING")
+			configPath = filepath.Join(GinkgoT().TempDir(), "config.json")
+			Expect(os.WriteFile(
+				configPath,
+				[]byte(`{"url": "https://vault.example.com", "user": "u", "pass": "p"}`),
+				0600,
+			)).To(Succeed())
+		})
+
+		It("reads the secret the url points at", func() {
+			Expect(execute(
+				"aws-credential-process",
+				"https://vault.example.com/secrets/AbC123/",
+				"--teamvault-config", configPath,
+			)).To(Succeed())
+
+			Expect(outBuf.String()).To(ContainSubstring(`"SecretAccessKey":"pass-AbC123"`))
+		})
+
+		It("rejects a url of another vault", func() {
+			err := execute(
+				"aws-credential-process",
+				"https://other.example.com/secrets/AbC123/",
+				"--teamvault-config", configPath,
+			)
+
+			Expect(errors.Is(err, teamvault.ErrKeyHostMismatch)).To(BeTrue())
+			Expect(fakeConn.PasswordCallCount()).To(Equal(0))
+		})
+	})
+
+	It("is offered by the read commands", func() {
+		for _, name := range []string{"password", "username", "url", "file", "info", "htpasswd", "otp", "qr", "ssh-add"} {
+			cmd := cli.NewRootCommand(ctx)
+			sub, _, err := cmd.Find([]string{name})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sub.Flags().Lookup("name")).NotTo(BeNil(), name)
+			Expect(sub.Flags().Lookup("fuzzy")).NotTo(BeNil(), name)
+		}
+	})
+})
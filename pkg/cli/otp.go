@@ -0,0 +1,99 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/bborbe/errors"
+	libtime "github.com/bborbe/time"
+	"github.com/spf13/cobra"
+
+	teamvault "github.com/Seibert-Data/teamvault-cli/v5/pkg"
+)
+
+// newCurrentDateTime is a seam for the clock TOTP codes are computed
+// against and backups are stamped with. Overridden by tests via
+// SetCurrentDateTimeForTest.
+var newCurrentDateTime = func() libtime.CurrentDateTimeGetter {
+	return libtime.NewCurrentDateTime()
+}
+
+// SetCurrentDateTimeForTest pins the clock used for TOTP codes and backups.
+// Returns a function to call in AfterEach to reset.
+func SetCurrentDateTimeForTest(currentDateTime libtime.CurrentDateTimeGetter) func() {
+	prev := newCurrentDateTime
+	newCurrentDateTime = func() libtime.CurrentDateTimeGetter { return currentDateTime }
+	return func() { newCurrentDateTime = prev }
+}
+
+// createOtpCommand builds the `otp` subcommand, which prints the current
+// TOTP code for a seed stored as an otpauth:// URI (or raw base32) in the
+// secret's password or file. The code alone goes to stdout so it composes
+// in command substitution; --remaining adds the validity to stderr.
+func createOtpCommand(ctx context.Context, sf *SharedFlags) *cobra.Command {
+	var remaining bool
+	var asJSON bool
+
+	cmd := &cobra.Command{
+		Use:               "otp [key]",
+		Short:             "Print the current TOTP code for a TeamVault secret",
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeKeyArg(ctx, sf),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key, err := resolveKey(cmd, args)
+			if err != nil {
+				return err
+			}
+			conn, err := newConnector(sf)(ctx)
+			if err != nil {
+				return errors.Wrap(ctx, err, "create connector failed")
+			}
+			key, err = lookupKey(ctx, cmd, sf, conn, key)
+			if err != nil {
+				return err
+			}
+			totp, err := teamvault.NewTotpGenerator(conn, newCurrentDateTime()).
+				Generate(ctx, key)
+			if err != nil {
+				return errors.Wrap(ctx, err, "generate totp failed")
+			}
+			seconds := int(totp.Remaining / time.Second)
+			if asJSON {
+				encoded, err := json.Marshal(map[string]any{
+					"code":      totp.Code,
+					"remaining": seconds,
+				})
+				if err != nil {
+					return errors.Wrapf(ctx, err, "marshal json failed")
+				}
+				if _, err := fmt.Fprintf(cmd.OutOrStdout(), "%s\n", encoded); err != nil {
+					return errors.Wrapf(ctx, err, "write totp failed")
+				}
+				return nil
+			}
+			if _, err := fmt.Fprintln(cmd.OutOrStdout(), totp.Code); err != nil {
+				return errors.Wrapf(ctx, err, "write totp failed")
+			}
+			if remaining {
+				fmt.Fprintf(cmd.ErrOrStderr(), "valid for %ds\n", seconds)
+			}
+			return nil
+		},
+	}
+
+	var key string
+	cmd.Flags().
+		StringVar(&key, "teamvault-key", "", "teamvault key (alternative to positional argument)")
+	cmd.Flags().
+		BoolVar(&remaining, "remaining", false, "print the seconds the code stays valid to stderr")
+	cmd.Flags().
+		BoolVar(&asJSON, "json", false, `print output as a JSON object ({"code":"...","remaining":<seconds>})`)
+
+	return cmd
+}
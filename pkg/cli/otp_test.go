@@ -0,0 +1,101 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"time"
+
+	libtime "github.com/bborbe/time"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	teamvault "github.com/Seibert-Data/teamvault-cli/v5/pkg"
+	"github.com/Seibert-Data/teamvault-cli/v5/pkg/cli"
+	"github.com/Seibert-Data/teamvault-cli/v5/pkg/mocks"
+)
+
+var _ = Describe("otp", func() {
+	var ctx context.Context
+	var fakeConn *mocks.Connector
+	var outBuf bytes.Buffer
+	var errBuf bytes.Buffer
+	var resets []func()
+
+	execute := func(args ...string) error {
+		outBuf.Reset()
+		errBuf.Reset()
+		cmd := cli.NewRootCommand(ctx)
+		cmd.SetArgs(append([]string{"otp"}, args...))
+		cmd.SetOut(&outBuf)
+		cmd.SetErr(&errBuf)
+		return cmd.Execute()
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		os.Setenv("STAGING", "true")
+		fakeConn = &mocks.Connector{}
+		fakeConn.PasswordReturns(
+			teamvault.Password(
+				"otpauth://totp/Acme:admin?secret=GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ&issuer=Acme",
+			),
+			nil,
+		)
+		resets = []func(){
+			cli.SetNewConnectorForTest(
+				func(sf *cli.SharedFlags) func(context.Context) (teamvault.Connector, error) {
+					return func(ctx context.Context) (teamvault.Connector, error) {
+						return fakeConn, nil
+					}
+				},
+			),
+			cli.SetCurrentDateTimeForTest(
+				libtime.CurrentDateTimeGetterFunc(func() libtime.DateTime {
+					return libtime.DateTime(time.Unix(1111111109, 0))
+				}),
+			),
+		}
+	})
+
+	AfterEach(func() {
+		for _, reset := range resets {
+			reset()
+		}
+		os.Unsetenv("STAGING")
+	})
+
+	It("prints the current code", func() {
+		Expect(execute("TOTP01")).To(Succeed())
+
+		Expect(outBuf.String()).To(Equal("081804\n"))
+		Expect(errBuf.String()).To(BeEmpty())
+		_, key := fakeConn.PasswordArgsForCall(0)
+		Expect(key).To(Equal(teamvault.Key("TOTP01")))
+	})
+
+	It("prints the remaining seconds to stderr", func() {
+		Expect(execute("TOTP01", "--remaining")).To(Succeed())
+
+		Expect(outBuf.String()).To(Equal("081804\n"))
+		Expect(errBuf.String()).To(Equal("valid for 1s\n"))
+	})
+
+	It("prints json", func() {
+		Expect(execute("TOTP01", "--json")).To(Succeed())
+
+		Expect(outBuf.String()).To(Equal(`{"code":"081804","remaining":1}` + "\n"))
+	})
+
+	It("fails when the secret holds no seed", func() {
+		fakeConn.PasswordReturns(teamvault.Password("hunter2"), nil)
+		fakeConn.FileReturns("", nil)
+
+		Expect(execute("TOTP01")).To(HaveOccurred())
+		Expect(outBuf.String()).To(BeEmpty())
+	})
+})
@@ -0,0 +1,236 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cli
+
+import (
+	"context"
+	"encoding/base32"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/bborbe/errors"
+	"github.com/spf13/cobra"
+	"rsc.io/qr"
+
+	teamvault "github.com/Seibert-Data/teamvault-cli/v5/pkg"
+)
+
+// qrQuietZone is the blank border, in modules, scanners need around a code.
+const qrQuietZone = 4
+
+// createQRCommand builds the `qr` subcommand, which renders a secret field
+// as a QR code in the terminal (or as a PNG) so values such as Wi-Fi
+// passwords or TOTP seeds reach a phone without passing through a website.
+func createQRCommand(ctx context.Context, sf *SharedFlags) *cobra.Command {
+	var (
+		field        string
+		format       string
+		pngPath      string
+		wifiSecurity string
+		wifiHidden   bool
+		label        string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "qr [key]",
+		Short: "Render a secret field as a QR code in the terminal or as PNG",
+		Long: `Render a secret field as a QR code in the terminal or as PNG.
+
+--format raw      encode the field as is (default)
+--format wifi     build a WIFI: payload, SSID = username, password = password
+--format otpauth  encode an otpauth:// URI; a raw base32 seed is wrapped into one`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeKeyArg(ctx, sf),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key, err := resolveKey(cmd, args)
+			if err != nil {
+				return err
+			}
+			conn, err := newConnector(sf)(ctx)
+			if err != nil {
+				return errors.Wrap(ctx, err, "create connector failed")
+			}
+			key, err = lookupKey(ctx, cmd, sf, conn, key)
+			if err != nil {
+				return err
+			}
+			var payload string
+			switch format {
+			case "raw":
+				payload, err = readQRField(ctx, conn, key, field)
+			case "wifi":
+				payload, err = wifiPayload(ctx, conn, key, wifiSecurity, wifiHidden)
+			case "otpauth":
+				if label == "" {
+					label = key.String()
+				}
+				payload, err = otpauthPayload(ctx, conn, key, field, label)
+			default:
+				return errors.Errorf(ctx, "unknown format %q (want raw, wifi or otpauth)", format)
+			}
+			if err != nil {
+				return err
+			}
+			code, err := qr.Encode(payload, qr.M)
+			if err != nil {
+				return errors.Wrapf(ctx, err, "encode qr code failed")
+			}
+			if pngPath != "" {
+				if err := os.WriteFile(pngPath, code.PNG(), 0600); err != nil {
+					return errors.Wrapf(ctx, err, "write png %s failed", pngPath)
+				}
+				return nil
+			}
+			return writeQRHalfBlocks(ctx, cmd.OutOrStdout(), code)
+		},
+	}
+
+	var teamvaultKey string
+	cmd.Flags().
+		StringVar(&teamvaultKey, "teamvault-key", "", "teamvault key (alternative to positional argument)")
+	cmd.Flags().StringVar(&field, "field", "password", "field to encode: password, url or file")
+	cmd.Flags().StringVar(&format, "format", "raw", "payload format: raw, wifi or otpauth")
+	cmd.Flags().StringVar(&pngPath, "png", "", "write a PNG to this path instead of printing")
+	cmd.Flags().
+		StringVar(&wifiSecurity, "wifi-security", "WPA", "wifi authentication: WPA, WEP or nopass")
+	cmd.Flags().BoolVar(&wifiHidden, "wifi-hidden", false, "mark the wifi network as hidden")
+	cmd.Flags().
+		StringVar(&label, "label", "", "account label when wrapping a raw seed into otpauth (default: key)")
+
+	return cmd
+}
+
+func readQRField(
+	ctx context.Context,
+	conn teamvault.Connector,
+	key teamvault.Key,
+	field string,
+) (string, error) {
+	switch field {
+	case "password", "url", "file":
+		return readSecretField(ctx, conn, key, field)
+	default:
+		return "", errors.Errorf(ctx, "unknown field %q (want password, url or file)", field)
+	}
+}
+
+// wifiPayload builds the WIFI: payload phone cameras join networks from.
+func wifiPayload(
+	ctx context.Context,
+	conn teamvault.Connector,
+	key teamvault.Key,
+	security string,
+	hidden bool,
+) (string, error) {
+	security = strings.ToUpper(security)
+	switch security {
+	case "WPA", "WEP":
+	case "NOPASS":
+		security = "nopass"
+	default:
+		return "", errors.Errorf(
+			ctx,
+			"unknown wifi security %q (want WPA, WEP or nopass)",
+			security,
+		)
+	}
+	ssid, err := conn.User(ctx, key)
+	if err != nil {
+		return "", errors.Wrap(ctx, err, "get user failed")
+	}
+	if ssid == "" {
+		return "", errors.Errorf(ctx, "secret %s needs the SSID as username", key)
+	}
+	var b strings.Builder
+	b.WriteString("WIFI:T:" + security + ";S:" + escapeWifi(ssid.String()) + ";")
+	if security != "nopass" {
+		pass, err := conn.Password(ctx, key)
+		if err != nil {
+			return "", errors.Wrap(ctx, err, "get password failed")
+		}
+		b.WriteString("P:" + escapeWifi(pass.String()) + ";")
+	}
+	if hidden {
+		b.WriteString("H:true;")
+	}
+	b.WriteString(";")
+	return b.String(), nil
+}
+
+// escapeWifi backslash-escapes the characters the WIFI: format reserves.
+func escapeWifi(value string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		`;`, `\;`,
+		`,`, `\,`,
+		`:`, `\:`,
+		`"`, `\"`,
+	).Replace(value)
+}
+
+// otpauthPayload returns the field when it already is a valid otpauth URI and
+// otherwise wraps a raw base32 seed into one, so authenticator apps import it.
+func otpauthPayload(
+	ctx context.Context,
+	conn teamvault.Connector,
+	key teamvault.Key,
+	field string,
+	label string,
+) (string, error) {
+	value, err := readQRField(ctx, conn, key, field)
+	if err != nil {
+		return "", err
+	}
+	value = strings.TrimSpace(value)
+	totp, err := teamvault.ParseTotp(ctx, value)
+	if err != nil {
+		return "", errors.Wrapf(ctx, err, "field %s of %s holds no totp seed", field, key)
+	}
+	if strings.HasPrefix(strings.ToLower(value), "otpauth:") {
+		return value, nil
+	}
+	secret := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(totp.Secret)
+	return (&url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + label,
+		RawQuery: url.Values{"secret": {secret}}.Encode(),
+	}).String(), nil
+}
+
+// writeQRHalfBlocks prints two module rows per text line using Unicode
+// half-blocks. Colors are set explicitly (light on dark) so the code scans
+// regardless of the terminal theme.
+func writeQRHalfBlocks(ctx context.Context, out io.Writer, code *qr.Code) error {
+	light := func(x, y int) bool {
+		return !code.Black(x-qrQuietZone, y-qrQuietZone)
+	}
+	size := code.Size + 2*qrQuietZone
+	var b strings.Builder
+	for y := 0; y < size; y += 2 {
+		b.WriteString("\x1b[97;40m")
+		for x := 0; x < size; x++ {
+			top := light(x, y)
+			bottom := y+1 < size && light(x, y+1)
+			switch {
+			case top && bottom:
+				b.WriteString("█")
+			case top:
+				b.WriteString("▀")
+			case bottom:
+				b.WriteString("▄")
+			default:
+				b.WriteString(" ")
+			}
+		}
+		b.WriteString("\x1b[0m\n")
+	}
+	if _, err := io.WriteString(out, b.String()); err != nil {
+		return errors.Wrapf(ctx, err, "write qr code failed")
+	}
+	return nil
+}
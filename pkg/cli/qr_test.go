@@ -0,0 +1,124 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"rsc.io/qr"
+
+	teamvault "github.com/Seibert-Data/teamvault-cli/v5/pkg"
+	"github.com/Seibert-Data/teamvault-cli/v5/pkg/cli"
+	"github.com/Seibert-Data/teamvault-cli/v5/pkg/mocks"
+)
+
+var _ = Describe("qr", func() {
+	var ctx context.Context
+	var fakeConn *mocks.Connector
+	var outBuf bytes.Buffer
+	var pngPath string
+	var resetConnector func()
+
+	execute := func(args ...string) error {
+		outBuf.Reset()
+		cmd := cli.NewRootCommand(ctx)
+		cmd.SetArgs(append([]string{"qr"}, args...))
+		cmd.SetOut(&outBuf)
+		cmd.SetErr(&bytes.Buffer{})
+		return cmd.Execute()
+	}
+
+	// expectPNGOf renders the PNG for key and compares it with the PNG of
+	// the expected payload, which pins the exact payload that was encoded.
+	expectPNGOf := func(payload string, args ...string) {
+		Expect(execute(append(args, "--png", pngPath)...)).To(Succeed())
+		written, err := os.ReadFile(pngPath)
+		Expect(err).NotTo(HaveOccurred())
+		code, err := qr.Encode(payload, qr.M)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(written).To(Equal(code.PNG()))
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		os.Setenv("STAGING", "true")
+		pngPath = filepath.Join(GinkgoT().TempDir(), "code.png")
+		fakeConn = &mocks.Connector{}
+		fakeConn.UserReturns(teamvault.User("Office;Guest"), nil)
+		fakeConn.PasswordReturns(teamvault.Password(`pa:ss\word`), nil)
+		fakeConn.UrlReturns(teamvault.Url("https://example.com"), nil)
+		resetConnector = cli.SetNewConnectorForTest(
+			func(sf *cli.SharedFlags) func(context.Context) (teamvault.Connector, error) {
+				return func(ctx context.Context) (teamvault.Connector, error) {
+					return fakeConn, nil
+				}
+			},
+		)
+	})
+
+	AfterEach(func() {
+		resetConnector()
+		os.Unsetenv("STAGING")
+	})
+
+	It("renders half-blocks in the terminal", func() {
+		Expect(execute("QR0001")).To(Succeed())
+
+		code, err := qr.Encode(`pa:ss\word`, qr.M)
+		Expect(err).NotTo(HaveOccurred())
+		lines := strings.Split(strings.TrimSuffix(outBuf.String(), "\n"), "\n")
+		Expect(lines).To(HaveLen((code.Size + 2*4 + 1) / 2))
+		Expect(outBuf.String()).To(ContainSubstring("▀"))
+		Expect(outBuf.String()).To(ContainSubstring("▄"))
+	})
+
+	It("writes the url field as png", func() {
+		expectPNGOf("https://example.com", "QR0001", "--field", "url")
+		info, err := os.Stat(pngPath)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+	})
+
+	It("builds an escaped WIFI payload", func() {
+		expectPNGOf(`WIFI:T:WPA;S:Office\;Guest;P:pa\:ss\\word;;`, "QR0001", "--format", "wifi")
+	})
+
+	It("builds an open hidden WIFI payload", func() {
+		expectPNGOf(
+			`WIFI:T:nopass;S:Office\;Guest;H:true;;`,
+			"QR0001", "--format", "wifi", "--wifi-security", "nopass", "--wifi-hidden",
+		)
+	})
+
+	It("passes an otpauth URI through", func() {
+		uri := "otpauth://totp/Acme:admin?secret=GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ&issuer=Acme"
+		fakeConn.PasswordReturns(teamvault.Password(uri), nil)
+
+		expectPNGOf(uri, "QR0001", "--format", "otpauth")
+	})
+
+	It("wraps a raw seed into an otpauth URI", func() {
+		fakeConn.PasswordReturns(teamvault.Password("gezd gnbv gy3t qojq gezd gnbv gy3t qojq"), nil)
+
+		expectPNGOf(
+			"otpauth://totp/admin?secret=GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ",
+			"QR0001", "--format", "otpauth", "--label", "admin",
+		)
+	})
+
+	It("rejects otpauth format for values without a seed", func() {
+		Expect(execute("QR0001", "--format", "otpauth")).To(HaveOccurred())
+	})
+
+	It("rejects unknown fields", func() {
+		Expect(execute("QR0001", "--field", "username")).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,82 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/bborbe/errors"
+	"github.com/spf13/cobra"
+
+	teamvault "github.com/Seibert-Data/teamvault-cli/v5/pkg"
+)
+
+// createRevisionCommand builds the `revision` subcommand, which prints a
+// secret's current revision id for `update --if-revision`. It reads metadata
+// only, so no password access is recorded.
+func createRevisionCommand(ctx context.Context, sf *SharedFlags) *cobra.Command {
+	var asJSON bool
+
+	cmd := &cobra.Command{
+		Use:               "revision [key]",
+		Short:             "Print a secret's current revision id (for update --if-revision)",
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeKeyArg(ctx, sf),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key, err := resolveKey(cmd, args)
+			if err != nil {
+				return err
+			}
+			conn, err := newConnector(sf)(ctx)
+			if err != nil {
+				return errors.Wrap(ctx, err, "create connector failed")
+			}
+			key, err = lookupKey(ctx, cmd, sf, conn, key)
+			if err != nil {
+				return err
+			}
+			metadata, err := teamvault.ReadMetadata(ctx, conn, key)
+			if err != nil {
+				return errors.Wrap(ctx, err, "read metadata failed")
+			}
+			if !asJSON {
+				if _, err := fmt.Fprintln(cmd.OutOrStdout(), metadata.CurrentRevision.ID()); err != nil {
+					return errors.Wrapf(ctx, err, "write revision failed")
+				}
+				return nil
+			}
+			output := struct {
+				Key          string `json:"key"`
+				Revision     string `json:"revision"`
+				LastModified string `json:"last_modified,omitempty"`
+			}{
+				Key:      key.String(),
+				Revision: metadata.CurrentRevision.ID(),
+			}
+			if !metadata.LastModified.IsZero() {
+				output.LastModified = metadata.LastModified.UTC().Format(time.RFC3339)
+			}
+			encoded, err := json.Marshal(output)
+			if err != nil {
+				return errors.Wrapf(ctx, err, "marshal json failed")
+			}
+			if _, err := fmt.Fprintf(cmd.OutOrStdout(), "%s\n", encoded); err != nil {
+				return errors.Wrapf(ctx, err, "write revision failed")
+			}
+			return nil
+		},
+	}
+
+	var key string
+	cmd.Flags().
+		StringVar(&key, "teamvault-key", "", "teamvault key (alternative to positional argument)")
+	cmd.Flags().
+		BoolVar(&asJSON, "json", false, "print key, revision and last_modified as JSON object")
+
+	return cmd
+}
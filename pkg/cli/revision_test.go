@@ -0,0 +1,72 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	teamvault "github.com/Seibert-Data/teamvault-cli/v5/pkg"
+	"github.com/Seibert-Data/teamvault-cli/v5/pkg/cli"
+	"github.com/Seibert-Data/teamvault-cli/v5/pkg/mocks"
+)
+
+var _ = Describe("revision", func() {
+	var ctx context.Context
+	var stdout bytes.Buffer
+	var reset func()
+
+	run := func(args ...string) error {
+		stdout.Reset()
+		cmd := cli.NewRootCommand(ctx)
+		cmd.SetArgs(append([]string{"revision"}, args...))
+		cmd.SetOut(&stdout)
+		cmd.SetErr(&bytes.Buffer{})
+		return cmd.Execute()
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		os.Setenv("STAGING", "true")
+		fakeConn := metadataConnector{
+			Connector:      &mocks.Connector{},
+			MetadataReader: &mocks.MetadataReader{},
+		}
+		fakeConn.MetadataReader.MetadataReturns(teamvault.SecretMetadata{
+			Key:             "AbC123",
+			CurrentRevision: "https://vault.example.com/api/secret-revisions/rev7/",
+			LastModified:    time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		}, nil)
+		reset = cli.SetNewConnectorForTest(
+			func(sf *cli.SharedFlags) func(context.Context) (teamvault.Connector, error) {
+				return func(ctx context.Context) (teamvault.Connector, error) {
+					return fakeConn, nil
+				}
+			},
+		)
+	})
+
+	AfterEach(func() {
+		reset()
+		os.Unsetenv("STAGING")
+	})
+
+	It("prints the revision id", func() {
+		Expect(run("AbC123")).To(Succeed())
+		Expect(stdout.String()).To(Equal("rev7\n"))
+	})
+
+	It("prints key, revision and last change with --json", func() {
+		Expect(run("AbC123", "--json")).To(Succeed())
+		Expect(stdout.String()).To(MatchJSON(
+			`{"key":"AbC123","revision":"rev7","last_modified":"2026-03-01T12:00:00Z"}`,
+		))
+	})
+})
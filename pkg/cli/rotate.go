@@ -0,0 +1,164 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bborbe/errors"
+	"github.com/spf13/cobra"
+
+	teamvault "github.com/Seibert-Data/teamvault-cli/v5/pkg"
+)
+
+// createRotateCommand creates the rotate command.
+func createRotateCommand(ctx context.Context, sf *SharedFlags) *cobra.Command {
+	var hook string
+	var hookArgs []string
+	var rollbackHook string
+	var rollbackHookArgs []string
+	var journal string
+	var generateLocal bool
+	var gf *generatorFlags
+
+	cmd := &cobra.Command{
+		Use:   "rotate [key] --hook <PROGRAM>",
+		Short: "Rotate a password on the target system and in TeamVault",
+		Long: `Rotate a password on the target system and in TeamVault.
+
+A new password is generated (by the server, or locally with --generate-local
+and the policy flags of the generate command) and the --hook program is run
+with a JSON object on stdin:
+
+  {"key": ..., "name": ..., "username": ..., "url": ...,
+   "old_password": ..., "new_password": ...}
+
+Passwords are never passed as arguments. --hook is the path of the program,
+run without a shell; each --hook-arg adds one argument, taken as is (spaces
+and quotes included). Only if it exits with 0 is the new password stored in
+TeamVault, guarded by the revision read before; if storing fails, the
+--rollback-hook program (with its --rollback-hook-arg arguments) is run with
+the same input to restore the old password. Without a rollback hook, or if
+it fails too, the new password is printed so it is not lost. The old
+password is read from the server, never from the disk cache, and neither
+password is written to it.
+
+Every rotation is appended to the journal, by default next to the config
+file (e.g. ~/.teamvault.rotations.jsonl). It holds no passwords.`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeKeyArg(ctx, sf),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if hook == "" {
+				return errors.New(ctx, "--hook is required")
+			}
+			if rollbackHook == "" && len(rollbackHookArgs) > 0 {
+				return errors.New(ctx, "--rollback-hook-arg requires --rollback-hook")
+			}
+			if !generateLocal && gf.changed(cmd) {
+				return errors.New(ctx, "password policy flags require --generate-local")
+			}
+			key, err := resolveKey(cmd, args)
+			if err != nil {
+				return err
+			}
+			conn, err := newConnector(sf.withoutCache())(ctx)
+			if err != nil {
+				return errors.Wrap(ctx, err, "create connector failed")
+			}
+			key, err = lookupKey(ctx, cmd, sf, conn, key)
+			if err != nil {
+				return err
+			}
+			writer, err := newWriter(sf)(ctx)
+			if err != nil {
+				return errors.Wrap(ctx, err, "create writer failed")
+			}
+			journalPath := teamvault.RotationJournalPath(journal)
+			if journalPath == "" {
+				if journalPath, err = teamvault.ProfileRotationJournalPath(
+					teamvault.TeamvaultConfigPath(sf.configPath),
+				); err != nil {
+					return errors.Wrap(ctx, err, "find rotation journal failed")
+				}
+			}
+
+			options := teamvault.RotateOptions{
+				Generate: writer.GeneratePassword,
+				Hook: teamvault.NewCommandRotationHook(
+					append([]string{hook}, hookArgs...),
+					cmd.ErrOrStderr(),
+				),
+				Journal: journalPath,
+			}
+			if generateLocal {
+				options.Generate = func(ctx context.Context) (teamvault.Password, error) {
+					return gf.generate(ctx, cmd)
+				}
+			}
+			if rollbackHook != "" {
+				options.Rollback = teamvault.NewCommandRotationHook(
+					append([]string{rollbackHook}, rollbackHookArgs...),
+					cmd.ErrOrStderr(),
+				)
+			}
+			result, err := teamvault.NewRotator(conn, writer, newCurrentDateTime()).
+				Rotate(ctx, key, options)
+			switch result.Status {
+			case teamvault.RotationRotated:
+				fmt.Fprintf(cmd.ErrOrStderr(), "Rotated %s (%s).\n", result.Name, key)
+			case teamvault.RotationUpdateFailed, teamvault.RotationRollbackFailed:
+				fmt.Fprintln(
+					cmd.ErrOrStderr(),
+					"The target system may use the new password, but TeamVault does not store it:",
+				)
+				fmt.Fprintln(cmd.OutOrStdout(), result.NewPassword)
+			}
+			return err
+		},
+	}
+
+	var key string
+	cmd.Flags().
+		StringVar(&key, "teamvault-key", "", "teamvault key (alternative to positional argument)")
+	cmd.Flags().StringVar(
+		&hook,
+		"hook",
+		"",
+		"program applying the new password to the target system",
+	)
+	cmd.Flags().StringArrayVar(
+		&hookArgs,
+		"hook-arg",
+		nil,
+		"argument passed to the --hook program; repeatable",
+	)
+	cmd.Flags().StringVar(
+		&rollbackHook,
+		"rollback-hook",
+		"",
+		"program restoring the old password if TeamVault cannot be updated",
+	)
+	cmd.Flags().StringArrayVar(
+		&rollbackHookArgs,
+		"rollback-hook-arg",
+		nil,
+		"argument passed to the --rollback-hook program; repeatable",
+	)
+	cmd.Flags().StringVar(
+		&journal,
+		"journal",
+		"",
+		"rotation journal file (default: next to the config file)",
+	)
+	cmd.Flags().BoolVar(
+		&generateLocal,
+		"generate-local",
+		false,
+		"generate the password locally with the policy flags (see the generate command)",
+	)
+	gf = addGeneratorFlags(cmd)
+	return cmd
+}
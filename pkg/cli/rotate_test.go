@@ -0,0 +1,190 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	stderrors "errors"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	teamvault "github.com/Seibert-Data/teamvault-cli/v5/pkg"
+	"github.com/Seibert-Data/teamvault-cli/v5/pkg/cli"
+	"github.com/Seibert-Data/teamvault-cli/v5/pkg/mocks"
+)
+
+var _ = Describe("rotate", func() {
+	var ctx context.Context
+	var fakeConn metadataConnector
+	var fakeWriter *mocks.Writer
+	var dir string
+	var journal string
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	var uncached bool
+	var resets []func()
+
+	run := func(args ...string) error {
+		stdout.Reset()
+		stderr.Reset()
+		cmd := cli.NewRootCommand(ctx)
+		cmd.SetArgs(append(append([]string{"rotate"}, args...), "--journal", journal))
+		cmd.SetOut(&stdout)
+		cmd.SetErr(&stderr)
+		return cmd.Execute()
+	}
+
+	// writeHook writes an executable script saving its stdin to <name>.json.
+	writeHook := func(name string, exitCode int) string {
+		path := filepath.Join(dir, name+".sh")
+		script := "#!/bin/sh\ncat > \"" + filepath.Join(dir, name+".json") + "\"\n"
+		if exitCode != 0 {
+			script += "exit 1\n"
+		}
+		Expect(os.WriteFile(path, []byte(script), 0700)).To(Succeed())
+		return path
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		os.Setenv("STAGING", "true")
+		dir = GinkgoT().TempDir()
+		journal = filepath.Join(dir, "rotations.jsonl")
+		fakeConn = metadataConnector{
+			Connector:      &mocks.Connector{},
+			MetadataReader: &mocks.MetadataReader{},
+		}
+		fakeConn.MetadataReader.MetadataReturns(teamvault.SecretMetadata{
+			Key:             "AbC123",
+			Name:            "payment-db",
+			ContentType:     teamvault.ContentTypePassword,
+			CurrentRevision: "rev1",
+		}, nil)
+		fakeConn.PasswordReturns("old-pw", nil)
+		fakeWriter = &mocks.Writer{}
+		fakeWriter.GeneratePasswordReturns("server-pw", nil)
+		resets = []func(){
+			cli.SetNewConnectorForTest(
+				func(sf *cli.SharedFlags) func(context.Context) (teamvault.Connector, error) {
+					uncached = sf.Uncached()
+					return func(ctx context.Context) (teamvault.Connector, error) {
+						return fakeConn, nil
+					}
+				},
+			),
+			cli.SetNewWriterForTest(
+				func(sf *cli.SharedFlags) func(context.Context) (teamvault.Writer, error) {
+					return func(ctx context.Context) (teamvault.Writer, error) {
+						return fakeWriter, nil
+					}
+				},
+			),
+		}
+	})
+
+	AfterEach(func() {
+		for _, reset := range resets {
+			reset()
+		}
+		os.Unsetenv("STAGING")
+	})
+
+	It("applies the new password via the hook and stores it", func() {
+		Expect(run("AbC123", "--hook", writeHook("apply", 0))).To(Succeed())
+
+		Expect(os.ReadFile(filepath.Join(dir, "apply.json"))).To(MatchJSON(`{
+			"key": "AbC123", "name": "payment-db", "username": "", "url": "",
+			"old_password": "old-pw", "new_password": "server-pw"
+		}`))
+		_, _, update := fakeWriter.UpdateArgsForCall(0)
+		Expect(*update.Password).To(Equal(teamvault.Password("server-pw")))
+		Expect(stderr.String()).To(ContainSubstring("Rotated payment-db (AbC123)."))
+		Expect(stdout.String()).To(BeEmpty())
+		entries, err := teamvault.RotationJournalPath(journal).Read(ctx)
+		Expect(err).To(BeNil())
+		Expect(entries).To(HaveLen(1))
+		Expect(entries[0].Status).To(Equal(teamvault.RotationRotated))
+	})
+
+	It("passes --hook-arg values as separate arguments", func() {
+		script := filepath.Join(dir, "args.sh")
+		Expect(os.WriteFile(
+			script,
+			[]byte("#!/bin/sh\nprintf '%s|' \"$@\" > \""+filepath.Join(dir, "args.txt")+"\"\n"),
+			0700,
+		)).To(Succeed())
+
+		Expect(run(
+			"AbC123",
+			"--hook", script,
+			"--hook-arg", "db host",
+			"--hook-arg", `it's "quoted"`,
+		)).To(Succeed())
+
+		Expect(os.ReadFile(filepath.Join(dir, "args.txt"))).
+			To(Equal([]byte(`db host|it's "quoted"|`)))
+	})
+
+	It("reads the old password without the disk cache", func() {
+		Expect(run("AbC123", "--hook", writeHook("apply", 0), "--cache")).To(Succeed())
+
+		Expect(uncached).To(BeTrue())
+	})
+
+	It("generates locally with --generate-local", func() {
+		Expect(run(
+			"AbC123",
+			"--hook", writeHook("apply", 0),
+			"--generate-local", "--length", "12", "--classes", "digits",
+		)).To(Succeed())
+
+		Expect(fakeWriter.GeneratePasswordCallCount()).To(Equal(0))
+		_, _, update := fakeWriter.UpdateArgsForCall(0)
+		Expect(update.Password.String()).To(MatchRegexp(`^[0-9]{12}$`))
+	})
+
+	It("leaves TeamVault alone when the hook fails", func() {
+		err := run("AbC123", "--hook", writeHook("apply", 1))
+
+		Expect(err).NotTo(BeNil())
+		Expect(fakeWriter.UpdateCallCount()).To(Equal(0))
+		entries, _ := teamvault.RotationJournalPath(journal).Read(ctx)
+		Expect(entries[0].Status).To(Equal(teamvault.RotationHookFailed))
+	})
+
+	It("runs the rollback hook when the update fails", func() {
+		fakeWriter.UpdateReturns("", "", teamvault.ErrRevisionConflict)
+
+		err := run(
+			"AbC123",
+			"--hook", writeHook("apply", 0),
+			"--rollback-hook", writeHook("rollback", 0),
+		)
+
+		Expect(stderrors.Is(err, teamvault.ErrRevisionConflict)).To(BeTrue())
+		Expect(filepath.Join(dir, "rollback.json")).To(BeAnExistingFile())
+		Expect(stdout.String()).To(BeEmpty())
+	})
+
+	It("prints the new password when nothing can restore the old one", func() {
+		fakeWriter.UpdateReturns("", "", stderrors.New("server error"))
+
+		err := run("AbC123", "--hook", writeHook("apply", 0))
+
+		Expect(err).NotTo(BeNil())
+		Expect(stdout.String()).To(Equal("server-pw\n"))
+		Expect(stderr.String()).To(ContainSubstring("TeamVault does not store it"))
+	})
+
+	It("requires --hook", func() {
+		err := run("AbC123")
+		Expect(err).NotTo(BeNil())
+		Expect(err.Error()).To(ContainSubstring("--hook is required"))
+	})
+})
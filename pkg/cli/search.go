@@ -9,7 +9,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"regexp"
+	"sort"
+	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/bborbe/errors"
 	"github.com/spf13/cobra"
@@ -17,49 +21,223 @@ import (
 	teamvault "github.com/Seibert-Data/teamvault-cli/v5/pkg"
 )
 
+// searchFields maps the field names accepted by --columns, --sort and the
+// --match-* filters to their value in a result. Timestamps are rendered in
+// UTC RFC 3339, so sorting the text sorts chronologically.
+var searchFields = map[string]func(teamvault.SearchResult) string{
+	"key":         func(r teamvault.SearchResult) string { return r.Key.String() },
+	"name":        func(r teamvault.SearchResult) string { return r.Name },
+	"username":    func(r teamvault.SearchResult) string { return r.Username },
+	"url":         func(r teamvault.SearchResult) string { return r.Url.String() },
+	"type":        func(r teamvault.SearchResult) string { return r.ContentType },
+	"status":      func(r teamvault.SearchResult) string { return r.Status },
+	"description": func(r teamvault.SearchResult) string { return r.Description },
+	"modified":    formatLastModified,
+}
+
+const searchFieldNames = "key, name, username, url, type, status, description, modified"
+
+func formatLastModified(r teamvault.SearchResult) string {
+	if r.LastModified.IsZero() {
+		return ""
+	}
+	return r.LastModified.UTC().Format(time.RFC3339)
+}
+
+// searchFilter is a client-side --match-* filter on one field.
+type searchFilter struct {
+	field string
+	match func(string) bool
+}
+
+// compileSearchFilters turns the --match-* flags into filters. Patterns are
+// case-insensitive globs matching the whole value, or Go regular
+// expressions matching anywhere in it when regex is set.
+func compileSearchFilters(
+	ctx context.Context,
+	patterns map[string]string,
+	regex bool,
+) ([]searchFilter, error) {
+	var filters []searchFilter
+	for _, field := range []string{"name", "username", "url"} {
+		pattern := patterns[field]
+		if pattern == "" {
+			continue
+		}
+		if regex {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, errors.Wrapf(ctx, err, "invalid --match-%s regex %q", field, pattern)
+			}
+			filters = append(filters, searchFilter{field: field, match: re.MatchString})
+			continue
+		}
+		filters = append(
+			filters,
+			searchFilter{field: field, match: globToRegexp(pattern).MatchString},
+		)
+	}
+	return filters, nil
+}
+
+// globToRegexp compiles a case-insensitive glob where * matches any text,
+// including '/' (so URL patterns work), and ? one character.
+func globToRegexp(glob string) *regexp.Regexp {
+	quoted := regexp.QuoteMeta(glob)
+	quoted = strings.ReplaceAll(quoted, `\*`, ".*")
+	quoted = strings.ReplaceAll(quoted, `\?`, ".")
+	return regexp.MustCompile("(?is)^" + quoted + "$")
+}
+
+func matchesSearchFilters(result teamvault.SearchResult, filters []searchFilter) bool {
+	for _, filter := range filters {
+		if !filter.match(searchFields[filter.field](result)) {
+			return false
+		}
+	}
+	return true
+}
+
+// parseSearchColumns validates the comma-separated --columns value.
+func parseSearchColumns(ctx context.Context, value string) ([]string, error) {
+	var columns []string
+	for _, column := range strings.Split(value, ",") {
+		column = strings.ToLower(strings.TrimSpace(column))
+		if column == "" {
+			continue
+		}
+		if _, ok := searchFields[column]; !ok {
+			return nil, errors.Errorf(
+				ctx,
+				"unknown column %q (want %s)",
+				column,
+				searchFieldNames,
+			)
+		}
+		columns = append(columns, column)
+	}
+	if len(columns) == 0 {
+		return nil, errors.New(ctx, "--columns must name at least one column")
+	}
+	return columns, nil
+}
+
+// sortSearchResults sorts by the given field, keeping the server order for
+// equal values.
+func sortSearchResults(results []teamvault.SearchResult, field string, reverse bool) {
+	value := searchFields[field]
+	sort.SliceStable(results, func(i, j int) bool {
+		if reverse {
+			return value(results[i]) > value(results[j])
+		}
+		return value(results[i]) < value(results[j])
+	})
+}
+
 // createSearchCommand creates the `search` subcommand.
 func createSearchCommand(ctx context.Context, sf *SharedFlags) *cobra.Command {
+	var (
+		query   teamvault.SearchQuery
+		regex   bool
+		sortBy  string
+		reverse bool
+		columns string
+	)
+
 	cmd := &cobra.Command{
 		Use:   "search <query>",
 		Short: "Search for secrets by name and print matching keys and names",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			query := args[0]
-			conn, err := newConnector(sf)(ctx)
+			query.Name = args[0]
+			patterns := make(map[string]string)
+			for _, field := range []string{"name", "username", "url"} {
+				patterns[field], _ = cmd.Flags().GetString("match-" + field)
+			}
+			filters, err := compileSearchFilters(ctx, patterns, regex)
 			if err != nil {
-				return errors.Wrap(ctx, err, "create connector failed")
+				return err
 			}
-			results, err := conn.Search(ctx, query)
+			tableColumns, err := parseSearchColumns(ctx, columns)
 			if err != nil {
-				return errors.Wrap(ctx, err, "search failed")
+				return err
 			}
-			asJSON, _ := cmd.Flags().GetBool("json")
-			keysOnly, _ := cmd.Flags().GetBool("keys-only")
-			// --limit truncates the results client-side after the (paginated,
-			// cap-bounded) fetch; it caps output, not server round-trips.
+			if _, ok := searchFields[sortBy]; sortBy != "" && !ok {
+				return errors.Errorf(
+					ctx,
+					"unknown sort field %q (want %s)",
+					sortBy,
+					searchFieldNames,
+				)
+			}
+			conn, err := newConnector(sf)(ctx)
+			if err != nil {
+				return errors.Wrap(ctx, err, "create connector failed")
+			}
+			// Without --sort, --limit ends the streamed search, so no further
+			// pages are fetched once enough results have arrived. With --sort
+			// all matches are needed first.
 			limit, _ := cmd.Flags().GetInt("limit")
+			var results []teamvault.SearchResult
+			for result, err := range teamvault.SearchSeq(ctx, conn, query) {
+				if err != nil {
+					return errors.Wrap(ctx, err, "search failed")
+				}
+				if !matchesSearchFilters(result, filters) {
+					continue
+				}
+				results = append(results, result)
+				if sortBy == "" && limit > 0 && len(results) >= limit {
+					break
+				}
+			}
+			if sortBy != "" {
+				sortSearchResults(results, sortBy, reverse)
+			}
 			if limit > 0 && len(results) > limit {
 				results = results[:limit]
 			}
-			return writeSearch(ctx, cmd.OutOrStdout(), results, asJSON, keysOnly)
+			asJSON, _ := cmd.Flags().GetBool("json")
+			keysOnly, _ := cmd.Flags().GetBool("keys-only")
+			return writeSearch(ctx, cmd.OutOrStdout(), results, tableColumns, asJSON, keysOnly)
 		},
 	}
 
 	cmd.Flags().
-		Bool("json", false, "print results as a JSON array of objects {key,name,username,url}")
+		Bool("json", false, "print results as a JSON array of objects {key,name,username,url,...}")
 	cmd.Flags().Bool("keys-only", false, "print only bare keys, one per line (for scripting)")
 	cmd.Flags().Int("limit", 0, "maximum number of results to print (0 = no limit)")
+	cmd.Flags().
+		StringVar(&query.ContentType, "type", "", "only secrets of this content type (password, cc, file)")
+	cmd.Flags().
+		StringVar(&query.Status, "status", "", "only secrets with this status (e.g. ok, needs_changing)")
+	cmd.Flags().
+		StringVar(&query.Description, "description", "", "only secrets whose description contains this text")
+	for _, field := range []string{"name", "username", "url"} {
+		cmd.Flags().String(
+			"match-"+field,
+			"",
+			"only results whose "+field+" matches this glob (or regex with --regex)",
+		)
+	}
+	cmd.Flags().BoolVar(&regex, "regex", false, "treat --match-* patterns as regular expressions")
+	cmd.Flags().StringVar(&sortBy, "sort", "", "sort by field ("+searchFieldNames+")")
+	cmd.Flags().BoolVar(&reverse, "reverse", false, "with --sort, sort in descending order")
+	cmd.Flags().
+		StringVar(&columns, "columns", "key,name", "comma-separated table columns ("+searchFieldNames+")")
 	return cmd
 }
 
 // writeSearch writes the search results to the given writer.
 // When keysOnly is true, scripting mode takes precedence and one bare key per
 // line is printed. When asJSON is true, a JSON array of {key,name,username,url}
-// objects is written. Otherwise, an aligned KEY / NAME table is printed.
+// objects is written; type, status, description and modified are included
+// when known. Otherwise, an aligned table of the given columns is printed.
 func writeSearch(
 	ctx context.Context,
 	out io.Writer,
 	results []teamvault.SearchResult,
+	columns []string,
 	asJSON bool,
 	keysOnly bool,
 ) error {
@@ -74,18 +252,26 @@ func writeSearch(
 	}
 	if asJSON {
 		type searchJSON struct {
-			Key      string `json:"key"`
-			Name     string `json:"name"`
-			Username string `json:"username"`
-			Url      string `json:"url"`
+			Key          string `json:"key"`
+			Name         string `json:"name"`
+			Username     string `json:"username"`
+			Url          string `json:"url"`
+			ContentType  string `json:"content_type,omitempty"`
+			Status       string `json:"status,omitempty"`
+			Description  string `json:"description,omitempty"`
+			LastModified string `json:"last_modified,omitempty"`
 		}
 		items := make([]searchJSON, 0, len(results))
 		for _, r := range results {
 			items = append(items, searchJSON{
-				Key:      r.Key.String(),
-				Name:     r.Name,
-				Username: r.Username,
-				Url:      r.Url.String(),
+				Key:          r.Key.String(),
+				Name:         r.Name,
+				Username:     r.Username,
+				Url:          r.Url.String(),
+				ContentType:  r.ContentType,
+				Status:       r.Status,
+				Description:  r.Description,
+				LastModified: formatLastModified(r),
 			})
 		}
 		encoded, err := json.Marshal(items)
@@ -97,11 +283,15 @@ func writeSearch(
 		}
 		return nil
 	}
-	// Default: aligned KEY / NAME table.
+	// Default: aligned table, KEY / NAME unless --columns says otherwise.
 	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
-	fmt.Fprintln(tw, "KEY\tNAME")
+	fmt.Fprintln(tw, strings.ToUpper(strings.Join(columns, "\t")))
 	for _, r := range results {
-		fmt.Fprintf(tw, "%s\t%s\n", r.Key.String(), r.Name)
+		values := make([]string, 0, len(columns))
+		for _, column := range columns {
+			values = append(values, searchFields[column](r))
+		}
+		fmt.Fprintln(tw, strings.Join(values, "\t"))
 	}
 	if err := tw.Flush(); err != nil {
 		return errors.Wrapf(ctx, err, "flush search table failed")
@@ -9,6 +9,7 @@ import (
 	"context"
 	"os"
 	"strings"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -250,6 +251,138 @@ var _ = Describe("search", func() {
 		})
 	})
 
+	Describe("filters, sorting and columns", func() {
+		var fakeConn *mocks.Connector
+		var outBuf bytes.Buffer
+		var resetConnector func()
+
+		execute := func(args ...string) error {
+			outBuf.Reset()
+			cmd := cli.NewRootCommand(ctx)
+			cmd.SetArgs(append([]string{"search", "db"}, args...))
+			cmd.SetOut(&outBuf)
+			cmd.SetErr(&bytes.Buffer{})
+			return cmd.Execute()
+		}
+
+		BeforeEach(func() {
+			fakeConn = &mocks.Connector{}
+			fakeConn.SearchReturns([]teamvault.SearchResult{
+				{
+					Key:          "ABC123",
+					Name:         "prod-db",
+					Username:     "admin",
+					Url:          "https://db.prod.example",
+					ContentType:  "password",
+					Status:       "ok",
+					LastModified: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+				},
+				{
+					Key:          "DEF456",
+					Name:         "staging-db",
+					Username:     "deploy",
+					Url:          "https://db.staging.example",
+					ContentType:  "file",
+					Status:       "needs_changing",
+					LastModified: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC),
+				},
+				{
+					Key:         "GHI789",
+					Name:        "Prod-DB-Replica",
+					Username:    "readonly",
+					ContentType: "password",
+					Description: "Read replica in eu-west",
+				},
+			}, nil)
+			resetConnector = cli.SetNewConnectorForTest(
+				func(sf *cli.SharedFlags) func(context.Context) (teamvault.Connector, error) {
+					return func(ctx context.Context) (teamvault.Connector, error) {
+						return fakeConn, nil
+					}
+				},
+			)
+		})
+
+		AfterEach(func() {
+			resetConnector()
+		})
+
+		It("filters by a case-insensitive name glob", func() {
+			Expect(execute("--match-name", "prod-*", "--keys-only")).To(Succeed())
+			Expect(outBuf.String()).To(Equal("ABC123\nGHI789\n"))
+		})
+
+		It("filters by regex with --regex", func() {
+			Expect(execute("--match-username", "^(admin|deploy)$", "--regex", "--keys-only")).
+				To(Succeed())
+			Expect(outBuf.String()).To(Equal("ABC123\nDEF456\n"))
+		})
+
+		It("filters by url glob", func() {
+			Expect(execute("--match-url", "*.staging.*", "--keys-only")).To(Succeed())
+			Expect(outBuf.String()).To(Equal("DEF456\n"))
+		})
+
+		It("rejects an invalid regex", func() {
+			Expect(execute("--match-name", "(", "--regex")).To(HaveOccurred())
+		})
+
+		It("filters by content type, status and description", func() {
+			Expect(execute("--type", "password", "--keys-only")).To(Succeed())
+			Expect(outBuf.String()).To(Equal("ABC123\nGHI789\n"))
+
+			Expect(execute("--status", "needs_changing", "--keys-only")).To(Succeed())
+			Expect(outBuf.String()).To(Equal("DEF456\n"))
+
+			Expect(execute("--description", "REPLICA", "--keys-only")).To(Succeed())
+			Expect(outBuf.String()).To(Equal("GHI789\n"))
+		})
+
+		It("sorts by a field", func() {
+			Expect(execute("--sort", "username", "--keys-only")).To(Succeed())
+			Expect(outBuf.String()).To(Equal("ABC123\nDEF456\nGHI789\n"))
+
+			Expect(execute("--sort", "modified", "--reverse", "--keys-only")).To(Succeed())
+			Expect(outBuf.String()).To(Equal("ABC123\nDEF456\nGHI789\n"))
+
+			Expect(execute("--sort", "modified", "--keys-only")).To(Succeed())
+			Expect(outBuf.String()).To(Equal("GHI789\nDEF456\nABC123\n"))
+		})
+
+		It("applies --limit after --sort", func() {
+			Expect(execute("--sort", "name", "--reverse", "--limit", "1", "--keys-only")).
+				To(Succeed())
+			Expect(outBuf.String()).To(Equal("DEF456\n"))
+		})
+
+		It("rejects an unknown sort field", func() {
+			Expect(execute("--sort", "size")).To(HaveOccurred())
+		})
+
+		It("prints the requested columns", func() {
+			Expect(execute("--columns", "key,type,status,modified", "--match-name", "prod-db")).
+				To(Succeed())
+			lines := strings.Split(strings.TrimSpace(outBuf.String()), "\n")
+			Expect(lines).To(HaveLen(2))
+			Expect(
+				strings.Fields(lines[0]),
+			).To(Equal([]string{"KEY", "TYPE", "STATUS", "MODIFIED"}))
+			Expect(strings.Fields(lines[1])).
+				To(Equal([]string{"ABC123", "password", "ok", "2026-03-01T12:00:00Z"}))
+		})
+
+		It("rejects an unknown column", func() {
+			Expect(execute("--columns", "key,size")).To(HaveOccurred())
+		})
+
+		It("includes the known extra fields in --json", func() {
+			Expect(execute("--match-name", "prod-db", "--json")).To(Succeed())
+			Expect(strings.TrimSpace(outBuf.String())).To(Equal(
+				`[{"key":"ABC123","name":"prod-db","username":"admin","url":"https://db.prod.example","content_type":"password","status":"ok","last_modified":"2026-03-01T12:00:00Z"}]`,
+			))
+		})
+	})
+
 	Describe("missing query", func() {
 		It("returns an error when no query is provided", func() {
 			var errBuf bytes.Buffer
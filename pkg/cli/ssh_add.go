@@ -0,0 +1,184 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"os"
+
+	"github.com/bborbe/errors"
+	libtime "github.com/bborbe/time"
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+
+	teamvault "github.com/Seibert-Data/teamvault-cli/v5/pkg"
+)
+
+// createSSHAddCommand builds the `ssh-add` subcommand, which loads an SSH
+// private key stored as a TeamVault file secret into the running ssh-agent.
+// The key is decoded, parsed and handed to the agent in memory only; it is
+// never written to disk.
+func createSSHAddCommand(ctx context.Context, sf *SharedFlags) *cobra.Command {
+	var (
+		passphraseKey string
+		lifetime      string
+		comment       string
+	)
+
+	cmd := &cobra.Command{
+		Use:               "ssh-add [key]",
+		Short:             "Add an SSH private key from a TeamVault file secret to ssh-agent",
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeKeyArg(ctx, sf),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key, err := resolveKey(cmd, args)
+			if err != nil {
+				return err
+			}
+			var lifetimeSecs uint32
+			if lifetime != "" {
+				d, err := libtime.ParseDuration(ctx, lifetime)
+				if err != nil {
+					return errors.Wrapf(ctx, err, "parse lifetime %q failed", lifetime)
+				}
+				seconds := d.Duration().Seconds()
+				if seconds < 0 || seconds > math.MaxUint32 {
+					return errors.Errorf(
+						ctx,
+						"invalid lifetime %v: must be between 0 and %ds",
+						d.Duration(),
+						uint32(math.MaxUint32),
+					)
+				}
+				lifetimeSecs = uint32(seconds) // #nosec G115 -- checked to fit uint32
+			}
+			var passphrase teamvault.Key
+			if passphraseKey != "" {
+				keyResolver, err := sf.keyResolver(ctx)
+				if err != nil {
+					return err
+				}
+				if passphrase, err = keyResolver.ResolveKey(ctx, passphraseKey); err != nil {
+					return errors.Wrap(ctx, err, "invalid --passphrase-key")
+				}
+			}
+			// The disk cache would store the decrypted private key in plain text.
+			conn, err := newConnector(sf.withoutCache())(ctx)
+			if err != nil {
+				return errors.Wrap(ctx, err, "create connector failed")
+			}
+			key, err = lookupKey(ctx, cmd, sf, conn, key)
+			if err != nil {
+				return err
+			}
+			if comment == "" {
+				comment = "teamvault:" + key.String()
+			}
+			privateKey, err := readSSHPrivateKey(ctx, conn, key, passphrase)
+			if err != nil {
+				return err
+			}
+			if err := addToSSHAgent(ctx, agent.AddedKey{
+				PrivateKey:   privateKey,
+				Comment:      comment,
+				LifetimeSecs: lifetimeSecs,
+			}); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "Identity added: %s\n", comment)
+			return nil
+		},
+	}
+
+	var teamvaultKey string
+	cmd.Flags().
+		StringVar(&teamvaultKey, "teamvault-key", "", "teamvault key (alternative to positional argument)")
+	cmd.Flags().StringVar(
+		&passphraseKey,
+		"passphrase-key",
+		"",
+		"teamvault key whose password decrypts an encrypted private key",
+	)
+	cmd.Flags().StringVar(
+		&lifetime,
+		"lifetime",
+		"",
+		"maximum lifetime of the identity in the agent (e.g. 30m, 8h); default: no limit",
+	)
+	cmd.Flags().
+		StringVar(&comment, "comment", "", "identity comment shown by ssh-add -l (default: teamvault:<key>)")
+
+	return cmd
+}
+
+// readSSHPrivateKey fetches and parses the private key held in key's file
+// field, decrypting it with passphraseKey's password when one is given.
+func readSSHPrivateKey(
+	ctx context.Context,
+	conn teamvault.Connector,
+	key teamvault.Key,
+	passphraseKey teamvault.Key,
+) (any, error) {
+	file, err := conn.File(ctx, key)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "get file failed")
+	}
+	pemBytes, err := file.Content()
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "decode file of %s failed", key)
+	}
+	defer clear(pemBytes)
+	if len(pemBytes) == 0 {
+		return nil, errors.Errorf(ctx, "secret %s has no file content", key)
+	}
+
+	if passphraseKey != "" {
+		passphrase, err := conn.Password(ctx, passphraseKey)
+		if err != nil {
+			return nil, errors.Wrap(ctx, err, "get passphrase failed")
+		}
+		privateKey, err := ssh.ParseRawPrivateKeyWithPassphrase(pemBytes, []byte(passphrase))
+		if err != nil {
+			return nil, errors.Wrapf(ctx, err, "parse encrypted private key of %s failed", key)
+		}
+		return privateKey, nil
+	}
+
+	privateKey, err := ssh.ParseRawPrivateKey(pemBytes)
+	if err != nil {
+		var missing *ssh.PassphraseMissingError
+		if errors.As(err, &missing) {
+			return nil, errors.Errorf(
+				ctx,
+				"private key of %s is encrypted; pass --passphrase-key <key>",
+				key,
+			)
+		}
+		return nil, errors.Wrapf(ctx, err, "parse private key of %s failed", key)
+	}
+	return privateKey, nil
+}
+
+// addToSSHAgent hands the key to the agent listening on SSH_AUTH_SOCK.
+func addToSSHAgent(ctx context.Context, key agent.AddedKey) error {
+	socket := os.Getenv("SSH_AUTH_SOCK")
+	if socket == "" {
+		return errors.New(ctx, "SSH_AUTH_SOCK is not set; start ssh-agent first")
+	}
+	var dialer net.Dialer
+	agentConn, err := dialer.DialContext(ctx, "unix", socket)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "connect to ssh-agent at %s failed", socket)
+	}
+	defer agentConn.Close()
+	if err := agent.NewClient(agentConn).Add(key); err != nil {
+		return errors.Wrapf(ctx, err, "add key to ssh-agent failed")
+	}
+	return nil
+}
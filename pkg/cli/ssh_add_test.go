@@ -0,0 +1,206 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/pem"
+	"net"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+
+	teamvault "github.com/Seibert-Data/teamvault-cli/v5/pkg"
+	"github.com/Seibert-Data/teamvault-cli/v5/pkg/cli"
+	"github.com/Seibert-Data/teamvault-cli/v5/pkg/mocks"
+)
+
+var _ = Describe("ssh-add", func() {
+	var ctx context.Context
+	var fakeConn *mocks.Connector
+	var keyring agent.Agent
+	var listener net.Listener
+	var publicKey ssh.PublicKey
+	var privateKey ed25519.PrivateKey
+	var resetConnector func()
+	var prevSock string
+	var uncached bool
+
+	execute := func(args ...string) error {
+		cmd := cli.NewRootCommand(ctx)
+		cmd.SetArgs(append([]string{"ssh-add"}, args...))
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(&bytes.Buffer{})
+		return cmd.Execute()
+	}
+
+	fileSecret := func(block *pem.Block) teamvault.File {
+		return teamvault.File(base64.StdEncoding.EncodeToString(pem.EncodeToMemory(block)))
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		os.Setenv("STAGING", "true")
+
+		pub, priv, err := ed25519.GenerateKey(rand.Reader)
+		Expect(err).NotTo(HaveOccurred())
+		privateKey = priv
+		publicKey, err = ssh.NewPublicKey(pub)
+		Expect(err).NotTo(HaveOccurred())
+
+		// Unix socket paths are length-limited, so stay out of GinkgoT().TempDir().
+		dir, err := os.MkdirTemp("", "tvssh")
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(func() { os.RemoveAll(dir) })
+		socket := filepath.Join(dir, "agent.sock")
+		listener, err = net.Listen("unix", socket)
+		Expect(err).NotTo(HaveOccurred())
+		keyring = agent.NewKeyring()
+		go func() {
+			for {
+				c, err := listener.Accept()
+				if err != nil {
+					return
+				}
+				go func() {
+					defer c.Close()
+					_ = agent.ServeAgent(keyring, c)
+				}()
+			}
+		}()
+		prevSock = os.Getenv("SSH_AUTH_SOCK")
+		os.Setenv("SSH_AUTH_SOCK", socket)
+
+		fakeConn = &mocks.Connector{}
+		resetConnector = cli.SetNewConnectorForTest(
+			func(sf *cli.SharedFlags) func(context.Context) (teamvault.Connector, error) {
+				uncached = sf.Uncached()
+				return func(ctx context.Context) (teamvault.Connector, error) {
+					return fakeConn, nil
+				}
+			},
+		)
+	})
+
+	AfterEach(func() {
+		resetConnector()
+		listener.Close()
+		os.Setenv("SSH_AUTH_SOCK", prevSock)
+		os.Unsetenv("STAGING")
+	})
+
+	It("adds an unencrypted key with the default comment", func() {
+		block, err := ssh.MarshalPrivateKey(privateKey, "")
+		Expect(err).NotTo(HaveOccurred())
+		fakeConn.FileReturns(fileSecret(block), nil)
+
+		Expect(execute("SSH001")).To(Succeed())
+
+		keys, err := keyring.List()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(keys).To(HaveLen(1))
+		Expect(keys[0].Blob).To(Equal(publicKey.Marshal()))
+		Expect(keys[0].Comment).To(Equal("teamvault:SSH001"))
+		_, key := fakeConn.FileArgsForCall(0)
+		Expect(key).To(Equal(teamvault.Key("SSH001")))
+	})
+
+	It("reads the private key without the disk cache", func() {
+		block, err := ssh.MarshalPrivateKey(privateKey, "")
+		Expect(err).NotTo(HaveOccurred())
+		fakeConn.FileReturns(fileSecret(block), nil)
+
+		Expect(execute("SSH001", "--cache")).To(Succeed())
+
+		Expect(uncached).To(BeTrue())
+	})
+
+	It("decrypts an encrypted key with the passphrase from a second secret", func() {
+		block, err := ssh.MarshalPrivateKeyWithPassphrase(privateKey, "", []byte("hunter2"))
+		Expect(err).NotTo(HaveOccurred())
+		fakeConn.FileReturns(fileSecret(block), nil)
+		fakeConn.PasswordReturns(teamvault.Password("hunter2"), nil)
+
+		Expect(execute(
+			"SSH001",
+			"--passphrase-key",
+			"PASS01",
+			"--lifetime",
+			"1h",
+			"--comment",
+			"deploy",
+		)).To(Succeed())
+
+		_, passKey := fakeConn.PasswordArgsForCall(0)
+		Expect(passKey).To(Equal(teamvault.Key("PASS01")))
+		keys, err := keyring.List()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(keys).To(HaveLen(1))
+		Expect(keys[0].Comment).To(Equal("deploy"))
+	})
+
+	It("asks for --passphrase-key when the key is encrypted", func() {
+		block, err := ssh.MarshalPrivateKeyWithPassphrase(privateKey, "", []byte("hunter2"))
+		Expect(err).NotTo(HaveOccurred())
+		fakeConn.FileReturns(fileSecret(block), nil)
+
+		err = execute("SSH001")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("--passphrase-key"))
+	})
+
+	It("fails without SSH_AUTH_SOCK", func() {
+		block, err := ssh.MarshalPrivateKey(privateKey, "")
+		Expect(err).NotTo(HaveOccurred())
+		fakeConn.FileReturns(fileSecret(block), nil)
+		os.Unsetenv("SSH_AUTH_SOCK")
+
+		err = execute("SSH001")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("SSH_AUTH_SOCK"))
+	})
+
+	It("rejects an invalid lifetime", func() {
+		Expect(execute("SSH001", "--lifetime", "soon")).To(HaveOccurred())
+		Expect(fakeConn.FileCallCount()).To(Equal(0))
+	})
+
+	It("rejects a lifetime the agent cannot hold", func() {
+		err := execute("SSH001", "--lifetime", "2000000h")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("invalid lifetime"))
+		Expect(fakeConn.FileCallCount()).To(Equal(0))
+	})
+
+	It("resolves an alias given as --passphrase-key", func() {
+		dir := GinkgoT().TempDir()
+		configPath := filepath.Join(dir, "config.json")
+		Expect(teamvault.AliasesPath(filepath.Join(dir, "config.aliases.json")).
+			Write(ctx, teamvault.Aliases{"deploy-passphrase": "PASS01"})).To(Succeed())
+		block, err := ssh.MarshalPrivateKeyWithPassphrase(privateKey, "", []byte("hunter2"))
+		Expect(err).NotTo(HaveOccurred())
+		fakeConn.FileReturns(fileSecret(block), nil)
+		fakeConn.PasswordReturns(teamvault.Password("hunter2"), nil)
+
+		Expect(execute(
+			"SSH001",
+			"--passphrase-key",
+			"deploy-passphrase",
+			"--teamvault-config",
+			configPath,
+		)).To(Succeed())
+
+		_, passKey := fakeConn.PasswordArgsForCall(0)
+		Expect(passKey).To(Equal(teamvault.Key("PASS01")))
+	})
+})
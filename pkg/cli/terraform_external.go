@@ -0,0 +1,192 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/bborbe/errors"
+	"github.com/spf13/cobra"
+
+	teamvault "github.com/Seibert-Data/teamvault-cli/v5/pkg"
+)
+
+// createTerraformExternalCommand builds the `terraform-external` subcommand
+// for Terraform's `external` data source: a flat JSON string map query on
+// stdin, a flat JSON string map result on stdout, and on failure a message
+// on stderr plus a non-zero exit, which Terraform surfaces as the error.
+func createTerraformExternalCommand(ctx context.Context, sf *SharedFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "terraform-external",
+		Short: "Resolve secrets for Terraform's external data source (JSON on stdin/stdout)",
+		Long: `Resolve secrets for Terraform's external data source.
+
+The query on stdin takes one of two forms:
+
+  {"key": "AbC123", "fields": "username,password"}
+      returns {"username": "...", "password": "..."}; fields defaults to password.
+
+  {"db_user": "AbC123/username", "db_pass": "AbC123/password"}
+      returns {"db_user": "...", "db_pass": "..."}; a bare key means its password.
+
+Fields: username, password, url, file (decoded content), file_base64.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var query map[string]string
+			if err := json.NewDecoder(cmd.InOrStdin()).Decode(&query); err != nil {
+				return errors.Wrapf(
+					ctx,
+					err,
+					"decode query failed: expected a JSON object of string values",
+				)
+			}
+			keyResolver, err := sf.keyResolver(ctx)
+			if err != nil {
+				return err
+			}
+			lookups, err := parseTerraformQuery(ctx, keyResolver, query)
+			if err != nil {
+				return err
+			}
+			conn, err := newConnector(sf)(ctx)
+			if err != nil {
+				return errors.Wrap(ctx, err, "create connector failed")
+			}
+			// Several names commonly point at the same secret; cache so each
+			// field is fetched once.
+			conn = teamvault.NewCacheConnector(conn)
+			result := make(map[string]string, len(lookups))
+			for name, lookup := range lookups {
+				value, err := readSecretField(ctx, conn, lookup.key, lookup.field)
+				if err != nil {
+					return errors.Wrapf(ctx, err, "resolve %q failed", name)
+				}
+				result[name] = value
+			}
+			return writeTerraformResult(ctx, cmd.OutOrStdout(), result)
+		},
+	}
+	return cmd
+}
+
+// secretFieldLookup names one field of one secret.
+type secretFieldLookup struct {
+	key   teamvault.Key
+	field string
+}
+
+// parseTerraformQuery turns either query form into result name → lookup.
+// Keys may be given as aliases or secret URLs.
+func parseTerraformQuery(
+	ctx context.Context,
+	keyResolver teamvault.KeyResolver,
+	query map[string]string,
+) (map[string]secretFieldLookup, error) {
+	lookups := make(map[string]secretFieldLookup)
+	if key, ok := query["key"]; ok {
+		fields := query["fields"]
+		if fields == "" {
+			fields = "password"
+		}
+		for _, field := range strings.Split(fields, ",") {
+			field = strings.TrimSpace(field)
+			if field == "" {
+				continue
+			}
+			lookups[field] = secretFieldLookup{key: teamvault.Key(key), field: field}
+		}
+	} else {
+		for name, ref := range query {
+			key, field, found := strings.Cut(ref, "/")
+			if !found {
+				field = "password"
+			}
+			lookups[name] = secretFieldLookup{key: teamvault.Key(key), field: field}
+		}
+	}
+	for name, lookup := range lookups {
+		key, err := keyResolver.ResolveKey(ctx, lookup.key.String())
+		if err != nil {
+			return nil, errors.Wrapf(ctx, err, "invalid key for %q", name)
+		}
+		lookups[name] = secretFieldLookup{key: key, field: lookup.field}
+		if !isSecretField(lookup.field) {
+			return nil, errors.Errorf(
+				ctx,
+				"unknown field %q for %q (want username, password, url, file or file_base64)",
+				lookup.field,
+				name,
+			)
+		}
+	}
+	return lookups, nil
+}
+
+func isSecretField(field string) bool {
+	switch field {
+	case "username", "password", "url", "file", "file_base64":
+		return true
+	}
+	return false
+}
+
+// readSecretField fetches a single named field of a secret as a string.
+func readSecretField(
+	ctx context.Context,
+	conn teamvault.Connector,
+	key teamvault.Key,
+	field string,
+) (string, error) {
+	switch field {
+	case "username":
+		user, err := conn.User(ctx, key)
+		if err != nil {
+			return "", errors.Wrap(ctx, err, "get user failed")
+		}
+		return user.String(), nil
+	case "password":
+		pass, err := conn.Password(ctx, key)
+		if err != nil {
+			return "", errors.Wrap(ctx, err, "get password failed")
+		}
+		return pass.String(), nil
+	case "url":
+		u, err := conn.Url(ctx, key)
+		if err != nil {
+			return "", errors.Wrap(ctx, err, "get url failed")
+		}
+		return u.String(), nil
+	case "file", "file_base64":
+		file, err := conn.File(ctx, key)
+		if err != nil {
+			return "", errors.Wrap(ctx, err, "get file failed")
+		}
+		if field == "file_base64" {
+			return file.String(), nil
+		}
+		content, err := file.Content()
+		if err != nil {
+			return "", errors.Wrapf(ctx, err, "decode file of %s failed", key)
+		}
+		return string(content), nil
+	default:
+		return "", errors.Errorf(ctx, "unknown field %q", field)
+	}
+}
+
+func writeTerraformResult(ctx context.Context, out io.Writer, result map[string]string) error {
+	encoded, err := json.Marshal(result)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "marshal json failed")
+	}
+	if _, err := fmt.Fprintf(out, "%s\n", encoded); err != nil {
+		return errors.Wrapf(ctx, err, "write result failed")
+	}
+	return nil
+}
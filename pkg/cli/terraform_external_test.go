@@ -0,0 +1,140 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	teamvault "github.com/Seibert-Data/teamvault-cli/v5/pkg"
+	"github.com/Seibert-Data/teamvault-cli/v5/pkg/cli"
+	"github.com/Seibert-Data/teamvault-cli/v5/pkg/mocks"
+)
+
+var _ = Describe("terraform-external", func() {
+	var ctx context.Context
+	var fakeConn *mocks.Connector
+	var outBuf bytes.Buffer
+	var resetConnector func()
+
+	execute := func(query string, args ...string) (map[string]string, error) {
+		outBuf.Reset()
+		cmd := cli.NewRootCommand(ctx)
+		cmd.SetArgs(append(args, "terraform-external"))
+		cmd.SetIn(strings.NewReader(query))
+		cmd.SetOut(&outBuf)
+		cmd.SetErr(&bytes.Buffer{})
+		if err := cmd.Execute(); err != nil {
+			return nil, err
+		}
+		var result map[string]string
+		Expect(json.Unmarshal(outBuf.Bytes(), &result)).To(Succeed())
+		return result, nil
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		os.Setenv("STAGING", "true")
+		fakeConn = &mocks.Connector{}
+		fakeConn.UserStub = func(ctx context.Context, key teamvault.Key) (teamvault.User, error) {
+			return teamvault.User("user-" + key.String()), nil
+		}
+		fakeConn.PasswordStub = func(ctx context.Context, key teamvault.Key) (teamvault.Password, error) {
+			return teamvault.Password("pass-" + key.String()), nil
+		}
+		fakeConn.UrlReturns(teamvault.Url("https://db.example.com"), nil)
+		fakeConn.FileReturns(
+			teamvault.File(base64.StdEncoding.EncodeToString([]byte("cert"))),
+			nil,
+		)
+		resetConnector = cli.SetNewConnectorForTest(
+			func(sf *cli.SharedFlags) func(context.Context) (teamvault.Connector, error) {
+				return func(ctx context.Context) (teamvault.Connector, error) {
+					return fakeConn, nil
+				}
+			},
+		)
+	})
+
+	AfterEach(func() {
+		resetConnector()
+		os.Unsetenv("STAGING")
+	})
+
+	It("resolves the listed fields of a single key", func() {
+		result, err := execute(`{"key":"AbC123","fields":"username, password,url,file"}`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(map[string]string{
+			"username": "user-AbC123",
+			"password": "pass-AbC123",
+			"url":      "https://db.example.com",
+			"file":     "cert",
+		}))
+	})
+
+	It("defaults fields to password", func() {
+		result, err := execute(`{"key":"AbC123"}`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(map[string]string{"password": "pass-AbC123"}))
+	})
+
+	It("resolves a map of name to key/field and fetches each field once", func() {
+		result, err := execute(`{
+			"db_user": "AbC123/username",
+			"db_pass": "AbC123",
+			"db_pass_again": "AbC123/password",
+			"api_pass": "XyZ789/password",
+			"tls": "XyZ789/file_base64"
+		}`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(map[string]string{
+			"db_user":       "user-AbC123",
+			"db_pass":       "pass-AbC123",
+			"db_pass_again": "pass-AbC123",
+			"api_pass":      "pass-XyZ789",
+			"tls":           base64.StdEncoding.EncodeToString([]byte("cert")),
+		}))
+		Expect(fakeConn.PasswordCallCount()).To(Equal(2))
+	})
+
+	It("resolves aliases in both query forms", func() {
+		dir := GinkgoT().TempDir()
+		configPath := filepath.Join(dir, "config.json")
+		Expect(teamvault.AliasesPath(filepath.Join(dir, "config.aliases.json")).
+			Write(ctx, teamvault.Aliases{"db-prod": "AbC123"})).To(Succeed())
+
+		result, err := execute(`{"key":"db-prod"}`, "--teamvault-config", configPath)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(map[string]string{"password": "pass-AbC123"}))
+
+		result, err = execute(
+			`{"db_user":"db-prod/username"}`,
+			"--teamvault-config",
+			configPath,
+		)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(map[string]string{"db_user": "user-AbC123"}))
+	})
+
+	It("rejects unknown fields before contacting TeamVault", func() {
+		_, err := execute(`{"x":"AbC123/secret"}`)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(`unknown field "secret"`))
+		Expect(fakeConn.PasswordCallCount()).To(Equal(0))
+	})
+
+	It("rejects queries with non-string values", func() {
+		_, err := execute(`{"key":123}`)
+		Expect(err).To(HaveOccurred())
+	})
+})
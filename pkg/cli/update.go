@@ -7,6 +7,7 @@ package cli
 import (
 	"context"
 	"os"
+	"time"
 
 	"github.com/bborbe/errors"
 	"github.com/spf13/cobra"
@@ -23,29 +24,54 @@ func createUpdateCommand(ctx context.Context, sf *SharedFlags) *cobra.Command {
 		description   string
 		passwordStdin bool
 		generate      bool
+		generateLocal bool
 		password      string
 		filePath      string
 		asJSON        bool
+		ifRevision    string
+		ifUnchanged   string
 	)
 
+	var gf *generatorFlags
 	cmd := &cobra.Command{
 		Use:   "update <key>",
 		Short: "Update an existing TeamVault secret",
-		Args:  cobra.ExactArgs(1),
+		Long: `Update an existing TeamVault secret.
+
+Only the given fields are changed. --if-revision and --if-unchanged-since
+guard against overwriting a concurrent change: the update fails with a
+revision conflict when the secret's current revision is no longer the given
+one (see the revision command), or when it was changed after the given
+RFC 3339 time.`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeKeyArg(ctx, sf),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			key := teamvault.Key(args[0])
-			if err := key.Validate(ctx); err != nil {
+			keyResolver, err := sf.keyResolver(ctx)
+			if err != nil {
+				return err
+			}
+			key, err := keyResolver.ResolveKey(ctx, args[0])
+			if err != nil {
 				return errors.Wrap(ctx, err, "invalid key")
 			}
 
 			// Mutual exclusion check: at most one value flag allowed
-			valueFlags := countValueFlags(passwordStdin, generate, password != "", filePath != "")
+			valueFlags := countValueFlags(
+				passwordStdin,
+				generate,
+				generateLocal,
+				password != "",
+				filePath != "",
+			)
 			if valueFlags > 1 {
 				return errors.New(
 					ctx,
-					"--password-stdin, --generate, --password, and --file are mutually exclusive",
+					"--password-stdin, --generate, --generate-local, --password, and --file are mutually exclusive",
 				)
 			}
+			if !generateLocal && gf.changed(cmd) {
+				return errors.New(ctx, "password policy flags require --generate-local")
+			}
 
 			// Build UpdateSecret — only set fields that were actually passed
 			var secret teamvault.UpdateSecret
@@ -61,6 +87,14 @@ func createUpdateCommand(ctx context.Context, sf *SharedFlags) *cobra.Command {
 			if cmd.Flags().Changed("description") {
 				secret.Description = &description
 			}
+			secret.ExpectedRevision = teamvault.CurrentRevision(ifRevision)
+			if ifUnchanged != "" {
+				since, err := time.Parse(time.RFC3339, ifUnchanged)
+				if err != nil {
+					return errors.Wrapf(ctx, err, "invalid --if-unchanged-since %q", ifUnchanged)
+				}
+				secret.UnchangedSince = since
+			}
 
 			switch {
 			case filePath != "":
@@ -88,6 +122,13 @@ func createUpdateCommand(ctx context.Context, sf *SharedFlags) *cobra.Command {
 				}
 				secret.Password = &pw
 
+			case generateLocal:
+				pw, err := gf.generate(ctx, cmd)
+				if err != nil {
+					return errors.Wrap(ctx, err, "generate password failed")
+				}
+				secret.Password = &pw
+
 			case password != "":
 				pw := teamvault.Password(password)
 				secret.Password = &pw
@@ -112,6 +153,13 @@ func createUpdateCommand(ctx context.Context, sf *SharedFlags) *cobra.Command {
 	cmd.Flags().StringVar(&description, "description", "", "description")
 	cmd.Flags().BoolVar(&passwordStdin, "password-stdin", false, "read password from stdin")
 	cmd.Flags().BoolVar(&generate, "generate", false, "generate a secure password via the server")
+	cmd.Flags().BoolVar(
+		&generateLocal,
+		"generate-local",
+		false,
+		"generate a password locally with the policy flags (see the generate command)",
+	)
+	gf = addGeneratorFlags(cmd)
 	cmd.Flags().StringVar(
 		&password,
 		"password",
@@ -120,6 +168,18 @@ func createUpdateCommand(ctx context.Context, sf *SharedFlags) *cobra.Command {
 	)
 	cmd.Flags().StringVar(&filePath, "file", "", "path to a file (content is base64-encoded)")
 	cmd.Flags().BoolVar(&asJSON, "json", false, "print output as JSON object")
+	cmd.Flags().StringVar(
+		&ifRevision,
+		"if-revision",
+		"",
+		"only update if the current revision is still this one (id or API url)",
+	)
+	cmd.Flags().StringVar(
+		&ifUnchanged,
+		"if-unchanged-since",
+		"",
+		"only update if the secret was not changed after this RFC 3339 time",
+	)
 
 	return cmd
 }
@@ -7,8 +7,11 @@ package cli_test
 import (
 	"bytes"
 	"context"
+	"errors"
 	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -85,6 +88,38 @@ var _ = Describe("update", func() {
 			_, key, _ := mockWriter.UpdateArgsForCall(0)
 			Expect(string(key)).To(Equal("mykey"))
 		})
+
+		It("resolves aliases and secret URLs", func() {
+			dir := GinkgoT().TempDir()
+			configPath := filepath.Join(dir, "config.json")
+			Expect(teamvault.AliasesPath(filepath.Join(dir, "config.aliases.json")).
+				Write(ctx, teamvault.Aliases{"prod-db": "AbC123"})).To(Succeed())
+			mockWriter := &mocks.Writer{}
+			cli.SetNewWriterForTest(
+				func(sf *cli.SharedFlags) func(context.Context) (teamvault.Writer, error) {
+					return func(ctx context.Context) (teamvault.Writer, error) {
+						return mockWriter, nil
+					}
+				},
+			)
+			defer cli.ResetNewWriterForTest()
+
+			for _, arg := range []string{"prod-db", "https://vault.example.com/secrets/AbC123/"} {
+				cmd := cli.NewRootCommand(ctx)
+				cmd.SetArgs([]string{
+					"update", arg, "--description", "d", "--teamvault-config", configPath,
+				})
+				cmd.SetOut(&bytes.Buffer{})
+				cmd.SetErr(&bytes.Buffer{})
+				Expect(cmd.Execute()).To(Succeed())
+			}
+
+			Expect(mockWriter.UpdateCallCount()).To(Equal(2))
+			_, key, _ := mockWriter.UpdateArgsForCall(0)
+			Expect(key).To(Equal(teamvault.Key("AbC123")))
+			_, key, _ = mockWriter.UpdateArgsForCall(1)
+			Expect(key).To(Equal(teamvault.Key("AbC123")))
+		})
 	})
 
 	Describe("metadata-only update omits secret_data", func() {
@@ -383,6 +418,50 @@ var _ = Describe("update", func() {
 		})
 	})
 
+	Describe("--generate-local", func() {
+		It("generates the password locally with the policy flags", func() {
+			mockWriter := &mocks.Writer{}
+			mockWriter.UpdateReturns(
+				teamvault.Key("K"),
+				teamvault.ApiUrl("http://h/api/secrets/K/"),
+				nil,
+			)
+
+			cmd := cli.NewRootCommand(ctx)
+			cmd.SetArgs([]string{
+				"update", "K", "--generate-local", "--length", "16", "--classes", "digits",
+			})
+			cmd.SetOut(&bytes.Buffer{})
+			cmd.SetErr(&bytes.Buffer{})
+
+			cli.SetNewWriterForTest(
+				func(sf *cli.SharedFlags) func(context.Context) (teamvault.Writer, error) {
+					return func(ctx context.Context) (teamvault.Writer, error) {
+						return mockWriter, nil
+					}
+				},
+			)
+			defer cli.ResetNewWriterForTest()
+
+			Expect(cmd.Execute()).To(Succeed())
+			_, _, secret := mockWriter.UpdateArgsForCall(0)
+			Expect(secret.Password).NotTo(BeNil())
+			Expect(string(*secret.Password)).To(MatchRegexp(`^[0-9]{16}$`))
+			Expect(mockWriter.GeneratePasswordCallCount()).To(Equal(0))
+		})
+
+		It("rejects policy flags without --generate-local", func() {
+			cmd := cli.NewRootCommand(ctx)
+			cmd.SetArgs([]string{"update", "K", "--length", "16"})
+			cmd.SetOut(&bytes.Buffer{})
+			cmd.SetErr(&bytes.Buffer{})
+
+			err := cmd.Execute()
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(ContainSubstring("require --generate-local"))
+		})
+	})
+
 	Describe("--password literal", func() {
 		It("passes literal password to Update", func() {
 			mockWriter := &mocks.Writer{}
@@ -448,4 +527,62 @@ var _ = Describe("update", func() {
 			Expect(*secret.Url).To(Equal("https://example.com"))
 		})
 	})
+
+	Describe("preconditions", func() {
+		var mockWriter *mocks.Writer
+
+		update := func(args ...string) error {
+			cmd := cli.NewRootCommand(ctx)
+			cmd.SetArgs(append([]string{"update", "K", "--name", "renamed"}, args...))
+			cmd.SetOut(&bytes.Buffer{})
+			cmd.SetErr(&bytes.Buffer{})
+			return cmd.Execute()
+		}
+
+		BeforeEach(func() {
+			mockWriter = &mocks.Writer{}
+			cli.SetNewWriterForTest(
+				func(sf *cli.SharedFlags) func(context.Context) (teamvault.Writer, error) {
+					return func(ctx context.Context) (teamvault.Writer, error) {
+						return mockWriter, nil
+					}
+				},
+			)
+		})
+
+		AfterEach(func() {
+			cli.ResetNewWriterForTest()
+		})
+
+		It("passes --if-revision as ExpectedRevision", func() {
+			Expect(update("--if-revision", "rev1")).To(Succeed())
+
+			_, _, secret := mockWriter.UpdateArgsForCall(0)
+			Expect(secret.ExpectedRevision).To(Equal(teamvault.CurrentRevision("rev1")))
+			Expect(secret.UnchangedSince.IsZero()).To(BeTrue())
+		})
+
+		It("passes --if-unchanged-since as UnchangedSince", func() {
+			Expect(update("--if-unchanged-since", "2026-03-01T12:00:00Z")).To(Succeed())
+
+			_, _, secret := mockWriter.UpdateArgsForCall(0)
+			Expect(secret.UnchangedSince).To(Equal(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)))
+			Expect(secret.ExpectedRevision).To(BeEmpty())
+		})
+
+		It("rejects an invalid --if-unchanged-since", func() {
+			err := update("--if-unchanged-since", "yesterday")
+
+			Expect(err).NotTo(BeNil())
+			Expect(mockWriter.UpdateCallCount()).To(Equal(0))
+		})
+
+		It("keeps ErrRevisionConflict detectable", func() {
+			mockWriter.UpdateReturns("", "", teamvault.ErrRevisionConflict)
+
+			err := update("--if-revision", "rev1")
+
+			Expect(errors.Is(err, teamvault.ErrRevisionConflict)).To(BeTrue())
+		})
+	})
 })
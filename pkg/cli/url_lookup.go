@@ -0,0 +1,74 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cli
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/bborbe/errors"
+
+	teamvault "github.com/Seibert-Data/teamvault-cli/v5/pkg"
+)
+
+// parseLooseURL parses a URL that may lack a scheme (e.g. "registry.example.com"
+// or "git.example.com/org/repo"), so the host lands in url.URL.Host instead of
+// the path. The Scheme of the result stays empty when raw has none.
+func parseLooseURL(raw string) (*url.URL, error) {
+	s := strings.TrimSpace(raw)
+	hasScheme := strings.Contains(s, "://")
+	if !hasScheme {
+		s = "https://" + s
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	if !hasScheme {
+		u.Scheme = ""
+	}
+	u.Host = strings.ToLower(u.Host)
+	return u, nil
+}
+
+// searchByURL runs a TeamVault search for query and keeps only the results
+// whose Url field parses and satisfies match. The server-side search narrows
+// the candidate set; the Url check makes the final decision.
+func searchByURL(
+	ctx context.Context,
+	conn teamvault.Connector,
+	query string,
+	match func(*url.URL) bool,
+) ([]teamvault.SearchResult, error) {
+	results, err := conn.Search(ctx, query)
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "search secrets for %q failed", query)
+	}
+	var matches []teamvault.SearchResult
+	for _, r := range results {
+		if r.Url == "" {
+			continue
+		}
+		u, err := parseLooseURL(r.Url.String())
+		if err != nil {
+			continue
+		}
+		if match(u) {
+			matches = append(matches, r)
+		}
+	}
+	return matches, nil
+}
+
+// joinKeys renders search results as a comma-separated key list for
+// ambiguity errors.
+func joinKeys(results []teamvault.SearchResult) string {
+	keys := make([]string, 0, len(results))
+	for _, r := range results {
+		keys = append(keys, r.Key.String())
+	}
+	return strings.Join(keys, ", ")
+}
@@ -14,6 +14,7 @@ import (
 	"text/template"
 
 	"github.com/bborbe/errors"
+	libtime "github.com/bborbe/time"
 	"github.com/golang/glog"
 )
 
@@ -25,16 +26,29 @@ type ConfigParser interface {
 }
 
 // NewConfigParser creates a new ConfigParser with the given TeamVault Connector.
+// Template functions accept keys and secret URLs of any host; use
+// NewConfigParserWithKeyResolver to restrict URLs to the configured vault.
 func NewConfigParser(
 	teamvaultConnector Connector,
+) ConfigParser {
+	return NewConfigParserWithKeyResolver(teamvaultConnector, NewKeyResolver(""))
+}
+
+// NewConfigParserWithKeyResolver creates a new ConfigParser resolving the
+// template function arguments with the given KeyResolver.
+func NewConfigParserWithKeyResolver(
+	teamvaultConnector Connector,
+	keyResolver KeyResolver,
 ) ConfigParser {
 	return &configParser{
 		teamvaultConnector: teamvaultConnector,
+		keyResolver:        keyResolver,
 	}
 }
 
 type configParser struct {
 	teamvaultConnector Connector
+	keyResolver        KeyResolver
 }
 
 func (c *configParser) Parse(ctx context.Context, content []byte) ([]byte, error) {
@@ -52,7 +66,7 @@ func (c *configParser) Parse(ctx context.Context, content []byte) ([]byte, error
 }
 
 func (c *configParser) createFuncMap(ctx context.Context) template.FuncMap {
-	return template.FuncMap{
+	funcs := template.FuncMap{
 		"indent": func(spaces int, v string) string {
 			pad := strings.Repeat(" ", spaces)
 			return pad + strings.ReplaceAll(v, "\n", "\n"+pad)
@@ -101,9 +115,9 @@ func (c *configParser) createFuncMap(ctx context.Context) template.FuncMap {
 			if !ok {
 				return "", errors.New(ctx, "expected string value")
 			}
-			key := Key(str)
-			if err := key.Validate(ctx); err != nil {
-				return nil, errors.Wrapf(ctx, err, "key '%s' invalid", key)
+			key, err := c.keyResolver.ResolveKey(ctx, str)
+			if err != nil {
+				return nil, errors.Wrapf(ctx, err, "key '%s' invalid", str)
 			}
 			user, err := c.teamvaultConnector.User(ctx, key)
 			if err != nil {
@@ -122,9 +136,9 @@ func (c *configParser) createFuncMap(ctx context.Context) template.FuncMap {
 			if !ok {
 				return "", errors.New(ctx, "expected string value")
 			}
-			key := Key(str)
-			if err := key.Validate(ctx); err != nil {
-				return nil, errors.Wrapf(ctx, err, "key '%s' invalid", key)
+			key, err := c.keyResolver.ResolveKey(ctx, str)
+			if err != nil {
+				return nil, errors.Wrapf(ctx, err, "key '%s' invalid", str)
 			}
 			pass, err := c.teamvaultConnector.Password(ctx, key)
 			if err != nil {
@@ -148,16 +162,43 @@ func (c *configParser) createFuncMap(ctx context.Context) template.FuncMap {
 			if !ok {
 				return "", errors.New(ctx, "expected string value")
 			}
+			key, err := c.keyResolver.ResolveKey(ctx, str)
+			if err != nil {
+				return nil, errors.Wrapf(ctx, err, "key '%s' invalid", str)
+			}
 			htpasswd := NewHtpasswdGenerator(
 				c.teamvaultConnector,
 			)
-			content, err := htpasswd.Generate(ctx, Key(str))
+			content, err := htpasswd.Generate(ctx, key)
 			if err != nil {
 				return "", errors.Wrapf(ctx, err, "generate htpasswd failed")
 			}
 			glog.V(4).Infof("htpasswd generated successfully for key %v", val)
 			return string(content), nil
 		},
+		"teamvaultTotp": func(val interface{}) (interface{}, error) {
+			glog.V(4).Infof("get teamvault value for %v", val)
+			if val == nil {
+				return "", nil
+			}
+			str, ok := val.(string)
+			if !ok {
+				return "", errors.New(ctx, "expected string value")
+			}
+			key, err := c.keyResolver.ResolveKey(ctx, str)
+			if err != nil {
+				return nil, errors.Wrapf(ctx, err, "key '%s' invalid", str)
+			}
+			totp, err := NewTotpGenerator(
+				c.teamvaultConnector,
+				libtime.NewCurrentDateTime(),
+			).Generate(ctx, key)
+			if err != nil {
+				return "", errors.Wrapf(ctx, err, "generate totp for key %v failed", key)
+			}
+			glog.V(4).Infof("totp generated successfully for key %v", key)
+			return totp.Code, nil
+		},
 		"teamvaultUrl": func(val interface{}) (interface{}, error) {
 			glog.V(4).Infof("get teamvault value for %v", val)
 			if val == nil {
@@ -167,9 +208,9 @@ func (c *configParser) createFuncMap(ctx context.Context) template.FuncMap {
 			if !ok {
 				return "", errors.New(ctx, "expected string value")
 			}
-			key := Key(str)
-			if err := key.Validate(ctx); err != nil {
-				return nil, errors.Wrapf(ctx, err, "key '%s' invalid", key)
+			key, err := c.keyResolver.ResolveKey(ctx, str)
+			if err != nil {
+				return nil, errors.Wrapf(ctx, err, "key '%s' invalid", str)
 			}
 			pass, err := c.teamvaultConnector.Url(ctx, key)
 			if err != nil {
@@ -188,9 +229,9 @@ func (c *configParser) createFuncMap(ctx context.Context) template.FuncMap {
 			if !ok {
 				return "", errors.New(ctx, "expected string value")
 			}
-			key := Key(str)
-			if err := key.Validate(ctx); err != nil {
-				return nil, errors.Wrapf(ctx, err, "key '%s' invalid", key)
+			key, err := c.keyResolver.ResolveKey(ctx, str)
+			if err != nil {
+				return nil, errors.Wrapf(ctx, err, "key '%s' invalid", str)
 			}
 			file, err := c.teamvaultConnector.File(ctx, key)
 			if err != nil {
@@ -218,9 +259,9 @@ func (c *configParser) createFuncMap(ctx context.Context) template.FuncMap {
 			if !ok {
 				return "", errors.New(ctx, "expected string value")
 			}
-			key := Key(str)
-			if err := key.Validate(ctx); err != nil {
-				return nil, errors.Wrapf(ctx, err, "key '%s' invalid", key)
+			key, err := c.keyResolver.ResolveKey(ctx, str)
+			if err != nil {
+				return nil, errors.Wrapf(ctx, err, "key '%s' invalid", str)
 			}
 			file, err := c.teamvaultConnector.File(ctx, key)
 			if err != nil {
@@ -281,4 +322,43 @@ func (c *configParser) createFuncMap(ctx context.Context) template.FuncMap {
 			return strings.ToUpper(str), nil
 		},
 	}
+	c.addByNameFuncs(ctx, funcs)
+	return funcs
+}
+
+// byNameFuncs are the key-based template functions that also get a
+// `<name>ByName` variant taking a secret name instead of a key.
+var byNameFuncs = []string{
+	"teamvaultUser",
+	"teamvaultPassword",
+	"teamvaultHtpasswd",
+	"teamvaultTotp",
+	"teamvaultUrl",
+	"teamvaultFile",
+	"teamvaultFileBase64",
+}
+
+// addByNameFuncs registers the `teamvault*ByName` functions. The name must
+// match exactly one secret; rendering fails with the candidates otherwise.
+// Fuzzy matching is deliberately not offered for templates.
+func (c *configParser) addByNameFuncs(ctx context.Context, funcs template.FuncMap) {
+	resolver := NewNameResolver(c.teamvaultConnector, false)
+	for _, name := range byNameFuncs {
+		byKey := funcs[name].(func(interface{}) (interface{}, error))
+		funcs[name+"ByName"] = func(val interface{}) (interface{}, error) {
+			glog.V(4).Infof("resolve teamvault name %v", val)
+			if val == nil {
+				return "", nil
+			}
+			str, ok := val.(string)
+			if !ok {
+				return "", errors.New(ctx, "expected string value")
+			}
+			key, err := resolver.Resolve(ctx, str)
+			if err != nil {
+				return "", errors.Wrapf(ctx, err, "resolve teamvault name failed")
+			}
+			return byKey(key.String())
+		}
+	}
 }
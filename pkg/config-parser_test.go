@@ -166,6 +166,82 @@ var _ = Describe("Parser", func() {
 				Expect(result).To(HaveLen(68))
 			})
 		})
+		Context("content teamvault totp", func() {
+			BeforeEach(func() {
+				connector.PasswordReturns(
+					"otpauth://totp/x?secret=GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ&digits=8",
+					nil,
+				)
+				content = []byte(`{{ "key123" | teamvaultTotp }}`)
+			})
+			It("returns no error", func() {
+				Expect(err).To(BeNil())
+			})
+			It("correct result", func() {
+				Expect(string(result)).To(MatchRegexp(`^[0-9]{8}$`))
+			})
+		})
+		Context("content teamvault password by url", func() {
+			BeforeEach(func() {
+				connector.PasswordReturns("mypass", nil)
+				content = []byte(
+					`{{ "https://vault.example.com/secrets/key123/" | teamvaultPassword }}`,
+				)
+			})
+			It("returns no error", func() {
+				Expect(err).To(BeNil())
+			})
+			It("reads the key from the url", func() {
+				_, key := connector.PasswordArgsForCall(0)
+				Expect(key).To(Equal(teamvault.Key("key123")))
+			})
+		})
+		Context("content teamvault password by url of another vault", func() {
+			BeforeEach(func() {
+				parser = teamvault.NewConfigParserWithKeyResolver(
+					connector,
+					teamvault.NewKeyResolver("https://vault.example.com"),
+				)
+				content = []byte(
+					`{{ "https://other.example.com/secrets/key123/" | teamvaultPassword }}`,
+				)
+			})
+			It("returns error", func() {
+				Expect(err).NotTo(BeNil())
+			})
+			It("reads nothing", func() {
+				Expect(connector.PasswordCallCount()).To(Equal(0))
+			})
+		})
+		Context("content teamvault password by name", func() {
+			BeforeEach(func() {
+				connector.SearchReturns([]teamvault.SearchResult{
+					{Key: "key123", Name: "prod db"},
+					{Key: "key456", Name: "prod db replica"},
+				}, nil)
+				connector.PasswordReturns("mypass", nil)
+				content = []byte(`{{ "prod db" | teamvaultPasswordByName }}`)
+			})
+			It("returns no error", func() {
+				Expect(err).To(BeNil())
+			})
+			It("correct result", func() {
+				Expect(result).To(Equal([]byte("mypass")))
+			})
+			It("reads the resolved key", func() {
+				_, key := connector.PasswordArgsForCall(0)
+				Expect(key).To(Equal(teamvault.Key("key123")))
+			})
+		})
+		Context("content teamvault user by unknown name", func() {
+			BeforeEach(func() {
+				connector.SearchReturns(nil, nil)
+				content = []byte(`{{ "prod db" | teamvaultUserByName }}`)
+			})
+			It("returns error", func() {
+				Expect(err).NotTo(BeNil())
+			})
+		})
 		Context("content teamvault file", func() {
 			var f *os.File
 			BeforeEach(func() {
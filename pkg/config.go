@@ -13,4 +13,23 @@ type Config struct {
 	Password     Password         `json:"pass"`
 	CacheEnabled bool             `json:"cacheEnabled,omitempty"`
 	Timeout      libtime.Duration `json:"timeout,omitempty"`
+	// DockerRegistries maps registry hostnames (e.g. "registry.example.com")
+	// to the TeamVault keys holding their credentials, for the
+	// docker-credential helper.
+	DockerRegistries map[string]Key `json:"dockerRegistries,omitempty"`
+	// GitCredentials maps URL prefixes (e.g. "https://git.example.com/org")
+	// to TeamVault keys for the git credential helper; the longest matching
+	// prefix wins.
+	GitCredentials map[string]Key `json:"gitCredentials,omitempty"`
+	// Askpass lists the prompt patterns the askpass helper answers, in
+	// order; the first matching rule wins.
+	Askpass []AskpassRule `json:"askpass,omitempty"`
+}
+
+// AskpassRule maps prompts matching Pattern (a Go regular expression) to the
+// TeamVault key whose password answers them. Key may also be an alias or a
+// secret URL.
+type AskpassRule struct {
+	Pattern string `json:"pattern"`
+	Key     Key    `json:"key"`
 }
@@ -4,6 +4,8 @@
 
 package teamvault
 
+import "strings"
+
 // CurrentRevision represents the current revision identifier of a TeamVault secret.
 type CurrentRevision string
 
@@ -11,3 +13,13 @@ type CurrentRevision string
 func (t CurrentRevision) String() string {
 	return string(t)
 }
+
+// ID returns the revision id, the last path segment of the revision's API
+// URL. A bare id is returned unchanged.
+func (t CurrentRevision) ID() string {
+	trimmed := strings.TrimRight(t.String(), "/")
+	if i := strings.LastIndex(trimmed, "/"); i >= 0 {
+		return trimmed[i+1:]
+	}
+	return trimmed
+}
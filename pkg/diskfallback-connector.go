@@ -6,6 +6,7 @@ package teamvault
 
 import (
 	"context"
+	"iter"
 	"os"
 	"path/filepath"
 
@@ -89,6 +90,45 @@ func (d *diskFallback) Search(ctx context.Context, key string) ([]SearchResult,
 	return d.connector.Search(ctx, key)
 }
 
+// Metadata reads the metadata of the wrapped connector; it is never cached,
+// so revision checks see the server's state.
+func (d *diskFallback) Metadata(ctx context.Context, key Key) (SecretMetadata, error) {
+	return ReadMetadata(ctx, d.connector, key)
+}
+
+// SearchSeq streams the search of the wrapped connector; see SearchSeq.
+func (d *diskFallback) SearchSeq(
+	ctx context.Context,
+	query SearchQuery,
+) iter.Seq2[SearchResult, error] {
+	return SearchSeq(ctx, d.connector, query)
+}
+
+// ListCachedKeys returns the keys the disk fallback holds values for, so
+// callers can offer them while TeamVault is unreachable. A missing cache
+// directory yields no keys.
+func ListCachedKeys(ctx context.Context) ([]Key, error) {
+	entries, err := os.ReadDir(filepath.Join(os.Getenv("HOME"), ".teamvault-cache"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(ctx, err, "read cache dir failed")
+	}
+	var keys []Key
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		key := Key(entry.Name())
+		if key.Validate(ctx) != nil {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
 func cachefile(key Key, kind string) string {
 	return filepath.Join(os.Getenv("HOME"), ".teamvault-cache", key.String(), kind)
 }
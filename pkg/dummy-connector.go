@@ -44,3 +44,18 @@ func (t *dummyConnector) File(ctx context.Context, key Key) (File, error) {
 func (t *dummyConnector) Search(ctx context.Context, search string) ([]SearchResult, error) {
 	return nil, nil
 }
+
+func (t *dummyConnector) Metadata(ctx context.Context, key Key) (SecretMetadata, error) {
+	url, err := t.Url(ctx, key)
+	if err != nil {
+		return SecretMetadata{}, err
+	}
+	return SecretMetadata{
+		Key:             key,
+		Name:            key.String(),
+		Username:        key.String(),
+		Url:             url,
+		ContentType:     ContentTypePassword,
+		CurrentRevision: CurrentRevision(key.String() + "-revision"),
+	}, nil
+}
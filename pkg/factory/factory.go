@@ -59,6 +59,71 @@ func CreateConnectorWithConfigAndTimeout(
 	currentDateTime libtime.CurrentDateTime,
 	keychain teamvault.Keychain,
 	cliTimeout libtime.Duration,
+) (teamvault.Connector, error) {
+	return createConnectorWithConfigAndTimeout(
+		ctx,
+		httpClient,
+		configPath,
+		apiURL,
+		apiUser,
+		apiPassword,
+		staging,
+		cacheEnabled,
+		true,
+		currentDateTime,
+		keychain,
+		cliTimeout,
+	)
+}
+
+// CreateUncachedConnectorWithConfigAndTimeout is like
+// CreateConnectorWithConfigAndTimeout but never uses the disk cache, even if
+// the config file enables it. Bulk reads (backups, audits, mirrors,
+// rotations) use it so values are neither written to the unencrypted cache
+// nor silently replaced by stale cached copies when the server fails.
+func CreateUncachedConnectorWithConfigAndTimeout(
+	ctx context.Context,
+	httpClient *http.Client,
+	configPath teamvault.TeamvaultConfigPath,
+	apiURL teamvault.Url,
+	apiUser teamvault.User,
+	apiPassword teamvault.Password,
+	staging teamvault.Staging,
+	currentDateTime libtime.CurrentDateTime,
+	keychain teamvault.Keychain,
+	cliTimeout libtime.Duration,
+) (teamvault.Connector, error) {
+	return createConnectorWithConfigAndTimeout(
+		ctx,
+		httpClient,
+		configPath,
+		apiURL,
+		apiUser,
+		apiPassword,
+		staging,
+		false,
+		false,
+		currentDateTime,
+		keychain,
+		cliTimeout,
+	)
+}
+
+// createConnectorWithConfigAndTimeout builds the connector; the config
+// file's cacheEnabled only counts if configCache is set.
+func createConnectorWithConfigAndTimeout(
+	ctx context.Context,
+	httpClient *http.Client,
+	configPath teamvault.TeamvaultConfigPath,
+	apiURL teamvault.Url,
+	apiUser teamvault.User,
+	apiPassword teamvault.Password,
+	staging teamvault.Staging,
+	cacheEnabled bool,
+	configCache bool,
+	currentDateTime libtime.CurrentDateTime,
+	keychain teamvault.Keychain,
+	cliTimeout libtime.Duration,
 ) (teamvault.Connector, error) {
 	var config *teamvault.Config
 	if configPath.Exists() {
@@ -70,7 +135,7 @@ func CreateConnectorWithConfigAndTimeout(
 		apiURL = config.Url
 		apiUser = config.User
 		apiPassword = config.Password
-		cacheEnabled = cacheEnabled || config.CacheEnabled
+		cacheEnabled = cacheEnabled || (configCache && config.CacheEnabled)
 	}
 	if cliTimeout.Duration() < 0 {
 		return nil, errors.Errorf(ctx, "invalid timeout %v: must be >= 0", cliTimeout.Duration())
@@ -8,7 +8,9 @@ import (
 	"context"
 	stderrors "errors"
 	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"time"
 
 	libtime "github.com/bborbe/time"
@@ -537,4 +539,64 @@ var _ = Describe("Factory", func() {
 			})
 		})
 	})
+
+	Describe("CreateUncachedConnectorWithConfigAndTimeout", func() {
+		var configPath string
+		var server *httptest.Server
+
+		BeforeEach(func() {
+			server = httptest.NewServer(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusInternalServerError)
+				}),
+			)
+			DeferCleanup(server.Close)
+			home := GinkgoT().TempDir()
+			GinkgoT().Setenv("HOME", home)
+			cacheDir := filepath.Join(home, ".teamvault-cache", "AbC123")
+			Expect(os.MkdirAll(cacheDir, 0700)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(cacheDir, "password"), []byte("cached-pw"), 0600)).
+				To(Succeed())
+			configPath = filepath.Join(home, "teamvault.json")
+			Expect(os.WriteFile(
+				configPath,
+				[]byte(`{"url":"`+server.URL+`","user":"admin","pass":"pwd","cacheEnabled":true}`),
+				0600,
+			)).To(Succeed())
+		})
+
+		It("ignores the cache enabled in the config file", func() {
+			cached, err := factory.CreateConnectorWithConfigAndTimeout(
+				ctx,
+				httpClient,
+				teamvault.TeamvaultConfigPath(configPath),
+				teamvault.Url(""),
+				teamvault.User(""),
+				teamvault.Password(""),
+				teamvault.Staging(false),
+				false,
+				currentDateTime,
+				fakeKeychain,
+				libtime.Duration(0),
+			)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cached.Password(ctx, "AbC123")).To(Equal(teamvault.Password("cached-pw")))
+
+			uncached, err := factory.CreateUncachedConnectorWithConfigAndTimeout(
+				ctx,
+				httpClient,
+				teamvault.TeamvaultConfigPath(configPath),
+				teamvault.Url(""),
+				teamvault.User(""),
+				teamvault.Password(""),
+				teamvault.Staging(false),
+				currentDateTime,
+				fakeKeychain,
+				libtime.Duration(0),
+			)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = uncached.Password(ctx, "AbC123")
+			Expect(err).To(HaveOccurred())
+		})
+	})
 })
@@ -0,0 +1,223 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package teamvault
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"strings"
+
+	"github.com/bborbe/errors"
+)
+
+// ImportRecord is one secret read from an import file. Row is its 1-based
+// position in the file (the data row for CSV, the entry for KeePass).
+type ImportRecord struct {
+	Row         int
+	Name        string
+	Username    string
+	Url         string
+	Description string
+	Password    Password
+}
+
+// ImportFields are the record fields a CSV column can map to.
+var ImportFields = []string{"name", "username", "url", "description", "password"}
+
+// ImportColumns maps record fields to CSV header names, e.g.
+// {"name": "Title", "password": "Secret"}. Fields not mapped are read
+// from the column named like the field, ignoring case.
+type ImportColumns map[string]string
+
+// ParseImportColumns parses a "field=Header,field=Header" mapping.
+func ParseImportColumns(ctx context.Context, value string) (ImportColumns, error) {
+	columns := ImportColumns{}
+	if strings.TrimSpace(value) == "" {
+		return columns, nil
+	}
+	for _, pair := range strings.Split(value, ",") {
+		field, header, ok := strings.Cut(pair, "=")
+		field = strings.ToLower(strings.TrimSpace(field))
+		if !ok || !isImportField(field) || strings.TrimSpace(header) == "" {
+			return nil, errors.Errorf(
+				ctx,
+				"invalid column mapping %q: use field=Header with field one of %s",
+				pair,
+				strings.Join(ImportFields, ", "),
+			)
+		}
+		columns[field] = strings.TrimSpace(header)
+	}
+	return columns, nil
+}
+
+func isImportField(field string) bool {
+	for _, f := range ImportFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseImportCSV reads records from CSV with a header row.
+func ParseImportCSV(
+	ctx context.Context,
+	reader io.Reader,
+	columns ImportColumns,
+) ([]ImportRecord, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1
+	header, err := csvReader.Read()
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "read csv header failed")
+	}
+	// Spreadsheet exports often start with a byte order mark.
+	index := make(map[string]int, len(ImportFields))
+	for _, field := range ImportFields {
+		want := field
+		if mapped, ok := columns[field]; ok {
+			want = mapped
+		}
+		for i, name := range header {
+			if strings.EqualFold(strings.TrimSpace(strings.TrimPrefix(name, "\uFEFF")), want) {
+				index[field] = i
+				break
+			}
+		}
+		if _, ok := index[field]; !ok && columns[field] != "" {
+			return nil, errors.Errorf(ctx, "csv has no column %q for %s", want, field)
+		}
+	}
+	if _, ok := index["name"]; !ok {
+		return nil, errors.New(ctx, "csv has no name column; map one with name=<header>")
+	}
+
+	var records []ImportRecord
+	for row := 1; ; row++ {
+		values, err := csvReader.Read()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, errors.Wrapf(ctx, err, "read csv row %d failed", row)
+		}
+		get := func(field string) string {
+			return strings.TrimSpace(valueAt(values, index, field))
+		}
+		records = append(records, ImportRecord{
+			Row:         row,
+			Name:        get("name"),
+			Username:    get("username"),
+			Url:         get("url"),
+			Description: get("description"),
+			// Passwords are taken verbatim; whitespace may be significant.
+			Password: Password(valueAt(values, index, "password")),
+		})
+	}
+}
+
+// valueAt returns the value of field's column, or "" if the field is not
+// mapped or the row is short.
+func valueAt(values []string, index map[string]int, field string) string {
+	if i, ok := index[field]; ok && i < len(values) {
+		return values[i]
+	}
+	return ""
+}
+
+// ParseImportJSON reads records from a JSON array of objects with the keys
+// name, username, url, description and password.
+func ParseImportJSON(ctx context.Context, reader io.Reader) ([]ImportRecord, error) {
+	var items []struct {
+		Name        string `json:"name"`
+		Username    string `json:"username"`
+		Url         string `json:"url"`
+		Description string `json:"description"`
+		Password    string `json:"password"`
+	}
+	if err := json.NewDecoder(reader).Decode(&items); err != nil {
+		return nil, errors.Wrapf(ctx, err, "parse json failed")
+	}
+	records := make([]ImportRecord, 0, len(items))
+	for i, item := range items {
+		records = append(records, ImportRecord{
+			Row:         i + 1,
+			Name:        strings.TrimSpace(item.Name),
+			Username:    item.Username,
+			Url:         item.Url,
+			Description: item.Description,
+			Password:    Password(item.Password),
+		})
+	}
+	return records, nil
+}
+
+type keePassFile struct {
+	Meta struct {
+		RecycleBinUUID string `xml:"RecycleBinUUID"`
+	} `xml:"Meta"`
+	Root struct {
+		Groups []keePassGroup `xml:"Group"`
+	} `xml:"Root"`
+}
+
+type keePassGroup struct {
+	UUID    string         `xml:"UUID"`
+	Name    string         `xml:"Name"`
+	Entries []keePassEntry `xml:"Entry"`
+	Groups  []keePassGroup `xml:"Group"`
+}
+
+type keePassEntry struct {
+	Strings []struct {
+		Key   string `xml:"Key"`
+		Value string `xml:"Value"`
+	} `xml:"String"`
+}
+
+func (e keePassEntry) value(key string) string {
+	for _, s := range e.Strings {
+		if s.Key == key {
+			return s.Value
+		}
+	}
+	return ""
+}
+
+// ParseKeePassXML reads the entries of a KeePass 2.x XML export (Title,
+// UserName, URL, Notes, Password). Entries in the recycle bin are skipped;
+// entry history is ignored.
+func ParseKeePassXML(ctx context.Context, reader io.Reader) ([]ImportRecord, error) {
+	var file keePassFile
+	if err := xml.NewDecoder(reader).Decode(&file); err != nil {
+		return nil, errors.Wrapf(ctx, err, "parse keepass xml failed")
+	}
+	var records []ImportRecord
+	var walk func(groups []keePassGroup)
+	walk = func(groups []keePassGroup) {
+		for _, group := range groups {
+			if file.Meta.RecycleBinUUID != "" && group.UUID == file.Meta.RecycleBinUUID {
+				continue
+			}
+			for _, entry := range group.Entries {
+				records = append(records, ImportRecord{
+					Row:         len(records) + 1,
+					Name:        strings.TrimSpace(entry.value("Title")),
+					Username:    entry.value("UserName"),
+					Url:         entry.value("URL"),
+					Description: entry.value("Notes"),
+					Password:    Password(entry.value("Password")),
+				})
+			}
+			walk(group.Groups)
+		}
+	}
+	walk(file.Root.Groups)
+	return records, nil
+}
@@ -0,0 +1,140 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package teamvault_test
+
+import (
+	"context"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	teamvault "github.com/Seibert-Data/teamvault-cli/v5/pkg"
+)
+
+var _ = Describe("ImportParser", func() {
+	var ctx context.Context
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	Context("ParseImportColumns", func() {
+		It("parses a mapping", func() {
+			columns, err := teamvault.ParseImportColumns(ctx, "name=Title, Password=Secret")
+			Expect(err).To(BeNil())
+			Expect(
+				columns,
+			).To(Equal(teamvault.ImportColumns{"name": "Title", "password": "Secret"}))
+		})
+
+		It("rejects unknown fields", func() {
+			_, err := teamvault.ParseImportColumns(ctx, "title=Title")
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(ContainSubstring("invalid column mapping"))
+		})
+	})
+
+	Context("ParseImportCSV", func() {
+		It("reads columns by field name, ignoring case and a byte order mark", func() {
+			records, err := teamvault.ParseImportCSV(
+				ctx,
+				strings.NewReader("\uFEFFName,USERNAME,Password\npayment-db,payment, s3cret \n"),
+				nil,
+			)
+			Expect(err).To(BeNil())
+			Expect(records).To(Equal([]teamvault.ImportRecord{{
+				Row:      1,
+				Name:     "payment-db",
+				Username: "payment",
+				Password: " s3cret ",
+			}}))
+		})
+
+		It("reads mapped columns and tolerates short rows", func() {
+			records, err := teamvault.ParseImportCSV(
+				ctx,
+				strings.NewReader("Title,Secret,Notes\na,pw-a,note\nb\n"),
+				teamvault.ImportColumns{
+					"name":        "Title",
+					"password":    "Secret",
+					"description": "Notes",
+				},
+			)
+			Expect(err).To(BeNil())
+			Expect(records).To(HaveLen(2))
+			Expect(records[0].Description).To(Equal("note"))
+			Expect(records[1]).To(Equal(teamvault.ImportRecord{Row: 2, Name: "b"}))
+		})
+
+		It("fails without a name column", func() {
+			_, err := teamvault.ParseImportCSV(ctx, strings.NewReader("Title,Password\n"), nil)
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(ContainSubstring("no name column"))
+		})
+
+		It("fails for a mapped column that does not exist", func() {
+			_, err := teamvault.ParseImportCSV(
+				ctx,
+				strings.NewReader("name,password\n"),
+				teamvault.ImportColumns{"url": "Website"},
+			)
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(ContainSubstring(`no column "Website"`))
+		})
+	})
+
+	It("ParseImportJSON reads an array of objects", func() {
+		records, err := teamvault.ParseImportJSON(ctx, strings.NewReader(
+			`[{"name":"payment-db","url":"postgres://db","password":"pw"}]`,
+		))
+		Expect(err).To(BeNil())
+		Expect(records).To(Equal([]teamvault.ImportRecord{{
+			Row:      1,
+			Name:     "payment-db",
+			Url:      "postgres://db",
+			Password: "pw",
+		}}))
+	})
+
+	It("ParseKeePassXML reads nested groups and skips the recycle bin", func() {
+		records, err := teamvault.ParseKeePassXML(ctx, strings.NewReader(`<KeePassFile>
+  <Meta><RecycleBinUUID>bin</RecycleBinUUID></Meta>
+  <Root>
+    <Group>
+      <UUID>root</UUID>
+      <Entry>
+        <String><Key>Title</Key><Value>payment-db</Value></String>
+        <String><Key>UserName</Key><Value>payment</Value></String>
+        <String><Key>Password</Key><Value ProtectInMemory="True">pw</Value></String>
+        <String><Key>Notes</Key><Value>primary</Value></String>
+      </Entry>
+      <Group>
+        <UUID>sub</UUID>
+        <Entry>
+          <String><Key>Title</Key><Value>mail</Value></String>
+          <String><Key>URL</Key><Value>https://mail</Value></String>
+        </Entry>
+      </Group>
+      <Group>
+        <UUID>bin</UUID>
+        <Entry><String><Key>Title</Key><Value>deleted</Value></String></Entry>
+      </Group>
+    </Group>
+  </Root>
+</KeePassFile>`))
+		Expect(err).To(BeNil())
+		Expect(records).To(Equal([]teamvault.ImportRecord{
+			{
+				Row:         1,
+				Name:        "payment-db",
+				Username:    "payment",
+				Description: "primary",
+				Password:    "pw",
+			},
+			{Row: 2, Name: "mail", Url: "https://mail"},
+		}))
+	})
+})
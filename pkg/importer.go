@@ -0,0 +1,218 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package teamvault
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"os"
+
+	"github.com/bborbe/errors"
+)
+
+// ImportStatus is the outcome of importing one record.
+type ImportStatus string
+
+const (
+	// ImportCreated means the secret was created.
+	ImportCreated ImportStatus = "created"
+	// ImportWouldCreate means a dry run would have created the secret.
+	ImportWouldCreate ImportStatus = "would create"
+	// ImportDone means an earlier run already created the secret.
+	ImportDone ImportStatus = "done"
+	// ImportDuplicate means a secret of that name already exists in
+	// TeamVault or earlier in the input.
+	ImportDuplicate ImportStatus = "duplicate"
+	// ImportInvalid means the record lacks a name or password.
+	ImportInvalid ImportStatus = "invalid"
+	// ImportFailed means creating the secret failed.
+	ImportFailed ImportStatus = "failed"
+	// ImportPending means the record was not reached because an earlier
+	// one failed.
+	ImportPending ImportStatus = "pending"
+)
+
+// ImportResult reports the outcome of one record.
+type ImportResult struct {
+	Record ImportRecord
+	Status ImportStatus
+	// Key is the created, previously created or duplicate secret.
+	Key Key
+	// Reason explains invalid, duplicate and failed records.
+	Reason string
+}
+
+// ImportState maps the names of records created by earlier runs to their
+// keys, so an import can resume after a failure.
+type ImportState map[string]Key
+
+// ImportStatePath is the JSON file an import records its progress in.
+type ImportStatePath string
+
+// String returns the path.
+func (p ImportStatePath) String() string {
+	return string(p)
+}
+
+// Read reads the state. A missing file is an empty state.
+func (p ImportStatePath) Read(ctx context.Context) (ImportState, error) {
+	content, err := os.ReadFile(p.String())
+	if os.IsNotExist(err) {
+		return ImportState{}, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "read import state %s failed", p)
+	}
+	state := ImportState{}
+	if err := json.Unmarshal(content, &state); err != nil {
+		return nil, errors.Wrapf(ctx, err, "parse import state %s failed", p)
+	}
+	return state, nil
+}
+
+// Write writes the state.
+func (p ImportStatePath) Write(ctx context.Context, state ImportState) error {
+	content, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return errors.Wrapf(ctx, err, "marshal import state failed")
+	}
+	if err := os.WriteFile(p.String(), append(content, '\n'), 0600); err != nil {
+		return errors.Wrapf(ctx, err, "write import state %s failed", p)
+	}
+	return nil
+}
+
+// ImportOptions control an import.
+type ImportOptions struct {
+	// DryRun checks every record without creating anything.
+	DryRun bool
+	// ContinueOnError keeps importing after a failed create instead of
+	// stopping; remaining records are ImportPending when stopping.
+	ContinueOnError bool
+	// State holds the records created by earlier runs; they are skipped.
+	State ImportState
+	// OnCreated is called after each create, e.g. to persist the state.
+	OnCreated func(ctx context.Context, name string, key Key) error
+}
+
+// Importer creates secrets from import records.
+type Importer interface {
+	// Import creates a password secret per record, skipping records
+	// without name or password and names that already exist. It returns
+	// one result per record and an error if any create failed.
+	Import(
+		ctx context.Context,
+		records []ImportRecord,
+		options ImportOptions,
+	) ([]ImportResult, error)
+}
+
+// NewImporter creates an Importer that detects duplicates via connector's
+// Search and creates secrets through writer. writer may be nil for dry runs.
+func NewImporter(connector Connector, writer Writer) Importer {
+	return &importer{
+		connector: connector,
+		writer:    writer,
+	}
+}
+
+type importer struct {
+	connector Connector
+	writer    Writer
+}
+
+func (i *importer) Import(
+	ctx context.Context,
+	records []ImportRecord,
+	options ImportOptions,
+) ([]ImportResult, error) {
+	results := make([]ImportResult, 0, len(records))
+	seen := make(map[string]int, len(records))
+	failed := 0
+	for n, record := range records {
+		if failed > 0 && !options.ContinueOnError {
+			for _, rest := range records[n:] {
+				results = append(results, ImportResult{Record: rest, Status: ImportPending})
+			}
+			break
+		}
+		result := i.importRecord(ctx, record, options, seen)
+		if result.Status == ImportFailed {
+			failed++
+		}
+		if result.Status != ImportInvalid {
+			seen[record.Name] = record.Row
+		}
+		results = append(results, result)
+	}
+	if failed > 0 {
+		return results, errors.Errorf(ctx, "%d of %d records failed", failed, len(records))
+	}
+	return results, nil
+}
+
+func (i *importer) importRecord(
+	ctx context.Context,
+	record ImportRecord,
+	options ImportOptions,
+	seen map[string]int,
+) ImportResult {
+	result := ImportResult{Record: record}
+	switch {
+	case record.Name == "":
+		result.Status, result.Reason = ImportInvalid, "name empty"
+		return result
+	case record.Password == "":
+		result.Status, result.Reason = ImportInvalid, "password empty"
+		return result
+	}
+	if key, ok := options.State[record.Name]; ok {
+		result.Status, result.Key = ImportDone, key
+		return result
+	}
+	if row, ok := seen[record.Name]; ok {
+		result.Status, result.Reason = ImportDuplicate, fmt.Sprintf("same name as row %d", row)
+		return result
+	}
+
+	key, err := NewNameResolver(i.connector, false).Resolve(ctx, record.Name)
+	switch {
+	case err == nil:
+		result.Status, result.Key, result.Reason = ImportDuplicate, key, "exists in TeamVault"
+		return result
+	case stderrors.Is(err, ErrNameAmbiguous):
+		result.Status, result.Reason = ImportDuplicate, "exists in TeamVault more than once"
+		return result
+	case !stderrors.Is(err, ErrNameNotFound):
+		result.Status, result.Reason = ImportFailed, err.Error()
+		return result
+	}
+
+	if options.DryRun {
+		result.Status = ImportWouldCreate
+		return result
+	}
+	key, _, err = i.writer.Create(ctx, CreateSecret{
+		ContentType: ContentTypePassword,
+		Name:        record.Name,
+		Username:    record.Username,
+		Url:         record.Url,
+		Description: record.Description,
+		Password:    record.Password,
+	})
+	if err != nil {
+		result.Status, result.Reason = ImportFailed, err.Error()
+		return result
+	}
+	result.Status, result.Key = ImportCreated, key
+	if options.OnCreated != nil {
+		if err := options.OnCreated(ctx, record.Name, key); err != nil {
+			result.Status, result.Reason = ImportFailed, err.Error()
+		}
+	}
+	return result
+}
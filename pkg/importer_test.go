@@ -0,0 +1,150 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package teamvault_test
+
+import (
+	"context"
+	stderrors "errors"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	teamvault "github.com/Seibert-Data/teamvault-cli/v5/pkg"
+	"github.com/Seibert-Data/teamvault-cli/v5/pkg/mocks"
+)
+
+var _ = Describe("Importer", func() {
+	var ctx context.Context
+	var conn *mocks.Connector
+	var writer *mocks.Writer
+	var records []teamvault.ImportRecord
+
+	statuses := func(results []teamvault.ImportResult) []teamvault.ImportStatus {
+		var result []teamvault.ImportStatus
+		for _, r := range results {
+			result = append(result, r.Status)
+		}
+		return result
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		conn = &mocks.Connector{}
+		conn.SearchStub = func(ctx context.Context, name string) ([]teamvault.SearchResult, error) {
+			if name == "existing" {
+				return []teamvault.SearchResult{{Key: "ExI123", Name: "existing"}}, nil
+			}
+			return []teamvault.SearchResult{{Key: "OtH456", Name: name + "-other"}}, nil
+		}
+		writer = &mocks.Writer{}
+		writer.CreateReturns("NeW789", "", nil)
+		records = []teamvault.ImportRecord{
+			{Row: 1, Name: "payment-db", Username: "payment", Password: "pw1"},
+			{Row: 2, Name: "existing", Password: "pw2"},
+			{Row: 3, Name: "payment-db", Password: "pw3"},
+			{Row: 4, Name: "no-password"},
+		}
+	})
+
+	It("creates new records and reports duplicates and invalid ones", func() {
+		results, err := teamvault.NewImporter(conn, writer).
+			Import(ctx, records, teamvault.ImportOptions{})
+
+		Expect(err).To(BeNil())
+		Expect(statuses(results)).To(Equal([]teamvault.ImportStatus{
+			teamvault.ImportCreated,
+			teamvault.ImportDuplicate,
+			teamvault.ImportDuplicate,
+			teamvault.ImportInvalid,
+		}))
+		Expect(results[0].Key).To(Equal(teamvault.Key("NeW789")))
+		Expect(results[1].Key).To(Equal(teamvault.Key("ExI123")))
+		Expect(results[2].Reason).To(Equal("same name as row 1"))
+		Expect(writer.CreateCallCount()).To(Equal(1))
+		_, secret := writer.CreateArgsForCall(0)
+		Expect(secret).To(Equal(teamvault.CreateSecret{
+			ContentType: teamvault.ContentTypePassword,
+			Name:        "payment-db",
+			Username:    "payment",
+			Password:    "pw1",
+		}))
+	})
+
+	It("does not write on a dry run", func() {
+		results, err := teamvault.NewImporter(conn, nil).
+			Import(ctx, records[:1], teamvault.ImportOptions{DryRun: true})
+
+		Expect(err).To(BeNil())
+		Expect(statuses(results)).To(Equal([]teamvault.ImportStatus{teamvault.ImportWouldCreate}))
+	})
+
+	It("skips records created by an earlier run", func() {
+		results, err := teamvault.NewImporter(conn, writer).
+			Import(ctx, records[:1], teamvault.ImportOptions{
+				State: teamvault.ImportState{"payment-db": "OlD111"},
+			})
+
+		Expect(err).To(BeNil())
+		Expect(statuses(results)).To(Equal([]teamvault.ImportStatus{teamvault.ImportDone}))
+		Expect(results[0].Key).To(Equal(teamvault.Key("OlD111")))
+		Expect(writer.Invocations()).To(BeEmpty())
+	})
+
+	Context("on failure", func() {
+		BeforeEach(func() {
+			writer.CreateReturnsOnCall(0, "", "", stderrors.New("boom"))
+			records = []teamvault.ImportRecord{
+				{Row: 1, Name: "a", Password: "pw"},
+				{Row: 2, Name: "b", Password: "pw"},
+			}
+		})
+
+		It("stops and leaves the rest pending", func() {
+			results, err := teamvault.NewImporter(conn, writer).
+				Import(ctx, records, teamvault.ImportOptions{})
+
+			Expect(err).NotTo(BeNil())
+			Expect(statuses(results)).To(Equal([]teamvault.ImportStatus{
+				teamvault.ImportFailed,
+				teamvault.ImportPending,
+			}))
+			Expect(results[0].Reason).To(ContainSubstring("boom"))
+		})
+
+		It("continues when asked to", func() {
+			var created []string
+			results, err := teamvault.NewImporter(conn, writer).
+				Import(ctx, records, teamvault.ImportOptions{
+					ContinueOnError: true,
+					OnCreated: func(ctx context.Context, name string, key teamvault.Key) error {
+						created = append(created, name)
+						return nil
+					},
+				})
+
+			Expect(err).NotTo(BeNil())
+			Expect(statuses(results)).To(Equal([]teamvault.ImportStatus{
+				teamvault.ImportFailed,
+				teamvault.ImportCreated,
+			}))
+			Expect(created).To(Equal([]string{"b"}))
+		})
+	})
+
+	Context("ImportStatePath", func() {
+		It("round-trips and reads a missing file as empty", func() {
+			path := teamvault.ImportStatePath(filepath.Join(GinkgoT().TempDir(), "state.json"))
+			state, err := path.Read(ctx)
+			Expect(err).To(BeNil())
+			Expect(state).To(BeEmpty())
+
+			Expect(path.Write(ctx, teamvault.ImportState{"a": "AbC123"})).To(Succeed())
+			state, err = path.Read(ctx)
+			Expect(err).To(BeNil())
+			Expect(state).To(Equal(teamvault.ImportState{"a": "AbC123"}))
+		})
+	})
+})
@@ -0,0 +1,293 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package teamvault
+
+import (
+	"bytes"
+	"context"
+	stderrors "errors"
+	"os"
+
+	"github.com/bborbe/errors"
+)
+
+// InventoryAction is what applying a plan does to one secret.
+type InventoryAction string
+
+const (
+	// InventoryCreate creates a missing secret.
+	InventoryCreate InventoryAction = "create"
+	// InventoryUpdate changes the fields listed in InventoryChange.Fields.
+	InventoryUpdate InventoryAction = "update"
+	// InventoryNoop leaves a secret that already matches untouched.
+	InventoryNoop InventoryAction = "noop"
+)
+
+// InventoryChange is the planned step for one secret of the inventory.
+type InventoryChange struct {
+	Secret InventorySecret
+	Action InventoryAction
+	// Key is the existing secret's key; empty for InventoryCreate.
+	Key Key
+	// Fields lists the fields a create sets or an update changes.
+	Fields []string
+	// Revision is the secret's revision at plan time. Apply fails with
+	// ErrRevisionConflict if the secret changed since.
+	Revision CurrentRevision
+	// fileContent is the source file content read while planning.
+	fileContent []byte
+}
+
+// InventoryPlan lists one change per inventory secret, in manifest order.
+type InventoryPlan struct {
+	Changes []InventoryChange
+}
+
+// Count returns how many changes have the given action.
+func (p InventoryPlan) Count(action InventoryAction) int {
+	count := 0
+	for _, change := range p.Changes {
+		if change.Action == action {
+			count++
+		}
+	}
+	return count
+}
+
+// HasChanges reports whether applying the plan would write anything.
+func (p InventoryPlan) HasChanges() bool {
+	return p.Count(InventoryCreate)+p.Count(InventoryUpdate) > 0
+}
+
+// InventoryPlanner diffs an inventory against TeamVault.
+type InventoryPlanner interface {
+	// Plan finds each secret by its key in lock, else by exact name via
+	// Search, and compares the declared fields. File secrets are compared
+	// by content, which reads them.
+	Plan(ctx context.Context, inventory Inventory, lock InventoryLock) (InventoryPlan, error)
+}
+
+// NewInventoryPlanner creates an InventoryPlanner reading through connector,
+// which must implement MetadataReader.
+func NewInventoryPlanner(connector Connector) InventoryPlanner {
+	return &inventoryPlanner{
+		connector: connector,
+	}
+}
+
+type inventoryPlanner struct {
+	connector Connector
+}
+
+func (p *inventoryPlanner) Plan(
+	ctx context.Context,
+	inventory Inventory,
+	lock InventoryLock,
+) (InventoryPlan, error) {
+	var plan InventoryPlan
+	for _, secret := range inventory.Secrets {
+		change, err := p.planSecret(ctx, secret, lock[secret.Name])
+		if err != nil {
+			return InventoryPlan{}, errors.Wrapf(ctx, err, "plan secret %q failed", secret.Name)
+		}
+		plan.Changes = append(plan.Changes, change)
+	}
+	return plan, nil
+}
+
+func (p *inventoryPlanner) planSecret(
+	ctx context.Context,
+	secret InventorySecret,
+	key Key,
+) (InventoryChange, error) {
+	change := InventoryChange{Secret: secret, Key: key}
+	if secret.ContentType == ContentTypeFile {
+		content, err := os.ReadFile(secret.SourceFile)
+		if err != nil {
+			return change, errors.Wrapf(ctx, err, "read source file failed")
+		}
+		change.fileContent = content
+	}
+
+	if key == "" {
+		resolved, err := NewNameResolver(p.connector, false).Resolve(ctx, secret.Name)
+		if stderrors.Is(err, ErrNameNotFound) {
+			return p.planCreate(ctx, change)
+		}
+		if err != nil {
+			return change, err
+		}
+		change.Key = resolved
+	}
+
+	metadata, err := ReadMetadata(ctx, p.connector, change.Key)
+	if err != nil {
+		return change, errors.Wrapf(ctx, err, "read secret %s failed", change.Key)
+	}
+	if metadata.ContentType != secret.ContentType {
+		return change, errors.Errorf(
+			ctx,
+			"secret %s is a %s secret, declared as %s; content types cannot change",
+			change.Key,
+			metadata.ContentType,
+			secret.ContentType,
+		)
+	}
+	change.Revision = metadata.CurrentRevision
+	if metadata.Name != secret.Name {
+		change.Fields = append(change.Fields, "name")
+	}
+	if secret.Username != nil && *secret.Username != metadata.Username {
+		change.Fields = append(change.Fields, "username")
+	}
+	if secret.Url != nil && *secret.Url != metadata.Url.String() {
+		change.Fields = append(change.Fields, "url")
+	}
+	if secret.Description != nil && *secret.Description != metadata.Description {
+		change.Fields = append(change.Fields, "description")
+	}
+	if secret.ContentType == ContentTypeFile {
+		file, err := p.connector.File(ctx, change.Key)
+		if err != nil {
+			return change, errors.Wrapf(ctx, err, "read file of %s failed", change.Key)
+		}
+		current, err := file.Content()
+		if err != nil {
+			return change, errors.Wrapf(ctx, err, "decode file of %s failed", change.Key)
+		}
+		if !bytes.Equal(current, change.fileContent) {
+			change.Fields = append(change.Fields, "file")
+		}
+	}
+	change.Action = InventoryNoop
+	if len(change.Fields) > 0 {
+		change.Action = InventoryUpdate
+	}
+	return change, nil
+}
+
+func (p *inventoryPlanner) planCreate(
+	ctx context.Context,
+	change InventoryChange,
+) (InventoryChange, error) {
+	secret := change.Secret
+	if secret.ContentType == ContentTypePassword && !secret.Generate {
+		return change, errors.New(
+			ctx,
+			"secret does not exist and has no value; set generate: true to create it",
+		)
+	}
+	change.Action = InventoryCreate
+	change.Fields = []string{"name"}
+	if secret.Username != nil {
+		change.Fields = append(change.Fields, "username")
+	}
+	if secret.Url != nil {
+		change.Fields = append(change.Fields, "url")
+	}
+	if secret.Description != nil {
+		change.Fields = append(change.Fields, "description")
+	}
+	if secret.ContentType == ContentTypeFile {
+		change.Fields = append(change.Fields, "file")
+	} else {
+		change.Fields = append(change.Fields, "password")
+	}
+	return change, nil
+}
+
+// InventoryApplier converges TeamVault to a plan.
+type InventoryApplier interface {
+	// Apply runs the plan's creates and updates and returns the lock for
+	// the inventory: the key of every planned secret. On error the lock
+	// holds the keys of the existing secrets and of those created so far,
+	// so it can still be written.
+	Apply(ctx context.Context, plan InventoryPlan) (InventoryLock, error)
+}
+
+// NewInventoryApplier creates an InventoryApplier writing through writer.
+func NewInventoryApplier(writer Writer) InventoryApplier {
+	return &inventoryApplier{
+		writer: writer,
+	}
+}
+
+type inventoryApplier struct {
+	writer Writer
+}
+
+func (a *inventoryApplier) Apply(ctx context.Context, plan InventoryPlan) (InventoryLock, error) {
+	lock := InventoryLock{}
+	for _, change := range plan.Changes {
+		if change.Key != "" {
+			lock[change.Secret.Name] = change.Key
+		}
+	}
+	for _, change := range plan.Changes {
+		key, err := a.applyChange(ctx, change)
+		if err != nil {
+			return lock, errors.Wrapf(
+				ctx,
+				err,
+				"%s secret %q failed",
+				change.Action,
+				change.Secret.Name,
+			)
+		}
+		lock[change.Secret.Name] = key
+	}
+	return lock, nil
+}
+
+func (a *inventoryApplier) applyChange(ctx context.Context, change InventoryChange) (Key, error) {
+	secret := change.Secret
+	switch change.Action {
+	case InventoryCreate:
+		create := CreateSecret{
+			ContentType: secret.ContentType,
+			Name:        secret.Name,
+			Username:    valueOf(secret.Username),
+			Url:         valueOf(secret.Url),
+			Description: valueOf(secret.Description),
+			FileContent: change.fileContent,
+		}
+		if secret.ContentType == ContentTypePassword {
+			password, err := a.writer.GeneratePassword(ctx)
+			if err != nil {
+				return "", errors.Wrapf(ctx, err, "generate password failed")
+			}
+			create.Password = password
+		}
+		key, _, err := a.writer.Create(ctx, create)
+		return key, err
+	case InventoryUpdate:
+		update := UpdateSecret{ExpectedRevision: change.Revision}
+		for _, field := range change.Fields {
+			switch field {
+			case "name":
+				update.Name = &secret.Name
+			case "username":
+				update.Username = secret.Username
+			case "url":
+				update.Url = secret.Url
+			case "description":
+				update.Description = secret.Description
+			case "file":
+				update.FileContent = change.fileContent
+			}
+		}
+		key, _, err := a.writer.Update(ctx, change.Key, update)
+		return key, err
+	default:
+		return change.Key, nil
+	}
+}
+
+func valueOf(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
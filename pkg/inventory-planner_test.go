@@ -0,0 +1,311 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package teamvault_test
+
+import (
+	"context"
+	"encoding/base64"
+	stderrors "errors"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	teamvault "github.com/Seibert-Data/teamvault-cli/v5/pkg"
+	"github.com/Seibert-Data/teamvault-cli/v5/pkg/mocks"
+)
+
+// metadataConnector is a fake connector that also reads metadata.
+type metadataConnector struct {
+	*mocks.Connector
+	*mocks.MetadataReader
+}
+
+func stringPtr(value string) *string {
+	return &value
+}
+
+var _ = Describe("InventoryPlanner", func() {
+	var ctx context.Context
+	var conn metadataConnector
+	var metadata map[teamvault.Key]teamvault.SecretMetadata
+	var planner teamvault.InventoryPlanner
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		conn = metadataConnector{
+			Connector:      &mocks.Connector{},
+			MetadataReader: &mocks.MetadataReader{},
+		}
+		metadata = map[teamvault.Key]teamvault.SecretMetadata{
+			"AbC123": {
+				Key:             "AbC123",
+				Name:            "payment-db",
+				Username:        "payment",
+				Description:     "db",
+				ContentType:     teamvault.ContentTypePassword,
+				CurrentRevision: "rev1",
+			},
+		}
+		conn.MetadataReader.MetadataStub = func(
+			ctx context.Context,
+			key teamvault.Key,
+		) (teamvault.SecretMetadata, error) {
+			return metadata[key], nil
+		}
+		conn.SearchStub = func(ctx context.Context, name string) ([]teamvault.SearchResult, error) {
+			var results []teamvault.SearchResult
+			for key, m := range metadata {
+				if m.Name == name {
+					results = append(results, teamvault.SearchResult{Key: key, Name: m.Name})
+				}
+			}
+			return results, nil
+		}
+		planner = teamvault.NewInventoryPlanner(conn)
+	})
+
+	plan := func(
+		lock teamvault.InventoryLock,
+		secrets ...teamvault.InventorySecret,
+	) teamvault.InventoryPlan {
+		result, err := planner.Plan(ctx, teamvault.Inventory{Secrets: secrets}, lock)
+		Expect(err).To(BeNil())
+		Expect(result.Changes).To(HaveLen(len(secrets)))
+		return result
+	}
+
+	It("plans nothing for a secret found by name that matches", func() {
+		result := plan(nil, teamvault.InventorySecret{
+			Name:        "payment-db",
+			ContentType: teamvault.ContentTypePassword,
+			Username:    stringPtr("payment"),
+		})
+
+		Expect(result.Changes[0].Action).To(Equal(teamvault.InventoryNoop))
+		Expect(result.Changes[0].Key).To(Equal(teamvault.Key("AbC123")))
+		Expect(result.HasChanges()).To(BeFalse())
+	})
+
+	It("plans an update of the fields that differ", func() {
+		result := plan(nil, teamvault.InventorySecret{
+			Name:        "payment-db",
+			ContentType: teamvault.ContentTypePassword,
+			Username:    stringPtr("payment"),
+			Url:         stringPtr("postgres://db"),
+			Description: stringPtr("db"),
+		})
+
+		Expect(result.Changes[0].Action).To(Equal(teamvault.InventoryUpdate))
+		Expect(result.Changes[0].Fields).To(Equal([]string{"url"}))
+		Expect(result.Changes[0].Revision).To(Equal(teamvault.CurrentRevision("rev1")))
+	})
+
+	It("identifies secrets by the lock file, renaming them back", func() {
+		result := plan(
+			teamvault.InventoryLock{"payments-db": "AbC123"},
+			teamvault.InventorySecret{
+				Name:        "payments-db",
+				ContentType: teamvault.ContentTypePassword,
+			},
+		)
+
+		Expect(conn.SearchCallCount()).To(Equal(0))
+		Expect(result.Changes[0].Key).To(Equal(teamvault.Key("AbC123")))
+		Expect(result.Changes[0].Fields).To(Equal([]string{"name"}))
+	})
+
+	It("plans to create a missing secret with a generated password", func() {
+		result := plan(nil, teamvault.InventorySecret{
+			Name:        "new-secret",
+			ContentType: teamvault.ContentTypePassword,
+			Username:    stringPtr("svc"),
+			Generate:    true,
+		})
+
+		Expect(result.Changes[0].Action).To(Equal(teamvault.InventoryCreate))
+		Expect(result.Changes[0].Fields).To(Equal([]string{"name", "username", "password"}))
+	})
+
+	It("fails for a missing password secret without generate", func() {
+		_, err := planner.Plan(ctx, teamvault.Inventory{Secrets: []teamvault.InventorySecret{
+			{Name: "new-secret", ContentType: teamvault.ContentTypePassword},
+		}}, nil)
+
+		Expect(err).NotTo(BeNil())
+		Expect(err.Error()).To(ContainSubstring("generate: true"))
+	})
+
+	It("fails when the content type differs", func() {
+		source := filepath.Join(GinkgoT().TempDir(), "tls.pem")
+		Expect(os.WriteFile(source, []byte("cert"), 0600)).To(Succeed())
+		_, err := planner.Plan(ctx, teamvault.Inventory{Secrets: []teamvault.InventorySecret{
+			{Name: "payment-db", ContentType: teamvault.ContentTypeFile, SourceFile: source},
+		}}, nil)
+
+		Expect(err).NotTo(BeNil())
+		Expect(err.Error()).To(ContainSubstring("content types cannot change"))
+	})
+
+	Context("file secrets", func() {
+		var source string
+
+		BeforeEach(func() {
+			source = filepath.Join(GinkgoT().TempDir(), "tls.pem")
+			Expect(os.WriteFile(source, []byte("cert-v2"), 0600)).To(Succeed())
+			metadata["XyZ789"] = teamvault.SecretMetadata{
+				Key:         "XyZ789",
+				Name:        "payment-tls",
+				ContentType: teamvault.ContentTypeFile,
+			}
+		})
+
+		It("plans an update when the content differs", func() {
+			conn.FileReturns(
+				teamvault.File(base64.StdEncoding.EncodeToString([]byte("cert-v1"))),
+				nil,
+			)
+
+			result := plan(nil, teamvault.InventorySecret{
+				Name:        "payment-tls",
+				ContentType: teamvault.ContentTypeFile,
+				SourceFile:  source,
+			})
+
+			Expect(result.Changes[0].Action).To(Equal(teamvault.InventoryUpdate))
+			Expect(result.Changes[0].Fields).To(Equal([]string{"file"}))
+		})
+
+		It("plans nothing when the content matches", func() {
+			conn.FileReturns(
+				teamvault.File(base64.StdEncoding.EncodeToString([]byte("cert-v2"))),
+				nil,
+			)
+
+			result := plan(nil, teamvault.InventorySecret{
+				Name:        "payment-tls",
+				ContentType: teamvault.ContentTypeFile,
+				SourceFile:  source,
+			})
+
+			Expect(result.Changes[0].Action).To(Equal(teamvault.InventoryNoop))
+		})
+	})
+
+	Context("InventoryApplier", func() {
+		var writer *mocks.Writer
+
+		BeforeEach(func() {
+			writer = &mocks.Writer{}
+			writer.GeneratePasswordReturns("generated", nil)
+			writer.CreateReturns("NeW456", "", nil)
+			writer.UpdateStub = func(
+				ctx context.Context,
+				key teamvault.Key,
+				secret teamvault.UpdateSecret,
+			) (teamvault.Key, teamvault.ApiUrl, error) {
+				return key, "", nil
+			}
+		})
+
+		It("creates, updates and locks every secret", func() {
+			result := plan(
+				nil,
+				teamvault.InventorySecret{
+					Name:        "payment-db",
+					ContentType: teamvault.ContentTypePassword,
+					Description: stringPtr("primary db"),
+				},
+				teamvault.InventorySecret{
+					Name:        "new-secret",
+					ContentType: teamvault.ContentTypePassword,
+					Url:         stringPtr("https://svc"),
+					Generate:    true,
+				},
+			)
+
+			lock, err := teamvault.NewInventoryApplier(writer).Apply(ctx, result)
+
+			Expect(err).To(BeNil())
+			Expect(lock).To(Equal(teamvault.InventoryLock{
+				"payment-db": "AbC123",
+				"new-secret": "NeW456",
+			}))
+			Expect(writer.UpdateCallCount()).To(Equal(1))
+			_, key, update := writer.UpdateArgsForCall(0)
+			Expect(key).To(Equal(teamvault.Key("AbC123")))
+			Expect(*update.Description).To(Equal("primary db"))
+			Expect(update.Name).To(BeNil())
+			Expect(update.Password).To(BeNil())
+			Expect(update.ExpectedRevision).To(Equal(teamvault.CurrentRevision("rev1")))
+			Expect(writer.CreateCallCount()).To(Equal(1))
+			_, create := writer.CreateArgsForCall(0)
+			Expect(create).To(Equal(teamvault.CreateSecret{
+				ContentType: teamvault.ContentTypePassword,
+				Name:        "new-secret",
+				Url:         "https://svc",
+				Password:    "generated",
+			}))
+		})
+
+		It("does not write for an unchanged plan", func() {
+			result := plan(nil, teamvault.InventorySecret{
+				Name:        "payment-db",
+				ContentType: teamvault.ContentTypePassword,
+			})
+
+			lock, err := teamvault.NewInventoryApplier(writer).Apply(ctx, result)
+
+			Expect(err).To(BeNil())
+			Expect(lock).To(Equal(teamvault.InventoryLock{"payment-db": "AbC123"}))
+			Expect(writer.Invocations()).To(BeEmpty())
+		})
+
+		It("returns the keys handled before a failure", func() {
+			writer.CreateReturns("", "", stderrors.New("boom"))
+			result := plan(
+				nil,
+				teamvault.InventorySecret{
+					Name:        "payment-db",
+					ContentType: teamvault.ContentTypePassword,
+				},
+				teamvault.InventorySecret{
+					Name:        "new-secret",
+					ContentType: teamvault.ContentTypePassword,
+					Generate:    true,
+				},
+			)
+
+			lock, err := teamvault.NewInventoryApplier(writer).Apply(ctx, result)
+
+			Expect(err).NotTo(BeNil())
+			Expect(lock).To(Equal(teamvault.InventoryLock{"payment-db": "AbC123"}))
+		})
+
+		It("keeps the keys of later secrets when failing partway", func() {
+			writer.CreateReturns("", "", stderrors.New("boom"))
+			result := plan(
+				teamvault.InventoryLock{"payment-db": "AbC123"},
+				teamvault.InventorySecret{
+					Name:        "new-secret",
+					ContentType: teamvault.ContentTypePassword,
+					Generate:    true,
+				},
+				teamvault.InventorySecret{
+					Name:        "payment-db",
+					ContentType: teamvault.ContentTypePassword,
+					Description: stringPtr("primary db"),
+				},
+			)
+
+			lock, err := teamvault.NewInventoryApplier(writer).Apply(ctx, result)
+
+			Expect(err).NotTo(BeNil())
+			Expect(writer.UpdateCallCount()).To(Equal(0))
+			Expect(lock).To(Equal(teamvault.InventoryLock{"payment-db": "AbC123"}))
+		})
+	})
+})
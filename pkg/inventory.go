@@ -0,0 +1,186 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package teamvault
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bborbe/errors"
+	"go.yaml.in/yaml/v3"
+)
+
+// DefaultInventoryPath is the manifest the inventory commands read by default.
+const DefaultInventoryPath = "teamvault-inventory.yaml"
+
+// Inventory is a declarative manifest of the secrets a service needs.
+type Inventory struct {
+	Secrets []InventorySecret `yaml:"secrets"`
+}
+
+// InventorySecret declares one secret. Name identifies the secret when the
+// lock file has no key for it yet. Username, Url and Description are only
+// managed when set; nil leaves the server's value alone.
+type InventorySecret struct {
+	Name string `yaml:"name"`
+	// ContentType is ContentTypePassword (the default) or ContentTypeFile.
+	ContentType ContentType `yaml:"content_type,omitempty"`
+	Username    *string     `yaml:"username,omitempty"`
+	Url         *string     `yaml:"url,omitempty"`
+	Description *string     `yaml:"description,omitempty"`
+	// Generate creates a missing password secret with a server-generated
+	// password. Existing passwords are never changed.
+	Generate bool `yaml:"generate,omitempty"`
+	// SourceFile is the content of a file secret, relative to the manifest.
+	SourceFile string `yaml:"source_file,omitempty"`
+}
+
+// Validate checks names are set and unique and each secret has a value
+// source matching its content type.
+func (i Inventory) Validate(ctx context.Context) error {
+	seen := make(map[string]bool, len(i.Secrets))
+	for n, secret := range i.Secrets {
+		if strings.TrimSpace(secret.Name) == "" {
+			return errors.Errorf(ctx, "secret #%d: name missing", n+1)
+		}
+		if seen[secret.Name] {
+			return errors.Errorf(ctx, "secret %q declared twice", secret.Name)
+		}
+		seen[secret.Name] = true
+		switch secret.ContentType {
+		case ContentTypePassword:
+			if secret.SourceFile != "" {
+				return errors.Errorf(
+					ctx,
+					"secret %q: source_file needs content_type file",
+					secret.Name,
+				)
+			}
+		case ContentTypeFile:
+			if secret.SourceFile == "" {
+				return errors.Errorf(ctx, "secret %q: file secrets need a source_file", secret.Name)
+			}
+			if secret.Generate {
+				return errors.Errorf(
+					ctx,
+					"secret %q: generate only applies to passwords",
+					secret.Name,
+				)
+			}
+		default:
+			return errors.Errorf(
+				ctx,
+				"secret %q: unsupported content_type %q (password or file)",
+				secret.Name,
+				secret.ContentType,
+			)
+		}
+	}
+	return nil
+}
+
+// InventoryPath is the location of an inventory manifest.
+type InventoryPath string
+
+// String returns the path.
+func (p InventoryPath) String() string {
+	return string(p)
+}
+
+// Read parses and validates the manifest. Unknown fields are rejected, the
+// content type defaults to password and source files are resolved relative
+// to the manifest's directory.
+func (p InventoryPath) Read(ctx context.Context) (Inventory, error) {
+	var inventory Inventory
+	content, err := os.ReadFile(p.String())
+	if err != nil {
+		return inventory, errors.Wrapf(ctx, err, "read inventory %s failed", p)
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&inventory); err != nil {
+		return inventory, errors.Wrapf(ctx, err, "parse inventory %s failed", p)
+	}
+	dir := filepath.Dir(p.String())
+	for i := range inventory.Secrets {
+		secret := &inventory.Secrets[i]
+		if secret.ContentType == "" {
+			secret.ContentType = ContentTypePassword
+		}
+		if secret.SourceFile != "" && !filepath.IsAbs(secret.SourceFile) {
+			secret.SourceFile = filepath.Join(dir, secret.SourceFile)
+		}
+	}
+	if err := inventory.Validate(ctx); err != nil {
+		return inventory, errors.Wrapf(ctx, err, "invalid inventory %s", p)
+	}
+	return inventory, nil
+}
+
+// LockPath returns the lock file next to the manifest:
+// teamvault-inventory.yaml locks to teamvault-inventory.lock.yaml.
+func (p InventoryPath) LockPath() InventoryLockPath {
+	path := p.String()
+	ext := filepath.Ext(path)
+	if ext == ".yaml" || ext == ".yml" {
+		path = strings.TrimSuffix(path, ext)
+	}
+	return InventoryLockPath(path + ".lock.yaml")
+}
+
+// InventoryLock maps the names of an inventory to the keys of their secrets.
+type InventoryLock map[string]Key
+
+// InventoryLockPath is the location of an inventory lock file.
+type InventoryLockPath string
+
+// String returns the path.
+func (p InventoryLockPath) String() string {
+	return string(p)
+}
+
+type inventoryLockFile struct {
+	Secrets InventoryLock `yaml:"secrets"`
+}
+
+// Read reads the lock file. A missing file is an empty lock.
+func (p InventoryLockPath) Read(ctx context.Context) (InventoryLock, error) {
+	content, err := os.ReadFile(p.String())
+	if os.IsNotExist(err) {
+		return InventoryLock{}, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "read lock file %s failed", p)
+	}
+	var file inventoryLockFile
+	if err := yaml.Unmarshal(content, &file); err != nil {
+		return nil, errors.Wrapf(ctx, err, "parse lock file %s failed", p)
+	}
+	if file.Secrets == nil {
+		file.Secrets = InventoryLock{}
+	}
+	return file.Secrets, nil
+}
+
+// Write writes the lock file, sorted by name so it diffs well in git. Keys
+// are not secret, so the file is meant to be committed.
+func (p InventoryLockPath) Write(ctx context.Context, lock InventoryLock) error {
+	content, err := yaml.Marshal(inventoryLockFile{Secrets: lock})
+	if err != nil {
+		return errors.Wrapf(ctx, err, "marshal lock file failed")
+	}
+	content = append(
+		[]byte("# Generated by teamvault-cli inventory apply. Maps names to keys.\n"),
+		content...,
+	)
+	// #nosec G306 -- the lock file holds keys only and is meant to be committed
+	if err := os.WriteFile(p.String(), content, 0644); err != nil {
+		return errors.Wrapf(ctx, err, "write lock file %s failed", p)
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package teamvault_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	teamvault "github.com/Seibert-Data/teamvault-cli/v5/pkg"
+)
+
+var _ = Describe("Inventory", func() {
+	var ctx context.Context
+	var dir string
+
+	read := func(content string) (teamvault.Inventory, error) {
+		path := filepath.Join(dir, "teamvault-inventory.yaml")
+		Expect(os.WriteFile(path, []byte(content), 0600)).To(Succeed())
+		return teamvault.InventoryPath(path).Read(ctx)
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		dir = GinkgoT().TempDir()
+	})
+
+	Context("InventoryPath", func() {
+		It("reads secrets with defaults and resolved source files", func() {
+			inventory, err := read(`
+secrets:
+  - name: payment-db
+    username: payment
+    generate: true
+  - name: payment-tls
+    content_type: file
+    source_file: certs/tls.pem
+`)
+			Expect(err).To(BeNil())
+			Expect(inventory.Secrets).To(HaveLen(2))
+			Expect(inventory.Secrets[0].ContentType).To(Equal(teamvault.ContentTypePassword))
+			Expect(*inventory.Secrets[0].Username).To(Equal("payment"))
+			Expect(inventory.Secrets[0].Url).To(BeNil())
+			Expect(inventory.Secrets[1].SourceFile).To(Equal(filepath.Join(dir, "certs/tls.pem")))
+		})
+
+		DescribeTable("rejects invalid manifests",
+			func(content string, message string) {
+				_, err := read(content)
+				Expect(err).NotTo(BeNil())
+				Expect(err.Error()).To(ContainSubstring(message))
+			},
+			Entry("unknown field", "secrets:\n  - name: a\n    pasword: x\n", "pasword"),
+			Entry("missing name", "secrets:\n  - username: a\n", "name missing"),
+			Entry("duplicate name", "secrets:\n  - name: a\n  - name: a\n", "declared twice"),
+			Entry("file without source", "secrets:\n  - name: a\n    content_type: file\n",
+				"need a source_file"),
+			Entry("source for a password", "secrets:\n  - name: a\n    source_file: x\n",
+				"needs content_type file"),
+			Entry("unsupported type", "secrets:\n  - name: a\n    content_type: cc\n",
+				"unsupported content_type"),
+		)
+
+		It("places the lock file next to the manifest", func() {
+			Expect(teamvault.InventoryPath("deploy/teamvault-inventory.yaml").LockPath()).
+				To(Equal(teamvault.InventoryLockPath("deploy/teamvault-inventory.lock.yaml")))
+			Expect(teamvault.InventoryPath("secrets").LockPath()).
+				To(Equal(teamvault.InventoryLockPath("secrets.lock.yaml")))
+		})
+	})
+
+	Context("InventoryLockPath", func() {
+		It("reads a missing file as an empty lock", func() {
+			lock, err := teamvault.InventoryLockPath(filepath.Join(dir, "missing.yaml")).Read(ctx)
+			Expect(err).To(BeNil())
+			Expect(lock).To(BeEmpty())
+		})
+
+		It("round-trips written keys", func() {
+			path := teamvault.InventoryLockPath(filepath.Join(dir, "inventory.lock.yaml"))
+			lock := teamvault.InventoryLock{"b": "XyZ789", "a": "AbC123"}
+			Expect(path.Write(ctx, lock)).To(Succeed())
+
+			read, err := path.Read(ctx)
+			Expect(err).To(BeNil())
+			Expect(read).To(Equal(lock))
+			content, err := os.ReadFile(path.String())
+			Expect(err).To(BeNil())
+			Expect(string(content)).To(ContainSubstring("secrets:\n    a: AbC123\n    b: XyZ789\n"))
+		})
+	})
+})
@@ -0,0 +1,106 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package teamvault
+
+import (
+	"context"
+	stderrors "errors"
+	"net/url"
+	"strings"
+
+	"github.com/bborbe/errors"
+)
+
+// ErrKeyHostMismatch is returned when a secret URL points at a different
+// TeamVault than the configured one.
+var ErrKeyHostMismatch = stderrors.New("secret url does not belong to the configured teamvault")
+
+//counterfeiter:generate -o mocks/key_resolver.go --fake-name KeyResolver . KeyResolver
+
+// KeyResolver turns what users paste (a bare hashid, a web UI URL like
+// https://vault.example.com/secrets/AbC123/ or an API URL) into a Key.
+type KeyResolver interface {
+	ResolveKey(ctx context.Context, value string) (Key, error)
+}
+
+// NewKeyResolver creates a KeyResolver accepting URLs of the vault at
+// vaultUrl only. An empty vaultUrl accepts URLs of any host.
+func NewKeyResolver(vaultUrl Url) KeyResolver {
+	return &keyResolver{
+		vaultUrl: vaultUrl,
+	}
+}
+
+type keyResolver struct {
+	vaultUrl Url
+}
+
+func (k *keyResolver) ResolveKey(ctx context.Context, value string) (Key, error) {
+	value = strings.TrimSpace(value)
+	if !strings.Contains(value, "://") && strings.Contains(value, "/secrets/") {
+		// pasted without scheme, e.g. vault.example.com/secrets/AbC123/
+		value = "https://" + value
+	}
+	if !strings.Contains(value, "://") {
+		key := Key(value)
+		if err := key.Validate(ctx); err != nil {
+			return "", err
+		}
+		if strings.ContainsAny(value, "/?#") {
+			return "", errors.Errorf(ctx, "invalid key %q", value)
+		}
+		return key, nil
+	}
+
+	u, err := url.Parse(value)
+	if err != nil {
+		return "", errors.Wrapf(ctx, err, "parse secret url failed")
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", errors.Errorf(ctx, "unsupported secret url scheme %q", u.Scheme)
+	}
+	if u.Host == "" {
+		return "", errors.Errorf(ctx, "secret url %q has no host", value)
+	}
+	if err := k.checkHost(ctx, u); err != nil {
+		return "", err
+	}
+	key, err := keyFromPath(u.Path)
+	if err != nil || !strings.Contains(u.Path, "/secrets/") {
+		return "", errors.Errorf(ctx, "no secret key in url %q", value)
+	}
+	return key, nil
+}
+
+func (k *keyResolver) checkHost(ctx context.Context, u *url.URL) error {
+	if k.vaultUrl == "" {
+		return nil
+	}
+	vault, err := url.Parse(k.vaultUrl.Normalize().String())
+	if err != nil {
+		return errors.Wrapf(ctx, err, "parse teamvault url failed")
+	}
+	if !strings.EqualFold(u.Hostname(), vault.Hostname()) ||
+		effectivePort(u) != effectivePort(vault) {
+		return errors.Wrapf(
+			ctx,
+			ErrKeyHostMismatch,
+			"url host %s, configured %s",
+			u.Host,
+			vault.Host,
+		)
+	}
+	return nil
+}
+
+func effectivePort(u *url.URL) string {
+	if port := u.Port(); port != "" {
+		return port
+	}
+	if u.Scheme == "http" {
+		return "80"
+	}
+	return "443"
+}
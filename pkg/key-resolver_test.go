@@ -0,0 +1,141 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package teamvault_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	teamvault "github.com/Seibert-Data/teamvault-cli/v5/pkg"
+)
+
+var _ = Describe("KeyResolver", func() {
+	var ctx context.Context
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	DescribeTable(
+		"ResolveKey",
+		func(vaultUrl teamvault.Url, value string, expectedKey teamvault.Key, expectedError bool) {
+			key, err := teamvault.NewKeyResolver(vaultUrl).ResolveKey(ctx, value)
+			if expectedError {
+				Expect(err).NotTo(BeNil())
+			} else {
+				Expect(err).To(BeNil())
+				Expect(key).To(Equal(expectedKey))
+			}
+		},
+		Entry(
+			"bare key",
+			teamvault.Url("https://vault.example.com"),
+			"AbC123",
+			teamvault.Key("AbC123"),
+			false,
+		),
+		Entry(
+			"bare key with whitespace",
+			teamvault.Url(""),
+			" AbC123\n",
+			teamvault.Key("AbC123"),
+			false,
+		),
+		Entry(
+			"web url",
+			teamvault.Url("https://vault.example.com"),
+			"https://vault.example.com/secrets/AbC123/",
+			teamvault.Key("AbC123"),
+			false,
+		),
+		Entry(
+			"web url without trailing slash",
+			teamvault.Url("https://vault.example.com"),
+			"https://vault.example.com/secrets/AbC123",
+			teamvault.Key("AbC123"),
+			false,
+		),
+		Entry(
+			"api url",
+			teamvault.Url("https://vault.example.com"),
+			"https://vault.example.com/api/secrets/AbC123/",
+			teamvault.Key("AbC123"),
+			false,
+		),
+		Entry(
+			"url with query and fragment",
+			teamvault.Url("https://vault.example.com"),
+			"https://vault.example.com/secrets/AbC123/?tab=1#top",
+			teamvault.Key("AbC123"),
+			false,
+		),
+		Entry(
+			"url without scheme",
+			teamvault.Url("https://vault.example.com"),
+			"vault.example.com/secrets/AbC123/",
+			teamvault.Key("AbC123"),
+			false,
+		),
+		Entry(
+			"host case and default port",
+			teamvault.Url("https://vault.example.com"),
+			"https://VAULT.example.com:443/secrets/AbC123/",
+			teamvault.Key("AbC123"),
+			false,
+		),
+		Entry(
+			"vault url with path",
+			teamvault.Url("https://example.com/teamvault/"),
+			"https://example.com/teamvault/secrets/AbC123/",
+			teamvault.Key("AbC123"),
+			false,
+		),
+		Entry(
+			"any host without vault url",
+			teamvault.Url(""),
+			"https://other.example.com/secrets/AbC123/",
+			teamvault.Key("AbC123"),
+			false,
+		),
+		Entry(
+			"other host",
+			teamvault.Url("https://vault.example.com"),
+			"https://other.example.com/secrets/AbC123/",
+			teamvault.Key(""),
+			true,
+		),
+		Entry(
+			"other port",
+			teamvault.Url("https://vault.example.com"),
+			"https://vault.example.com:8443/secrets/AbC123/",
+			teamvault.Key(""),
+			true,
+		),
+		Entry("empty", teamvault.Url(""), "", teamvault.Key(""), true),
+		Entry("key with slash", teamvault.Url(""), "AbC/123", teamvault.Key(""), true),
+		Entry(
+			"url without secret",
+			teamvault.Url(""),
+			"https://vault.example.com/",
+			teamvault.Key(""),
+			true,
+		),
+		Entry(
+			"unsupported scheme",
+			teamvault.Url(""),
+			"ftp://vault.example.com/secrets/AbC123/",
+			teamvault.Key(""),
+			true,
+		),
+	)
+
+	It("reports a host mismatch as ErrKeyHostMismatch", func() {
+		_, err := teamvault.NewKeyResolver("https://vault.example.com").
+			ResolveKey(ctx, "https://other.example.com/secrets/AbC123/")
+		Expect(errors.Is(err, teamvault.ErrKeyHostMismatch)).To(BeTrue())
+	})
+})
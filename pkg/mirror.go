@@ -0,0 +1,262 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package teamvault
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"os"
+
+	"github.com/bborbe/errors"
+)
+
+// DefaultMirrorKeyMapPath is the key map the mirror command writes by default.
+const DefaultMirrorKeyMapPath = "teamvault-mirror.json"
+
+// MirrorAction is what mirroring does with one secret.
+type MirrorAction string
+
+const (
+	// MirrorCreate creates the secret in the target.
+	MirrorCreate MirrorAction = "create"
+	// MirrorUpdate overwrites the target copy because the source revision
+	// changed since the last run.
+	MirrorUpdate MirrorAction = "update"
+	// MirrorUnchanged leaves the target copy alone; the source revision is
+	// the one mirrored last time.
+	MirrorUnchanged MirrorAction = "unchanged"
+	// MirrorConflict skips a secret whose name already exists in the target
+	// without being in the key map.
+	MirrorConflict MirrorAction = "conflict"
+	// MirrorFailed means reading or writing the secret failed.
+	MirrorFailed MirrorAction = "failed"
+)
+
+// MirrorResult reports what happened to one source secret.
+type MirrorResult struct {
+	SourceKey Key
+	Name      string
+	Action    MirrorAction
+	// TargetKey is the target copy, or the conflicting target secret.
+	TargetKey Key
+	Reason    string
+}
+
+// MirrorEntry records where a source secret was mirrored to and which of
+// its revisions.
+type MirrorEntry struct {
+	Key      Key             `json:"key"`
+	Revision CurrentRevision `json:"revision"`
+}
+
+// MirrorKeyMap maps source keys to their target copies.
+type MirrorKeyMap map[Key]MirrorEntry
+
+// MirrorKeyMapPath is the JSON file a mirror records its key map in.
+type MirrorKeyMapPath string
+
+// String returns the path.
+func (p MirrorKeyMapPath) String() string {
+	return string(p)
+}
+
+// Read reads the key map. A missing file is an empty map.
+func (p MirrorKeyMapPath) Read(ctx context.Context) (MirrorKeyMap, error) {
+	content, err := os.ReadFile(p.String())
+	if os.IsNotExist(err) {
+		return MirrorKeyMap{}, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "read key map %s failed", p)
+	}
+	keyMap := MirrorKeyMap{}
+	if err := json.Unmarshal(content, &keyMap); err != nil {
+		return nil, errors.Wrapf(ctx, err, "parse key map %s failed", p)
+	}
+	return keyMap, nil
+}
+
+// Write writes the key map. It holds keys only, no secret values.
+func (p MirrorKeyMapPath) Write(ctx context.Context, keyMap MirrorKeyMap) error {
+	content, err := json.MarshalIndent(keyMap, "", "  ")
+	if err != nil {
+		return errors.Wrapf(ctx, err, "marshal key map failed")
+	}
+	// #nosec G306 -- the key map holds keys only and is meant to be shared
+	if err := os.WriteFile(p.String(), append(content, '\n'), 0644); err != nil {
+		return errors.Wrapf(ctx, err, "write key map %s failed", p)
+	}
+	return nil
+}
+
+// MirrorOptions control a mirror run.
+type MirrorOptions struct {
+	// DryRun decides every action without reading values or writing.
+	DryRun bool
+	// KeyMap holds the copies of earlier runs; it is updated in place.
+	KeyMap MirrorKeyMap
+	// OnMirrored is called after each create or update, e.g. to persist
+	// the key map.
+	OnMirrored func(ctx context.Context, keyMap MirrorKeyMap) error
+}
+
+// Mirror copies secrets from one TeamVault to another.
+type Mirror interface {
+	// Mirror copies name, username, url, description and value of each
+	// source key. It keeps going after failures and returns an error if
+	// any occurred.
+	Mirror(ctx context.Context, keys []Key, options MirrorOptions) ([]MirrorResult, error)
+}
+
+// NewMirror creates a Mirror reading from source, which must implement
+// MetadataReader, and writing through targetWriter. target is searched to
+// detect name conflicts.
+func NewMirror(source Connector, target Connector, targetWriter Writer) Mirror {
+	return &mirror{
+		source:       source,
+		target:       target,
+		targetWriter: targetWriter,
+	}
+}
+
+type mirror struct {
+	source       Connector
+	target       Connector
+	targetWriter Writer
+}
+
+func (m *mirror) Mirror(
+	ctx context.Context,
+	keys []Key,
+	options MirrorOptions,
+) ([]MirrorResult, error) {
+	if options.KeyMap == nil {
+		options.KeyMap = MirrorKeyMap{}
+	}
+	results := make([]MirrorResult, 0, len(keys))
+	failed := 0
+	for _, key := range keys {
+		result := m.mirrorSecret(ctx, key, options)
+		if result.Action == MirrorFailed {
+			failed++
+		}
+		results = append(results, result)
+	}
+	if failed > 0 {
+		return results, errors.Errorf(ctx, "%d of %d secrets failed to mirror", failed, len(keys))
+	}
+	return results, nil
+}
+
+func (m *mirror) mirrorSecret(ctx context.Context, key Key, options MirrorOptions) MirrorResult {
+	result := MirrorResult{SourceKey: key}
+	metadata, err := ReadMetadata(ctx, m.source, key)
+	if err != nil {
+		result.Action, result.Reason = MirrorFailed, err.Error()
+		return result
+	}
+	result.Name = metadata.Name
+	if metadata.ContentType != ContentTypePassword && metadata.ContentType != ContentTypeFile {
+		result.Action = MirrorFailed
+		result.Reason = fmt.Sprintf("content type %s is not supported", metadata.ContentType)
+		return result
+	}
+
+	entry, mirrored := options.KeyMap[key]
+	switch {
+	case mirrored && metadata.CurrentRevision != "" &&
+		entry.Revision.ID() == metadata.CurrentRevision.ID():
+		result.Action, result.TargetKey = MirrorUnchanged, entry.Key
+		return result
+	case mirrored:
+		result.Action, result.TargetKey = MirrorUpdate, entry.Key
+	default:
+		result.Action = MirrorCreate
+		targetKey, err := NewNameResolver(m.target, false).Resolve(ctx, metadata.Name)
+		switch {
+		case err == nil:
+			result.Action, result.TargetKey = MirrorConflict, targetKey
+			result.Reason = "name exists in target; add it to the key map to mirror into it"
+			return result
+		case stderrors.Is(err, ErrNameAmbiguous):
+			result.Action, result.Reason = MirrorConflict, "name exists in target more than once"
+			return result
+		case !stderrors.Is(err, ErrNameNotFound):
+			result.Action, result.Reason = MirrorFailed, err.Error()
+			return result
+		}
+	}
+	if options.DryRun {
+		return result
+	}
+
+	if err := m.write(ctx, &result, metadata); err != nil {
+		result.Action, result.Reason = MirrorFailed, err.Error()
+		return result
+	}
+	options.KeyMap[key] = MirrorEntry{Key: result.TargetKey, Revision: metadata.CurrentRevision}
+	if options.OnMirrored != nil {
+		if err := options.OnMirrored(ctx, options.KeyMap); err != nil {
+			result.Action, result.Reason = MirrorFailed, err.Error()
+		}
+	}
+	return result
+}
+
+// write reads the source value and creates or overwrites the target copy.
+func (m *mirror) write(ctx context.Context, result *MirrorResult, metadata SecretMetadata) error {
+	var password Password
+	var fileContent []byte
+	switch metadata.ContentType {
+	case ContentTypePassword:
+		var err error
+		if password, err = m.source.Password(ctx, result.SourceKey); err != nil {
+			return errors.Wrapf(ctx, err, "read password failed")
+		}
+	case ContentTypeFile:
+		file, err := m.source.File(ctx, result.SourceKey)
+		if err != nil {
+			return errors.Wrapf(ctx, err, "read file failed")
+		}
+		if fileContent, err = base64.StdEncoding.DecodeString(file.String()); err != nil {
+			return errors.Wrapf(ctx, err, "decode file failed")
+		}
+	}
+
+	if result.Action == MirrorCreate {
+		key, _, err := m.targetWriter.Create(ctx, CreateSecret{
+			ContentType: metadata.ContentType,
+			Name:        metadata.Name,
+			Username:    metadata.Username,
+			Url:         metadata.Url.String(),
+			Description: metadata.Description,
+			Password:    password,
+			FileContent: fileContent,
+		})
+		if err != nil {
+			return errors.Wrapf(ctx, err, "create in target failed")
+		}
+		result.TargetKey = key
+		return nil
+	}
+	url := metadata.Url.String()
+	update := UpdateSecret{
+		Name:        &metadata.Name,
+		Username:    &metadata.Username,
+		Url:         &url,
+		Description: &metadata.Description,
+		FileContent: fileContent,
+	}
+	if metadata.ContentType == ContentTypePassword {
+		update.Password = &password
+	}
+	if _, _, err := m.targetWriter.Update(ctx, result.TargetKey, update); err != nil {
+		return errors.Wrapf(ctx, err, "update %s in target failed", result.TargetKey)
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package teamvault_test
+
+import (
+	"context"
+	stderrors "errors"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	teamvault "github.com/Seibert-Data/teamvault-cli/v5/pkg"
+	"github.com/Seibert-Data/teamvault-cli/v5/pkg/mocks"
+)
+
+var _ = Describe("Mirror", func() {
+	var ctx context.Context
+	var source metadataConnector
+	var target *mocks.Connector
+	var writer *mocks.Writer
+	var metadata map[teamvault.Key]teamvault.SecretMetadata
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		source = metadataConnector{
+			Connector:      &mocks.Connector{},
+			MetadataReader: &mocks.MetadataReader{},
+		}
+		metadata = map[teamvault.Key]teamvault.SecretMetadata{
+			"AbC123": {
+				Key:             "AbC123",
+				Name:            "payment-db",
+				Username:        "payment",
+				Url:             "postgres://db",
+				ContentType:     teamvault.ContentTypePassword,
+				CurrentRevision: "https://vault/api/secret-revisions/rev2/",
+			},
+			"XyZ789": {
+				Key:             "XyZ789",
+				Name:            "payment-tls",
+				ContentType:     teamvault.ContentTypeFile,
+				CurrentRevision: "https://vault/api/secret-revisions/rev7/",
+			},
+		}
+		source.MetadataReader.MetadataStub = func(
+			ctx context.Context,
+			key teamvault.Key,
+		) (teamvault.SecretMetadata, error) {
+			m, ok := metadata[key]
+			if !ok {
+				return teamvault.SecretMetadata{}, stderrors.New("not found")
+			}
+			return m, nil
+		}
+		source.PasswordReturns("s3cret", nil)
+		source.FileReturns("Y2VydA==", nil)
+		target = &mocks.Connector{}
+		writer = &mocks.Writer{}
+		writer.CreateReturns("NeW456", "", nil)
+		writer.UpdateReturns("OlD111", "", nil)
+	})
+
+	It("creates secrets missing in the target and records them", func() {
+		keyMap := teamvault.MirrorKeyMap{}
+		results, err := teamvault.NewMirror(source, target, writer).Mirror(
+			ctx,
+			[]teamvault.Key{"AbC123", "XyZ789"},
+			teamvault.MirrorOptions{KeyMap: keyMap},
+		)
+
+		Expect(err).To(BeNil())
+		Expect(results[0].Action).To(Equal(teamvault.MirrorCreate))
+		Expect(results[0].TargetKey).To(Equal(teamvault.Key("NeW456")))
+		_, password := writer.CreateArgsForCall(0)
+		Expect(password).To(Equal(teamvault.CreateSecret{
+			ContentType: teamvault.ContentTypePassword,
+			Name:        "payment-db",
+			Username:    "payment",
+			Url:         "postgres://db",
+			Password:    "s3cret",
+		}))
+		_, file := writer.CreateArgsForCall(1)
+		Expect(file.FileContent).To(Equal([]byte("cert")))
+		Expect(keyMap["AbC123"]).To(Equal(teamvault.MirrorEntry{
+			Key:      "NeW456",
+			Revision: "https://vault/api/secret-revisions/rev2/",
+		}))
+	})
+
+	It("updates only secrets whose source revision changed", func() {
+		keyMap := teamvault.MirrorKeyMap{
+			"AbC123": {Key: "OlD111", Revision: "rev1"},
+			"XyZ789": {Key: "OlD222", Revision: "rev7"},
+		}
+		results, err := teamvault.NewMirror(source, target, writer).Mirror(
+			ctx,
+			[]teamvault.Key{"AbC123", "XyZ789"},
+			teamvault.MirrorOptions{KeyMap: keyMap},
+		)
+
+		Expect(err).To(BeNil())
+		Expect(results[0].Action).To(Equal(teamvault.MirrorUpdate))
+		Expect(results[1].Action).To(Equal(teamvault.MirrorUnchanged))
+		Expect(results[1].TargetKey).To(Equal(teamvault.Key("OlD222")))
+		Expect(writer.UpdateCallCount()).To(Equal(1))
+		_, key, update := writer.UpdateArgsForCall(0)
+		Expect(key).To(Equal(teamvault.Key("OlD111")))
+		Expect(*update.Name).To(Equal("payment-db"))
+		Expect(*update.Url).To(Equal("postgres://db"))
+		Expect(*update.Password).To(Equal(teamvault.Password("s3cret")))
+		Expect(keyMap["AbC123"].Revision).
+			To(Equal(teamvault.CurrentRevision("https://vault/api/secret-revisions/rev2/")))
+		Expect(target.SearchCallCount()).To(Equal(0))
+	})
+
+	It("reports a name that exists in the target as conflict", func() {
+		target.SearchReturns([]teamvault.SearchResult{{Key: "TaR999", Name: "payment-db"}}, nil)
+
+		results, err := teamvault.NewMirror(source, target, writer).Mirror(
+			ctx,
+			[]teamvault.Key{"AbC123"},
+			teamvault.MirrorOptions{},
+		)
+
+		Expect(err).To(BeNil())
+		Expect(results[0].Action).To(Equal(teamvault.MirrorConflict))
+		Expect(results[0].TargetKey).To(Equal(teamvault.Key("TaR999")))
+		Expect(writer.Invocations()).To(BeEmpty())
+	})
+
+	It("decides without reading values or writing on a dry run", func() {
+		keyMap := teamvault.MirrorKeyMap{}
+		results, err := teamvault.NewMirror(source, target, nil).Mirror(
+			ctx,
+			[]teamvault.Key{"AbC123"},
+			teamvault.MirrorOptions{DryRun: true, KeyMap: keyMap},
+		)
+
+		Expect(err).To(BeNil())
+		Expect(results[0].Action).To(Equal(teamvault.MirrorCreate))
+		Expect(source.PasswordCallCount()).To(Equal(0))
+		Expect(keyMap).To(BeEmpty())
+	})
+
+	It("keeps going after a failure and reports it", func() {
+		results, err := teamvault.NewMirror(source, target, writer).Mirror(
+			ctx,
+			[]teamvault.Key{"MiS000", "AbC123"},
+			teamvault.MirrorOptions{},
+		)
+
+		Expect(err).NotTo(BeNil())
+		Expect(results[0].Action).To(Equal(teamvault.MirrorFailed))
+		Expect(results[1].Action).To(Equal(teamvault.MirrorCreate))
+	})
+
+	It("MirrorKeyMapPath round-trips", func() {
+		path := teamvault.MirrorKeyMapPath(filepath.Join(GinkgoT().TempDir(), "mirror.json"))
+		keyMap, err := path.Read(ctx)
+		Expect(err).To(BeNil())
+		Expect(keyMap).To(BeEmpty())
+
+		keyMap = teamvault.MirrorKeyMap{"AbC123": {Key: "NeW456", Revision: "rev2"}}
+		Expect(path.Write(ctx, keyMap)).To(Succeed())
+		Expect(path.Read(ctx)).To(Equal(keyMap))
+	})
+})
@@ -0,0 +1,117 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	teamvault "github.com/Seibert-Data/teamvault-cli/v5/pkg"
+)
+
+type KeyResolver struct {
+	ResolveKeyStub        func(context.Context, string) (teamvault.Key, error)
+	resolveKeyMutex       sync.RWMutex
+	resolveKeyArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	resolveKeyReturns struct {
+		result1 teamvault.Key
+		result2 error
+	}
+	resolveKeyReturnsOnCall map[int]struct {
+		result1 teamvault.Key
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *KeyResolver) ResolveKey(arg1 context.Context, arg2 string) (teamvault.Key, error) {
+	fake.resolveKeyMutex.Lock()
+	ret, specificReturn := fake.resolveKeyReturnsOnCall[len(fake.resolveKeyArgsForCall)]
+	fake.resolveKeyArgsForCall = append(fake.resolveKeyArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.ResolveKeyStub
+	fakeReturns := fake.resolveKeyReturns
+	fake.recordInvocation("ResolveKey", []interface{}{arg1, arg2})
+	fake.resolveKeyMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *KeyResolver) ResolveKeyCallCount() int {
+	fake.resolveKeyMutex.RLock()
+	defer fake.resolveKeyMutex.RUnlock()
+	return len(fake.resolveKeyArgsForCall)
+}
+
+func (fake *KeyResolver) ResolveKeyCalls(stub func(context.Context, string) (teamvault.Key, error)) {
+	fake.resolveKeyMutex.Lock()
+	defer fake.resolveKeyMutex.Unlock()
+	fake.ResolveKeyStub = stub
+}
+
+func (fake *KeyResolver) ResolveKeyArgsForCall(i int) (context.Context, string) {
+	fake.resolveKeyMutex.RLock()
+	defer fake.resolveKeyMutex.RUnlock()
+	argsForCall := fake.resolveKeyArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *KeyResolver) ResolveKeyReturns(result1 teamvault.Key, result2 error) {
+	fake.resolveKeyMutex.Lock()
+	defer fake.resolveKeyMutex.Unlock()
+	fake.ResolveKeyStub = nil
+	fake.resolveKeyReturns = struct {
+		result1 teamvault.Key
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *KeyResolver) ResolveKeyReturnsOnCall(i int, result1 teamvault.Key, result2 error) {
+	fake.resolveKeyMutex.Lock()
+	defer fake.resolveKeyMutex.Unlock()
+	fake.ResolveKeyStub = nil
+	if fake.resolveKeyReturnsOnCall == nil {
+		fake.resolveKeyReturnsOnCall = make(map[int]struct {
+			result1 teamvault.Key
+			result2 error
+		})
+	}
+	fake.resolveKeyReturnsOnCall[i] = struct {
+		result1 teamvault.Key
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *KeyResolver) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *KeyResolver) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ teamvault.KeyResolver = new(KeyResolver)
@@ -0,0 +1,117 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	teamvault "github.com/Seibert-Data/teamvault-cli/v5/pkg"
+)
+
+type MetadataReader struct {
+	MetadataStub        func(context.Context, teamvault.Key) (teamvault.SecretMetadata, error)
+	metadataMutex       sync.RWMutex
+	metadataArgsForCall []struct {
+		arg1 context.Context
+		arg2 teamvault.Key
+	}
+	metadataReturns struct {
+		result1 teamvault.SecretMetadata
+		result2 error
+	}
+	metadataReturnsOnCall map[int]struct {
+		result1 teamvault.SecretMetadata
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *MetadataReader) Metadata(arg1 context.Context, arg2 teamvault.Key) (teamvault.SecretMetadata, error) {
+	fake.metadataMutex.Lock()
+	ret, specificReturn := fake.metadataReturnsOnCall[len(fake.metadataArgsForCall)]
+	fake.metadataArgsForCall = append(fake.metadataArgsForCall, struct {
+		arg1 context.Context
+		arg2 teamvault.Key
+	}{arg1, arg2})
+	stub := fake.MetadataStub
+	fakeReturns := fake.metadataReturns
+	fake.recordInvocation("Metadata", []interface{}{arg1, arg2})
+	fake.metadataMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *MetadataReader) MetadataCallCount() int {
+	fake.metadataMutex.RLock()
+	defer fake.metadataMutex.RUnlock()
+	return len(fake.metadataArgsForCall)
+}
+
+func (fake *MetadataReader) MetadataCalls(stub func(context.Context, teamvault.Key) (teamvault.SecretMetadata, error)) {
+	fake.metadataMutex.Lock()
+	defer fake.metadataMutex.Unlock()
+	fake.MetadataStub = stub
+}
+
+func (fake *MetadataReader) MetadataArgsForCall(i int) (context.Context, teamvault.Key) {
+	fake.metadataMutex.RLock()
+	defer fake.metadataMutex.RUnlock()
+	argsForCall := fake.metadataArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *MetadataReader) MetadataReturns(result1 teamvault.SecretMetadata, result2 error) {
+	fake.metadataMutex.Lock()
+	defer fake.metadataMutex.Unlock()
+	fake.MetadataStub = nil
+	fake.metadataReturns = struct {
+		result1 teamvault.SecretMetadata
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *MetadataReader) MetadataReturnsOnCall(i int, result1 teamvault.SecretMetadata, result2 error) {
+	fake.metadataMutex.Lock()
+	defer fake.metadataMutex.Unlock()
+	fake.MetadataStub = nil
+	if fake.metadataReturnsOnCall == nil {
+		fake.metadataReturnsOnCall = make(map[int]struct {
+			result1 teamvault.SecretMetadata
+			result2 error
+		})
+	}
+	fake.metadataReturnsOnCall[i] = struct {
+		result1 teamvault.SecretMetadata
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *MetadataReader) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *MetadataReader) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ teamvault.MetadataReader = new(MetadataReader)
@@ -0,0 +1,159 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package teamvault
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/bborbe/errors"
+	"github.com/golang/glog"
+)
+
+var (
+	// ErrNameNotFound is returned when no secret carries the requested name.
+	ErrNameNotFound = stderrors.New("no secret with this name")
+	// ErrNameAmbiguous is returned when a name matches more than one secret.
+	ErrNameAmbiguous = stderrors.New("secret name is ambiguous")
+)
+
+// minFuzzyScore is the score a fuzzy candidate needs to be picked; see
+// fuzzyNameScore.
+const minFuzzyScore = 0.75
+
+// maxCandidates caps the candidates listed in resolve errors.
+const maxCandidates = 10
+
+// NameResolver resolves a human-readable secret name to its key.
+type NameResolver interface {
+	Resolve(ctx context.Context, name string) (Key, error)
+}
+
+// NewNameResolver creates a NameResolver searching via the given Connector.
+// Names must match exactly unless fuzzy is set; fuzzy only applies when no
+// exact match exists and exactly one candidate scores highly.
+func NewNameResolver(connector Connector, fuzzy bool) NameResolver {
+	return &nameResolver{
+		connector: connector,
+		fuzzy:     fuzzy,
+	}
+}
+
+type nameResolver struct {
+	connector Connector
+	fuzzy     bool
+}
+
+func (n *nameResolver) Resolve(ctx context.Context, name string) (Key, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", errors.New(ctx, "secret name empty")
+	}
+	results, err := n.connector.Search(ctx, name)
+	if err != nil {
+		return "", errors.Wrapf(ctx, err, "search for name %q failed", name)
+	}
+
+	var exact []SearchResult
+	for _, result := range results {
+		if result.Name == name {
+			exact = append(exact, result)
+		}
+	}
+	switch len(exact) {
+	case 1:
+		return exact[0].Key, nil
+	case 0:
+	default:
+		return "", errors.Wrapf(
+			ctx,
+			ErrNameAmbiguous,
+			"name %q matches %d secrets (%s)",
+			name,
+			len(exact),
+			describeCandidates(exact),
+		)
+	}
+
+	if n.fuzzy {
+		if key, ok := pickFuzzy(name, results); ok {
+			glog.V(2).Infof("fuzzy name %q resolved to %v", name, key)
+			return key, nil
+		}
+	}
+	if len(results) == 0 {
+		return "", errors.Wrapf(ctx, ErrNameNotFound, "name %q", name)
+	}
+	return "", errors.Wrapf(
+		ctx,
+		ErrNameNotFound,
+		"name %q has no exact match; candidates: %s",
+		name,
+		describeCandidates(results),
+	)
+}
+
+// pickFuzzy returns the single candidate scoring at least minFuzzyScore.
+// Several high scorers are treated as no match.
+func pickFuzzy(name string, results []SearchResult) (Key, bool) {
+	var picked []SearchResult
+	for _, result := range results {
+		if fuzzyNameScore(name, result.Name) >= minFuzzyScore {
+			picked = append(picked, result)
+		}
+	}
+	if len(picked) != 1 {
+		return "", false
+	}
+	return picked[0].Key, true
+}
+
+// fuzzyNameScore compares the words of a query and a secret name,
+// case-insensitively and ignoring punctuation, so "prod postgres admin"
+// matches "Prod-Postgres (admin)". A query word also matches a name word it
+// is a prefix of. The result is the Jaccard index in [0, 1].
+func fuzzyNameScore(query, name string) float64 {
+	queryWords := nameWords(query)
+	nameWordList := nameWords(name)
+	if len(queryWords) == 0 || len(nameWordList) == 0 {
+		return 0
+	}
+	matched := 0
+	for _, q := range queryWords {
+		for _, w := range nameWordList {
+			if strings.HasPrefix(w, q) {
+				matched++
+				break
+			}
+		}
+	}
+	union := len(queryWords) + len(nameWordList) - matched
+	return float64(matched) / float64(union)
+}
+
+func nameWords(value string) []string {
+	return strings.FieldsFunc(strings.ToLower(value), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// describeCandidates lists candidates as `KEY (name)` sorted by key, capped
+// at maxCandidates so a broad search still yields a readable error.
+func describeCandidates(results []SearchResult) string {
+	candidates := make([]string, 0, len(results))
+	for _, result := range results {
+		candidates = append(candidates, fmt.Sprintf("%s (%s)", result.Key, result.Name))
+	}
+	sort.Strings(candidates)
+	if len(candidates) > maxCandidates {
+		more := len(candidates) - maxCandidates
+		candidates = append(candidates[:maxCandidates], fmt.Sprintf("and %d more", more))
+	}
+	return strings.Join(candidates, ", ")
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package teamvault_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	teamvault "github.com/Seibert-Data/teamvault-cli/v5/pkg"
+	"github.com/Seibert-Data/teamvault-cli/v5/pkg/mocks"
+)
+
+var _ = Describe("NameResolver", func() {
+	var ctx context.Context
+	var connector *mocks.Connector
+	var fuzzy bool
+	var name string
+	var key teamvault.Key
+	var err error
+	BeforeEach(func() {
+		ctx = context.Background()
+		connector = &mocks.Connector{}
+		fuzzy = false
+		connector.SearchReturns([]teamvault.SearchResult{
+			{Key: "AbC123", Name: "prod postgres admin"},
+			{Key: "DeF456", Name: "prod postgres admin (old)"},
+			{Key: "GhI789", Name: "staging postgres admin"},
+		}, nil)
+	})
+	JustBeforeEach(func() {
+		key, err = teamvault.NewNameResolver(connector, fuzzy).Resolve(ctx, name)
+	})
+	Context("exact match", func() {
+		BeforeEach(func() {
+			name = "prod postgres admin"
+		})
+		It("returns the key", func() {
+			Expect(err).To(BeNil())
+			Expect(key).To(Equal(teamvault.Key("AbC123")))
+		})
+		It("searches for the name", func() {
+			_, query := connector.SearchArgsForCall(0)
+			Expect(query).To(Equal("prod postgres admin"))
+		})
+	})
+	Context("ambiguous name", func() {
+		BeforeEach(func() {
+			connector.SearchReturns([]teamvault.SearchResult{
+				{Key: "AbC123", Name: "db"},
+				{Key: "XyZ789", Name: "db"},
+			}, nil)
+			name = "db"
+		})
+		It("lists the candidates", func() {
+			Expect(errors.Is(err, teamvault.ErrNameAmbiguous)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("AbC123 (db), XyZ789 (db)"))
+		})
+	})
+	Context("no exact match", func() {
+		BeforeEach(func() {
+			name = "Prod-Postgres"
+		})
+		It("lists the search results as candidates", func() {
+			Expect(errors.Is(err, teamvault.ErrNameNotFound)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("AbC123 (prod postgres admin)"))
+		})
+	})
+	Context("nothing found", func() {
+		BeforeEach(func() {
+			connector.SearchReturns(nil, nil)
+			name = "unknown"
+		})
+		It("returns ErrNameNotFound", func() {
+			Expect(errors.Is(err, teamvault.ErrNameNotFound)).To(BeTrue())
+		})
+	})
+	Context("fuzzy", func() {
+		BeforeEach(func() {
+			fuzzy = true
+		})
+		Context("with a single high scorer", func() {
+			BeforeEach(func() {
+				name = "Staging-Postgres Admin"
+			})
+			It("picks it", func() {
+				Expect(err).To(BeNil())
+				Expect(key).To(Equal(teamvault.Key("GhI789")))
+			})
+		})
+		Context("with word prefixes", func() {
+			BeforeEach(func() {
+				name = "stag postg adm"
+			})
+			It("picks it", func() {
+				Expect(err).To(BeNil())
+				Expect(key).To(Equal(teamvault.Key("GhI789")))
+			})
+		})
+		Context("with several high scorers", func() {
+			BeforeEach(func() {
+				name = "prod postgres"
+			})
+			It("refuses to guess", func() {
+				Expect(errors.Is(err, teamvault.ErrNameNotFound)).To(BeTrue())
+			})
+		})
+		Context("still prefers an exact match", func() {
+			BeforeEach(func() {
+				name = "prod postgres admin"
+			})
+			It("returns it", func() {
+				Expect(err).To(BeNil())
+				Expect(key).To(Equal(teamvault.Key("AbC123")))
+			})
+		})
+	})
+})
@@ -0,0 +1,285 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package teamvault
+
+import (
+	"context"
+	"crypto/rand"
+	_ "embed"
+	"io"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/bborbe/errors"
+)
+
+// PasswordCharacterClass is a set of characters a generated password draws
+// from.
+type PasswordCharacterClass string
+
+const (
+	PasswordLower   PasswordCharacterClass = "lower"
+	PasswordUpper   PasswordCharacterClass = "upper"
+	PasswordDigits  PasswordCharacterClass = "digits"
+	PasswordSymbols PasswordCharacterClass = "symbols"
+)
+
+// PasswordCharacterClasses lists every class in the order passwords are
+// built from them.
+var PasswordCharacterClasses = []PasswordCharacterClass{
+	PasswordLower,
+	PasswordUpper,
+	PasswordDigits,
+	PasswordSymbols,
+}
+
+// Characters returns the characters of the class.
+func (c PasswordCharacterClass) Characters() string {
+	switch c {
+	case PasswordLower:
+		return "abcdefghijklmnopqrstuvwxyz"
+	case PasswordUpper:
+		return "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	case PasswordDigits:
+		return "0123456789"
+	case PasswordSymbols:
+		return "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~"
+	default:
+		return ""
+	}
+}
+
+// maxPasswordLength keeps a typo in --length from generating megabytes.
+const maxPasswordLength = 4096
+
+// PasswordPolicy describes a locally generated password.
+type PasswordPolicy struct {
+	Length  int
+	Classes []PasswordCharacterClass
+	// Exclude lists characters never used, e.g. symbols a target rejects.
+	Exclude string
+	// MinCounts is the minimum number of characters per class. Classes not
+	// listed appear at least once.
+	MinCounts map[PasswordCharacterClass]int
+}
+
+// DefaultPasswordPolicy returns 24 characters from all classes.
+func DefaultPasswordPolicy() PasswordPolicy {
+	return PasswordPolicy{
+		Length:  24,
+		Classes: PasswordCharacterClasses,
+	}
+}
+
+// minCount returns how many characters of the class the password needs.
+func (p PasswordPolicy) minCount(class PasswordCharacterClass) int {
+	if count, ok := p.MinCounts[class]; ok {
+		return count
+	}
+	return 1
+}
+
+// characters returns the characters of the class left after exclusions.
+func (p PasswordPolicy) characters(class PasswordCharacterClass) string {
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(p.Exclude, r) {
+			return -1
+		}
+		return r
+	}, class.Characters())
+}
+
+// Validate returns an error if no password can satisfy the policy.
+func (p PasswordPolicy) Validate(ctx context.Context) error {
+	if p.Length < 1 || p.Length > maxPasswordLength {
+		return errors.Errorf(ctx, "length must be between 1 and %d", maxPasswordLength)
+	}
+	if len(p.Classes) == 0 {
+		return errors.New(ctx, "at least one character class is required")
+	}
+	selected := map[PasswordCharacterClass]bool{}
+	required := 0
+	for _, class := range p.Classes {
+		if class.Characters() == "" {
+			return errors.Errorf(ctx, "unknown character class %q", class)
+		}
+		if selected[class] {
+			return errors.Errorf(ctx, "character class %s is listed twice", class)
+		}
+		selected[class] = true
+		if p.characters(class) == "" {
+			return errors.Errorf(
+				ctx,
+				"character class %s has no characters left after excluding",
+				class,
+			)
+		}
+		required += p.minCount(class)
+	}
+	for class, count := range p.MinCounts {
+		if count < 0 {
+			return errors.Errorf(ctx, "minimum count of %s must not be negative", class)
+		}
+		if count > 0 && !selected[class] {
+			return errors.Errorf(ctx, "minimum count of %s requires that class", class)
+		}
+	}
+	if required > p.Length {
+		return errors.Errorf(
+			ctx,
+			"minimum counts need %d characters but length is %d",
+			required,
+			p.Length,
+		)
+	}
+	return nil
+}
+
+// PassphrasePolicy describes a diceware passphrase.
+type PassphrasePolicy struct {
+	Words     int
+	Separator string
+	// Capitalize upper-cases the first letter of each word.
+	Capitalize bool
+}
+
+// DefaultPassphrasePolicy returns six words joined by dashes, about 77 bits
+// of entropy.
+func DefaultPassphrasePolicy() PassphrasePolicy {
+	return PassphrasePolicy{
+		Words:     6,
+		Separator: "-",
+	}
+}
+
+// maxPassphraseWords keeps a typo in --words from generating a novel.
+const maxPassphraseWords = 64
+
+// Validate returns an error if the word count is out of range.
+func (p PassphrasePolicy) Validate(ctx context.Context) error {
+	if p.Words < 1 || p.Words > maxPassphraseWords {
+		return errors.Errorf(ctx, "words must be between 1 and %d", maxPassphraseWords)
+	}
+	return nil
+}
+
+// effLargeWordlist is the EFF large diceware wordlist, one "<dice>\t<word>"
+// line per word (https://www.eff.org/dice, licensed CC BY 3.0 US).
+//
+//go:embed eff_large_wordlist.txt
+var effLargeWordlist string
+
+// dicewareWords returns the 7776 words of the embedded wordlist.
+var dicewareWords = sync.OnceValue(func() []string {
+	lines := strings.Split(strings.TrimSpace(effLargeWordlist), "\n")
+	words := make([]string, 0, len(lines))
+	for _, line := range lines {
+		_, word, _ := strings.Cut(line, "\t")
+		words = append(words, word)
+	}
+	return words
+})
+
+// LocalPasswordGenerator generates passwords and passphrases on the client,
+// independent of the TeamVault server's password policy.
+type LocalPasswordGenerator interface {
+	// Password returns a random password satisfying the policy.
+	Password(ctx context.Context, policy PasswordPolicy) (Password, error)
+	// Passphrase returns random words from the EFF large diceware wordlist.
+	Passphrase(ctx context.Context, policy PassphrasePolicy) (Password, error)
+}
+
+// NewLocalPasswordGenerator creates a LocalPasswordGenerator reading
+// randomness from random, normally crypto/rand.Reader.
+func NewLocalPasswordGenerator(random io.Reader) LocalPasswordGenerator {
+	return &localPasswordGenerator{
+		random: random,
+	}
+}
+
+type localPasswordGenerator struct {
+	random io.Reader
+}
+
+func (g *localPasswordGenerator) Password(
+	ctx context.Context,
+	policy PasswordPolicy,
+) (Password, error) {
+	if err := policy.Validate(ctx); err != nil {
+		return "", errors.Wrapf(ctx, err, "invalid password policy")
+	}
+	// Draw the required minimum of each class, fill up from all selected
+	// classes, then shuffle so the required characters are not up front.
+	var all strings.Builder
+	password := make([]byte, 0, policy.Length)
+	for _, class := range policy.Classes {
+		characters := policy.characters(class)
+		all.WriteString(characters)
+		for range policy.minCount(class) {
+			c, err := g.pick(ctx, characters)
+			if err != nil {
+				return "", err
+			}
+			password = append(password, c)
+		}
+	}
+	for len(password) < policy.Length {
+		c, err := g.pick(ctx, all.String())
+		if err != nil {
+			return "", err
+		}
+		password = append(password, c)
+	}
+	for i := len(password) - 1; i > 0; i-- {
+		j, err := g.intn(ctx, i+1)
+		if err != nil {
+			return "", err
+		}
+		password[i], password[j] = password[j], password[i]
+	}
+	return Password(password), nil
+}
+
+func (g *localPasswordGenerator) Passphrase(
+	ctx context.Context,
+	policy PassphrasePolicy,
+) (Password, error) {
+	if err := policy.Validate(ctx); err != nil {
+		return "", errors.Wrapf(ctx, err, "invalid passphrase policy")
+	}
+	words := dicewareWords()
+	selected := make([]string, 0, policy.Words)
+	for range policy.Words {
+		i, err := g.intn(ctx, len(words))
+		if err != nil {
+			return "", err
+		}
+		word := words[i]
+		if policy.Capitalize {
+			word = strings.ToUpper(word[:1]) + word[1:]
+		}
+		selected = append(selected, word)
+	}
+	return Password(strings.Join(selected, policy.Separator)), nil
+}
+
+// pick returns a uniformly random character of the ASCII string characters.
+func (g *localPasswordGenerator) pick(ctx context.Context, characters string) (byte, error) {
+	i, err := g.intn(ctx, len(characters))
+	if err != nil {
+		return 0, err
+	}
+	return characters[i], nil
+}
+
+// intn returns a uniformly random int in [0, n).
+func (g *localPasswordGenerator) intn(ctx context.Context, n int) (int, error) {
+	i, err := rand.Int(g.random, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, errors.Wrapf(ctx, err, "read randomness failed")
+	}
+	return int(i.Int64()), nil
+}
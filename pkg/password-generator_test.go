@@ -0,0 +1,132 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package teamvault_test
+
+import (
+	"context"
+	"crypto/rand"
+	"strings"
+	"testing/iotest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	teamvault "github.com/Seibert-Data/teamvault-cli/v5/pkg"
+)
+
+var _ = Describe("LocalPasswordGenerator", func() {
+	var ctx context.Context
+	var generator teamvault.LocalPasswordGenerator
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		generator = teamvault.NewLocalPasswordGenerator(rand.Reader)
+	})
+
+	countOf := func(password teamvault.Password, class teamvault.PasswordCharacterClass) int {
+		count := 0
+		for _, r := range password.String() {
+			if strings.ContainsRune(class.Characters(), r) {
+				count++
+			}
+		}
+		return count
+	}
+
+	It("generates the default policy with every class", func() {
+		password, err := generator.Password(ctx, teamvault.DefaultPasswordPolicy())
+
+		Expect(err).To(BeNil())
+		Expect(password).To(HaveLen(24))
+		for _, class := range teamvault.PasswordCharacterClasses {
+			Expect(countOf(password, class)).To(BeNumerically(">=", 1), string(class))
+		}
+	})
+
+	It("honours classes, exclusions and minimum counts", func() {
+		policy := teamvault.PasswordPolicy{
+			Length: 12,
+			Classes: []teamvault.PasswordCharacterClass{
+				teamvault.PasswordLower,
+				teamvault.PasswordDigits,
+			},
+			Exclude:   "0o1l",
+			MinCounts: map[teamvault.PasswordCharacterClass]int{teamvault.PasswordDigits: 5},
+		}
+		for range 50 {
+			password, err := generator.Password(ctx, policy)
+
+			Expect(err).To(BeNil())
+			Expect(password).To(HaveLen(12))
+			Expect(password.String()).To(MatchRegexp(`^[a-z2-9]+$`))
+			Expect(password.String()).NotTo(ContainSubstring("o"))
+			Expect(password.String()).NotTo(ContainSubstring("l"))
+			Expect(countOf(password, teamvault.PasswordDigits)).To(BeNumerically(">=", 5))
+		}
+	})
+
+	DescribeTable("rejects unsatisfiable policies",
+		func(policy teamvault.PasswordPolicy, message string) {
+			_, err := generator.Password(ctx, policy)
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(ContainSubstring(message))
+		},
+		Entry("zero length", teamvault.PasswordPolicy{
+			Classes: teamvault.PasswordCharacterClasses,
+		}, "length must be between"),
+		Entry("no classes", teamvault.PasswordPolicy{Length: 8}, "at least one character class"),
+		Entry("unknown class", teamvault.PasswordPolicy{
+			Length:  8,
+			Classes: []teamvault.PasswordCharacterClass{"emoji"},
+		}, `unknown character class "emoji"`),
+		Entry("class fully excluded", teamvault.PasswordPolicy{
+			Length:  8,
+			Classes: []teamvault.PasswordCharacterClass{teamvault.PasswordDigits},
+			Exclude: "0123456789",
+		}, "digits has no characters left"),
+		Entry("minimum of an unselected class", teamvault.PasswordPolicy{
+			Length:    8,
+			Classes:   []teamvault.PasswordCharacterClass{teamvault.PasswordLower},
+			MinCounts: map[teamvault.PasswordCharacterClass]int{teamvault.PasswordSymbols: 1},
+		}, "minimum count of symbols requires that class"),
+		Entry("minimums longer than the password", teamvault.PasswordPolicy{
+			Length:    4,
+			Classes:   []teamvault.PasswordCharacterClass{teamvault.PasswordLower},
+			MinCounts: map[teamvault.PasswordCharacterClass]int{teamvault.PasswordLower: 5},
+		}, "need 5 characters but length is 4"),
+	)
+
+	It("generates diceware passphrases", func() {
+		passphrase, err := generator.Passphrase(ctx, teamvault.PassphrasePolicy{
+			Words:      5,
+			Separator:  " ",
+			Capitalize: true,
+		})
+
+		Expect(err).To(BeNil())
+		words := strings.Split(passphrase.String(), " ")
+		Expect(words).To(HaveLen(5))
+		for _, word := range words {
+			Expect(word).To(MatchRegexp(`^[A-Z][a-z-]+$`))
+		}
+	})
+
+	It("uses the default passphrase policy", func() {
+		passphrase, err := generator.Passphrase(ctx, teamvault.DefaultPassphrasePolicy())
+
+		Expect(err).To(BeNil())
+		// Some wordlist entries contain a dash themselves.
+		Expect(strings.Count(passphrase.String(), "-")).To(BeNumerically(">=", 5))
+	})
+
+	It("returns an error if randomness fails", func() {
+		generator = teamvault.NewLocalPasswordGenerator(iotest.ErrReader(iotest.ErrTimeout))
+
+		_, err := generator.Password(ctx, teamvault.DefaultPasswordPolicy())
+		Expect(err).NotTo(BeNil())
+		_, err = generator.Passphrase(ctx, teamvault.DefaultPassphrasePolicy())
+		Expect(err).NotTo(BeNil())
+	})
+})
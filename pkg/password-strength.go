@@ -0,0 +1,296 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package teamvault
+
+import (
+	_ "embed"
+	"math"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// PasswordStrength is a zxcvbn-style estimate of how hard a password is to
+// guess.
+type PasswordStrength struct {
+	// Entropy is the estimated guessing entropy in bits.
+	Entropy float64
+	// Score ranks Entropy from 0 (too guessable) to 4 (very unguessable)
+	// with the guess thresholds of zxcvbn (10^3, 10^6, 10^8, 10^10).
+	Score int
+}
+
+// maxStrengthLength bounds the pattern search; characters beyond it count
+// as random.
+const maxStrengthLength = 128
+
+// minPatternLength is the shortest token matched as a pattern.
+const minPatternLength = 3
+
+// commonPasswordsList holds frequently used passwords, most common first.
+//
+//go:embed common_passwords.txt
+var commonPasswordsList string
+
+// commonPasswordRanks maps each common password to its rank, starting at 1.
+var commonPasswordRanks = sync.OnceValue(func() map[string]int {
+	ranks := map[string]int{}
+	for i, word := range strings.Fields(commonPasswordsList) {
+		ranks[word] = i + 1
+	}
+	return ranks
+})
+
+// dicewareWordSet is the EFF wordlist as a set, used as English dictionary.
+var dicewareWordSet = sync.OnceValue(func() map[string]bool {
+	words := dicewareWords()
+	set := make(map[string]bool, len(words))
+	for _, word := range words {
+		set[word] = true
+	}
+	return set
+})
+
+// sequenceAlphabets are the runs matched as sequences, forwards and
+// backwards: the alphabet, digits and qwerty keyboard rows.
+var sequenceAlphabets = []string{
+	"abcdefghijklmnopqrstuvwxyz",
+	"01234567890",
+	"qwertyuiop",
+	"asdfghjkl",
+	"zxcvbnm",
+	"qwertzuiop",
+	"yxcvbnm",
+}
+
+// leetSubstitutions undoes common character substitutions before dictionary
+// lookups.
+var leetSubstitutions = map[rune]rune{
+	'@': 'a',
+	'4': 'a',
+	'3': 'e',
+	'1': 'i',
+	'!': 'i',
+	'0': 'o',
+	'$': 's',
+	'5': 's',
+	'7': 't',
+	'+': 't',
+	'|': 'l',
+}
+
+// EstimatePasswordStrength estimates the entropy of password as the cheapest
+// split into dictionary words (common passwords, the EFF wordlist and
+// userInputs such as name and username, also with leet substitutions),
+// repeats, sequences, years and random characters.
+func EstimatePasswordStrength(password string, userInputs ...string) PasswordStrength {
+	runes := []rune(password)
+	if len(runes) == 0 {
+		return PasswordStrength{}
+	}
+	inputs := map[string]bool{}
+	for _, input := range userInputs {
+		for _, field := range strings.FieldsFunc(strings.ToLower(input), isSeparator) {
+			if len(field) >= minPatternLength {
+				inputs[field] = true
+			}
+		}
+	}
+	charBits := math.Log2(float64(bruteforcePool(runes)))
+	n := min(len(runes), maxStrengthLength)
+	// best[j] is the lowest entropy of the first j characters.
+	best := make([]float64, n+1)
+	for j := 1; j <= n; j++ {
+		best[j] = best[j-1] + charBits
+		for i := 0; i <= j-minPatternLength; i++ {
+			if bits, ok := patternEntropy(runes[i:j], inputs); ok && best[i]+bits < best[j] {
+				best[j] = best[i] + bits
+			}
+		}
+	}
+	entropy := best[n] + float64(len(runes)-n)*charBits
+	return PasswordStrength{
+		Entropy: entropy,
+		Score:   strengthScore(entropy),
+	}
+}
+
+// strengthScore maps entropy to the zxcvbn score.
+func strengthScore(entropy float64) int {
+	guesses := math.Pow(2, entropy)
+	switch {
+	case guesses < 1e3:
+		return 0
+	case guesses < 1e6:
+		return 1
+	case guesses < 1e8:
+		return 2
+	case guesses < 1e10:
+		return 3
+	default:
+		return 4
+	}
+}
+
+// bruteforcePool returns the number of characters an attacker guessing
+// each character at random has to try.
+func bruteforcePool(runes []rune) int {
+	var lower, upper, digit, symbol, other bool
+	for _, r := range runes {
+		switch {
+		case r >= 'a' && r <= 'z':
+			lower = true
+		case r >= 'A' && r <= 'Z':
+			upper = true
+		case r >= '0' && r <= '9':
+			digit = true
+		case r < unicode.MaxASCII:
+			symbol = true
+		default:
+			other = true
+		}
+	}
+	pool := 0
+	for _, class := range []struct {
+		present bool
+		size    int
+	}{{lower, 26}, {upper, 26}, {digit, 10}, {symbol, 33}, {other, 100}} {
+		if class.present {
+			pool += class.size
+		}
+	}
+	return pool
+}
+
+// patternEntropy returns the entropy of token if it matches a pattern.
+func patternEntropy(token []rune, inputs map[string]bool) (float64, bool) {
+	bits, matched := math.Inf(1), false
+	consider := func(b float64) {
+		if b < bits {
+			bits, matched = b, true
+		}
+	}
+	if b, ok := dictionaryEntropy(token, inputs); ok {
+		consider(b)
+	}
+	if repeated(token) {
+		consider(math.Log2(float64(bruteforcePool(token[:1]))) + math.Log2(float64(len(token))))
+	}
+	if b, ok := sequenceEntropy(token); ok {
+		consider(b)
+	}
+	if year(token) {
+		consider(math.Log2(200))
+	}
+	return bits, matched
+}
+
+// dictionaryEntropy matches token against the dictionaries, ignoring case
+// and leet substitutions, each of which adds entropy.
+func dictionaryEntropy(token []rune, inputs map[string]bool) (float64, bool) {
+	lower := strings.ToLower(string(token))
+	unleet, substitutions := lower, 0
+	if strings.ContainsFunc(lower, func(r rune) bool { _, ok := leetSubstitutions[r]; return ok }) {
+		unleet = strings.Map(func(r rune) rune {
+			if s, ok := leetSubstitutions[r]; ok {
+				substitutions++
+				return s
+			}
+			return r
+		}, lower)
+	}
+	bits, matched := math.Inf(1), false
+	for i, word := range []string{lower, unleet} {
+		extra := caseEntropy(token)
+		if i == 1 {
+			if substitutions == 0 {
+				break
+			}
+			extra += float64(substitutions)
+		}
+		if rank, ok := commonPasswordRanks()[word]; ok {
+			bits, matched = min(bits, math.Log2(float64(rank))+extra), true
+		}
+		if inputs[word] {
+			bits, matched = min(bits, 1+extra), true
+		}
+		if dicewareWordSet()[word] {
+			bits, matched = min(bits, math.Log2(float64(len(dicewareWords())))+extra), true
+		}
+	}
+	return bits, matched
+}
+
+// caseEntropy is the entropy added by upper-case letters: one bit for a
+// capitalized or all upper-case word, one per letter otherwise.
+func caseEntropy(token []rune) float64 {
+	upper, letters := 0, 0
+	for _, r := range token {
+		if unicode.IsLetter(r) {
+			letters++
+			if unicode.IsUpper(r) {
+				upper++
+			}
+		}
+	}
+	switch {
+	case upper == 0:
+		return 0
+	case upper == letters || (upper == 1 && unicode.IsUpper(token[0])):
+		return 1
+	default:
+		return float64(upper)
+	}
+}
+
+// repeated reports whether token is one character repeated.
+func repeated(token []rune) bool {
+	for _, r := range token[1:] {
+		if r != token[0] {
+			return false
+		}
+	}
+	return true
+}
+
+// sequenceEntropy matches token as a run of one of the sequenceAlphabets.
+func sequenceEntropy(token []rune) (float64, bool) {
+	lower := strings.ToLower(string(token))
+	for _, alphabet := range sequenceAlphabets {
+		for direction, candidate := range []string{alphabet, reverse(alphabet)} {
+			if strings.Contains(candidate, lower) {
+				bits := math.Log2(float64(len(alphabet))) + math.Log2(float64(len(token)))
+				return bits + float64(direction) + caseEntropy(token), true
+			}
+		}
+	}
+	return 0, false
+}
+
+// year reports whether token is a year from 1900 to 2099.
+func year(token []rune) bool {
+	if len(token) != 4 {
+		return false
+	}
+	for _, r := range token {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	prefix := string(token[:2])
+	return prefix == "19" || prefix == "20"
+}
+
+func reverse(s string) string {
+	runes := []rune(s)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+	return string(runes)
+}
+
+func isSeparator(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
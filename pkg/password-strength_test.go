@@ -0,0 +1,50 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package teamvault_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	teamvault "github.com/Seibert-Data/teamvault-cli/v5/pkg"
+)
+
+var _ = Describe("EstimatePasswordStrength", func() {
+	DescribeTable("scores",
+		func(password string, score int) {
+			Expect(teamvault.EstimatePasswordStrength(password).Score).To(Equal(score))
+		},
+		Entry("empty", "", 0),
+		Entry("common password", "password", 0),
+		Entry("common password with leet substitutions", "P@ssw0rd", 0),
+		Entry("repeat", "aaaaaaaaaaaa", 0),
+		Entry("keyboard row", "qwertyuiop", 0),
+		Entry("sequence", "abcdefgh", 0),
+		Entry("common password with year", "Sunshine2024!", 1),
+		Entry("short random", "x7#", 1),
+		Entry("random", "hT9#vQ2!mZ", 4),
+		Entry("diceware passphrase", "correct-horse-battery-staple", 4),
+		Entry("generated password", "r8N!c2pW@x4Lq#Zt6vB$m9Hy", 4),
+	)
+
+	It("is weaker when the password contains user inputs", func() {
+		without := teamvault.EstimatePasswordStrength("payment-db-7")
+		with := teamvault.EstimatePasswordStrength("payment-db-7", "payment-db", "payment")
+		Expect(with.Entropy).To(BeNumerically("<", without.Entropy))
+	})
+
+	It("never exceeds random characters", func() {
+		strength := teamvault.EstimatePasswordStrength("zq")
+		Expect(strength.Entropy).To(BeNumerically("~", 2*4.7, 0.01))
+	})
+
+	It("handles very long passwords", func() {
+		password := make([]byte, 10000)
+		for i := range password {
+			password[i] = byte('a' + i*7%26)
+		}
+		Expect(teamvault.EstimatePasswordStrength(string(password)).Score).To(Equal(4))
+	})
+})
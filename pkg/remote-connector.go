@@ -10,6 +10,7 @@ import (
 	"encoding/json"
 	stderrors "errors"
 	"fmt"
+	"iter"
 	"net/http"
 	"net/url"
 	"strings"
@@ -44,6 +45,25 @@ func (u Url) Normalize() Url {
 	return Url(strings.TrimRight(strings.TrimSpace(string(u)), "/"))
 }
 
+// jsonText decodes a JSON string or number (older TeamVault versions send
+// some choice fields as numbers) into text; null decodes to "".
+type jsonText string
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (t *jsonText) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err == nil {
+		*t = jsonText(str)
+		return nil
+	}
+	var num json.Number
+	if err := json.Unmarshal(data, &num); err != nil {
+		return err
+	}
+	*t = jsonText(num.String())
+	return nil
+}
+
 // User represents a TeamVault username.
 type User string
 
@@ -167,6 +187,31 @@ func (r *remoteConnector) CurrentRevision(ctx context.Context, key Key) (Current
 	return response.CurrentRevision, nil
 }
 
+func (r *remoteConnector) Metadata(ctx context.Context, key Key) (SecretMetadata, error) {
+	var response struct {
+		Name            string          `json:"name"`
+		Username        User            `json:"username"`
+		Url             Url             `json:"url"`
+		Description     string          `json:"description"`
+		ContentType     jsonText        `json:"content_type"`
+		CurrentRevision CurrentRevision `json:"current_revision"`
+		LastChanged     time.DateTime   `json:"last_changed"`
+	}
+	if err := r.call(ctx, fmt.Sprintf("%s/api/secrets/%s/", r.url.String(), key.String()), nil, &response, r.createHeader()); err != nil {
+		return SecretMetadata{}, err
+	}
+	return SecretMetadata{
+		Key:             key,
+		Name:            response.Name,
+		Username:        response.Username.String(),
+		Url:             response.Url,
+		Description:     response.Description,
+		ContentType:     ContentType(response.ContentType),
+		CurrentRevision: response.CurrentRevision,
+		LastModified:    response.LastChanged.Time(),
+	}, nil
+}
+
 func (r *remoteConnector) File(ctx context.Context, key Key) (File, error) {
 	rev, err := r.CurrentRevision(ctx, key)
 	if err != nil {
@@ -196,56 +241,19 @@ func (r *remoteConnector) createHeader() http.Header {
 	return httpHeader
 }
 
+// maxSearchResults caps Search, which buffers all results; use SearchSeq to
+// stream without a cap.
 const maxSearchResults = 1000
 
 func (r *remoteConnector) Search(ctx context.Context, search string) ([]SearchResult, error) {
 	var result []SearchResult
-	baseParsed, err := url.Parse(r.url.String())
-	if err != nil {
-		return nil, errors.Wrapf(ctx, err, "parse base url failed")
-	}
-	nextURL := fmt.Sprintf("%s/api/secrets/", r.url.String())
-	values := url.Values{}
-	values.Add("search", search)
-	isFirstPage := true
-
-	for len(result) < maxSearchResults {
-		var response struct {
-			Next    *string `json:"next"`
-			Results []struct {
-				Hashid   string `json:"hashid"`
-				Name     string `json:"name"`
-				Username string `json:"username"`
-				Url      Url    `json:"url"`
-			} `json:"results"`
-		}
-
-		var callErr error
-		if isFirstPage {
-			callErr = r.call(ctx, nextURL, values, &response, r.createHeader())
-		} else {
-			// Subsequent pages: the next URL already carries the query string.
-			callErr = r.call(ctx, nextURL, nil, &response, r.createHeader())
-		}
-		if callErr != nil {
-			return nil, errors.Wrapf(ctx, callErr, "search call failed")
-		}
-		isFirstPage = false
-
-		for _, re := range response.Results {
-			result = append(result, SearchResult{
-				Key:      Key(re.Hashid),
-				Name:     re.Name,
-				Username: re.Username,
-				Url:      re.Url,
-			})
-		}
-
-		if response.Next == nil || *response.Next == "" {
-			break
+	// One result past the cap tells whether more matches exist.
+	for re, err := range r.SearchSeq(ctx, SearchQuery{Name: search, Limit: maxSearchResults + 1}) {
+		if err != nil {
+			return nil, err
 		}
 		// Warn (don't silently drop) when the safety cap is reached while more
-		// pages remain, so the caller knows the result set is incomplete.
+		// results remain, so the caller knows the result set is incomplete.
 		if len(result) >= maxSearchResults {
 			glog.Warningf(
 				"teamvault search: result cap (%d) reached; more matches exist but were not fetched",
@@ -253,28 +261,118 @@ func (r *remoteConnector) Search(ctx context.Context, search string) ([]SearchRe
 			)
 			break
 		}
-		// Same-host pagination only: the Basic-auth header is attached to every
-		// request, so following a tampered `next` to a different host would leak
-		// the TeamVault credentials. A relative next (no host) is same-origin by
-		// definition and is resolved against the base; an absolute next must match
-		// the configured host (host compared, not a raw prefix, so an http/https
-		// difference behind a proxy doesn't break legitimate pagination).
-		nextParsed, parseErr := url.Parse(*response.Next)
-		if parseErr != nil {
-			return nil, errors.Wrapf(ctx, parseErr, "parse next url %q failed", *response.Next)
+		result = append(result, re)
+	}
+	return result, nil
+}
+
+// SearchSeq streams search results page by page. The next page is only
+// requested once the consumer has taken every result of the current one.
+func (r *remoteConnector) SearchSeq(
+	ctx context.Context,
+	query SearchQuery,
+) iter.Seq2[SearchResult, error] {
+	return func(yield func(SearchResult, error) bool) {
+		baseParsed, err := url.Parse(r.url.String())
+		if err != nil {
+			yield(SearchResult{}, errors.Wrapf(ctx, err, "parse base url failed"))
+			return
 		}
-		if nextParsed.Host != "" && nextParsed.Host != baseParsed.Host {
-			return nil, errors.Errorf(
-				ctx,
-				"refusing to follow search pagination to a different host %q (expected %q)",
-				nextParsed.Host,
-				baseParsed.Host,
-			)
+		nextURL := fmt.Sprintf("%s/api/secrets/", r.url.String())
+		values := url.Values{}
+		values.Add("search", query.Name)
+		if query.ContentType != "" {
+			values.Add("content_type", query.ContentType)
+		}
+		if query.Status != "" {
+			values.Add("status", query.Status)
+		}
+		if query.Description != "" {
+			values.Add("description", query.Description)
+		}
+		isFirstPage := true
+		count := 0
+
+		for {
+			var response struct {
+				Next    *string `json:"next"`
+				Results []struct {
+					Hashid      string        `json:"hashid"`
+					Name        string        `json:"name"`
+					Username    string        `json:"username"`
+					Url         Url           `json:"url"`
+					ContentType jsonText      `json:"content_type"`
+					Description string        `json:"description"`
+					Status      jsonText      `json:"status"`
+					LastChanged time.DateTime `json:"last_changed"`
+				} `json:"results"`
+			}
+
+			var callErr error
+			if isFirstPage {
+				callErr = r.call(ctx, nextURL, values, &response, r.createHeader())
+			} else {
+				// Subsequent pages: the next URL already carries the query string.
+				callErr = r.call(ctx, nextURL, nil, &response, r.createHeader())
+			}
+			if callErr != nil {
+				yield(SearchResult{}, errors.Wrapf(ctx, callErr, "search call failed"))
+				return
+			}
+			isFirstPage = false
+
+			for _, re := range response.Results {
+				result := SearchResult{
+					Key:          Key(re.Hashid),
+					Name:         re.Name,
+					Username:     re.Username,
+					Url:          re.Url,
+					ContentType:  string(re.ContentType),
+					Description:  re.Description,
+					Status:       string(re.Status),
+					LastModified: re.LastChanged.Time(),
+				}
+				if !query.matches(result) {
+					continue
+				}
+				if !yield(result, nil) {
+					return
+				}
+				count++
+				if query.Limit > 0 && count >= query.Limit {
+					return
+				}
+			}
+
+			if response.Next == nil || *response.Next == "" {
+				return
+			}
+			// Same-host pagination only: the Basic-auth header is attached to every
+			// request, so following a tampered `next` to a different host would leak
+			// the TeamVault credentials. A relative next (no host) is same-origin by
+			// definition and is resolved against the base; an absolute next must match
+			// the configured host (host compared, not a raw prefix, so an http/https
+			// difference behind a proxy doesn't break legitimate pagination).
+			nextParsed, parseErr := url.Parse(*response.Next)
+			if parseErr != nil {
+				yield(
+					SearchResult{},
+					errors.Wrapf(ctx, parseErr, "parse next url %q failed", *response.Next),
+				)
+				return
+			}
+			if nextParsed.Host != "" && nextParsed.Host != baseParsed.Host {
+				yield(SearchResult{}, errors.Errorf(
+					ctx,
+					"refusing to follow search pagination to a different host %q (expected %q)",
+					nextParsed.Host,
+					baseParsed.Host,
+				))
+				return
+			}
+			nextURL = baseParsed.ResolveReference(nextParsed).String()
 		}
-		nextURL = baseParsed.ResolveReference(nextParsed).String()
 	}
-
-	return result, nil
 }
 
 func (r *remoteConnector) call(
@@ -8,6 +8,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"time"
 
 	libhttp "github.com/bborbe/http"
 	libtime "github.com/bborbe/time"
@@ -245,6 +246,44 @@ var _ = Describe("RemoteConnector", func() {
 			Expect(result.String()).To(Equal("http://my.example.com"))
 		})
 	})
+	Context("Metadata", func() {
+		var result teamvault.SecretMetadata
+		JustBeforeEach(func() {
+			result, err = teamvault.ReadMetadata(ctx, remoteConnector, key)
+		})
+		BeforeEach(func() {
+			server.RouteToHandler(
+				http.MethodGet,
+				"/api/secrets/key123/",
+				func(resp http.ResponseWriter, req *http.Request) {
+					resp.WriteHeader(http.StatusOK)
+					fmt.Fprintf(
+						resp,
+						`{"name":"prod db","username":"admin","url":"https://db.example.com",`+
+							`"description":"primary","content_type":"file",`+
+							`"current_revision":"https://vault.example.com/api/secret-revisions/rev1/",`+
+							`"last_changed":"2026-03-01T12:00:00Z"}`,
+					)
+				},
+			)
+		})
+		It("returns no error", func() {
+			Expect(err).To(BeNil())
+		})
+		It("returns all fields from a single request", func() {
+			Expect(result.Key).To(Equal(key))
+			Expect(result.Name).To(Equal("prod db"))
+			Expect(result.Username).To(Equal("admin"))
+			Expect(result.Url.String()).To(Equal("https://db.example.com"))
+			Expect(result.Description).To(Equal("primary"))
+			Expect(result.ContentType).To(Equal(teamvault.ContentTypeFile))
+			Expect(result.CurrentRevision.String()).
+				To(Equal("https://vault.example.com/api/secret-revisions/rev1/"))
+			Expect(result.LastModified.UTC()).
+				To(Equal(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)))
+			Expect(server.ReceivedRequests()).To(HaveLen(1))
+		})
+	})
 	Context("Search", func() {
 		var result []teamvault.SearchResult
 		JustBeforeEach(func() {
@@ -453,4 +492,138 @@ var _ = Describe("RemoteConnector", func() {
 			Expect(result[1].Name).To(Equal("OtherSecret"))
 		})
 	})
+
+	Context("SearchSeq", func() {
+		var pageRequests int
+		BeforeEach(func() {
+			pageRequests = 0
+			server.RouteToHandler(
+				http.MethodGet,
+				"/api/secrets/",
+				func(resp http.ResponseWriter, req *http.Request) {
+					pageRequests++
+					resp.WriteHeader(http.StatusOK)
+					if req.URL.Query().Get("page") == "2" {
+						fmt.Fprint(
+							resp,
+							`{"next":null,"results":[{"hashid":"key3","name":"three"}]}`,
+						)
+						return
+					}
+					fmt.Fprint(
+						resp,
+						`{"next":"/api/secrets/?search=searchString&page=2","results":[{"hashid":"key1","name":"one"},{"hashid":"key2","name":"two"}]}`,
+					)
+				},
+			)
+		})
+		collect := func(query teamvault.SearchQuery) []teamvault.Key {
+			var keys []teamvault.Key
+			for result, err := range teamvault.SearchSeq(ctx, remoteConnector, query) {
+				Expect(err).To(BeNil())
+				keys = append(keys, result.Key)
+			}
+			return keys
+		}
+		It("is implemented by the remote connector", func() {
+			_, ok := remoteConnector.(teamvault.SearchIterator)
+			Expect(ok).To(BeTrue())
+		})
+		It("yields the results of all pages without a limit", func() {
+			Expect(collect(teamvault.SearchQuery{Name: "searchString"})).
+				To(Equal([]teamvault.Key{"key1", "key2", "key3"}))
+			Expect(pageRequests).To(Equal(2))
+		})
+		It("does not fetch the next page once the limit is reached", func() {
+			Expect(collect(teamvault.SearchQuery{Name: "searchString", Limit: 2})).
+				To(Equal([]teamvault.Key{"key1", "key2"}))
+			Expect(pageRequests).To(Equal(1))
+		})
+		It("stops fetching when the consumer stops", func() {
+			for range teamvault.SearchSeq(ctx, remoteConnector, teamvault.SearchQuery{Name: "searchString"}) {
+				break
+			}
+			Expect(pageRequests).To(Equal(1))
+		})
+		It("is forwarded by the cache connector", func() {
+			Expect(
+				teamvault.NewCacheConnector(remoteConnector).(teamvault.SearchIterator),
+			).NotTo(BeNil())
+			var keys []teamvault.Key
+			for result, err := range teamvault.SearchSeq(
+				ctx,
+				teamvault.NewCacheConnector(remoteConnector),
+				teamvault.SearchQuery{Name: "searchString", Limit: 1},
+			) {
+				Expect(err).To(BeNil())
+				keys = append(keys, result.Key)
+			}
+			Expect(keys).To(Equal([]teamvault.Key{"key1"}))
+			Expect(pageRequests).To(Equal(1))
+		})
+		It("sends the filters and checks them on every result", func() {
+			server.RouteToHandler(
+				http.MethodGet,
+				"/api/secrets/",
+				func(resp http.ResponseWriter, req *http.Request) {
+					Expect(req.URL.Query().Get("content_type")).To(Equal("file"))
+					Expect(req.URL.Query().Get("status")).To(Equal("ok"))
+					Expect(req.URL.Query().Get("description")).To(Equal("cert"))
+					resp.WriteHeader(http.StatusOK)
+					fmt.Fprint(
+						resp,
+						`{"next":null,"results":[`+
+							`{"hashid":"key1","name":"one","content_type":"file","status":"ok","description":"TLS cert","last_changed":"2026-03-01T12:00:00.123456Z"},`+
+							`{"hashid":"key2","name":"two","content_type":"password","status":"ok","description":"cert password"}]}`,
+					)
+				},
+			)
+			var results []teamvault.SearchResult
+			for result, err := range teamvault.SearchSeq(ctx, remoteConnector, teamvault.SearchQuery{
+				Name:        "x",
+				ContentType: "file",
+				Status:      "ok",
+				Description: "cert",
+			}) {
+				Expect(err).To(BeNil())
+				results = append(results, result)
+			}
+			Expect(results).To(HaveLen(1))
+			Expect(results[0].Key).To(Equal(teamvault.Key("key1")))
+			Expect(results[0].ContentType).To(Equal("file"))
+			Expect(results[0].Status).To(Equal("ok"))
+			Expect(results[0].Description).To(Equal("TLS cert"))
+			Expect(results[0].LastModified.UTC()).
+				To(Equal(time.Date(2026, 3, 1, 12, 0, 0, 123456000, time.UTC)))
+		})
+		It("accepts numeric choice fields", func() {
+			server.RouteToHandler(
+				http.MethodGet,
+				"/api/secrets/",
+				ghttp.RespondWith(
+					http.StatusOK,
+					`{"next":null,"results":[{"hashid":"key1","content_type":1,"status":2,"last_changed":null}]}`,
+				),
+			)
+			results, err := remoteConnector.Search(ctx, "x")
+			Expect(err).To(BeNil())
+			Expect(results).To(HaveLen(1))
+			Expect(results[0].ContentType).To(Equal("1"))
+			Expect(results[0].Status).To(Equal("2"))
+			Expect(results[0].LastModified.IsZero()).To(BeTrue())
+		})
+		It("yields the error of a failed page", func() {
+			server.RouteToHandler(
+				http.MethodGet,
+				"/api/secrets/",
+				ghttp.RespondWith(http.StatusNotFound, ""),
+			)
+			var errs []error
+			for _, err := range teamvault.SearchSeq(ctx, remoteConnector, teamvault.SearchQuery{Name: "x"}) {
+				errs = append(errs, err)
+			}
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0]).NotTo(BeNil())
+		})
+	})
 })
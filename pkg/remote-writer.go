@@ -11,8 +11,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/bborbe/errors"
+	libhttp "github.com/bborbe/http"
 	"github.com/bborbe/time"
 	"github.com/golang/glog"
 )
@@ -70,7 +72,7 @@ func (w *remoteWriter) Create(ctx context.Context, secret CreateSecret) (Key, Ap
 	var response struct {
 		ApiUrl ApiUrl `json:"api_url"`
 	}
-	if err := w.call(ctx, http.MethodPost, fmt.Sprintf("%s/api/secrets/", w.url.String()), body, &response); err != nil {
+	if err := w.call(ctx, http.MethodPost, fmt.Sprintf("%s/api/secrets/", w.url.String()), body, &response, nil); err != nil {
 		return "", "", err
 	}
 	key, err := response.ApiUrl.Key()
@@ -85,6 +87,11 @@ func (w *remoteWriter) Update(
 	key Key,
 	secret UpdateSecret,
 ) (Key, ApiUrl, error) {
+	// Accept pasted web UI and API URLs of this vault as well as bare keys.
+	key, err := NewKeyResolver(w.url).ResolveKey(ctx, key.String())
+	if err != nil {
+		return "", "", errors.Wrapf(ctx, err, "resolve key failed")
+	}
 	body := make(map[string]any)
 
 	if secret.Name != nil {
@@ -115,26 +122,97 @@ func (w *remoteWriter) Update(
 		body["secret_data"] = secretData
 	}
 
+	secretUrl := fmt.Sprintf("%s/api/secrets/%s/", w.url.String(), key.String())
+	conditions, err := w.checkPreconditions(ctx, key, secretUrl, secret)
+	if err != nil {
+		return "", "", err
+	}
+
 	var response struct {
 		ApiUrl ApiUrl `json:"api_url"`
 	}
-	if err := w.call(ctx, http.MethodPatch, fmt.Sprintf("%s/api/secrets/%s/", w.url.String(), key.String()), body, &response); err != nil {
+	if err := w.call(ctx, http.MethodPatch, secretUrl, body, &response, conditions); err != nil {
 		return "", "", err
 	}
 	return key, response.ApiUrl, nil
 }
 
+// checkPreconditions verifies ExpectedRevision and UnchangedSince against
+// the secret's current state and returns the matching If-Match and
+// If-Unmodified-Since headers. TeamVault ignores these headers, so the check
+// happens here; servers honoring them close the window until the PATCH.
+func (w *remoteWriter) checkPreconditions(
+	ctx context.Context,
+	key Key,
+	secretUrl string,
+	secret UpdateSecret,
+) (http.Header, error) {
+	conditions := make(http.Header)
+	if secret.ExpectedRevision == "" && secret.UnchangedSince.IsZero() {
+		return conditions, nil
+	}
+	var response struct {
+		CurrentRevision CurrentRevision `json:"current_revision"`
+		LastChanged     time.DateTime   `json:"last_changed"`
+	}
+	if err := w.call(ctx, http.MethodGet, secretUrl, nil, &response, nil); err != nil {
+		return nil, err
+	}
+	if secret.ExpectedRevision != "" {
+		if current := response.CurrentRevision.ID(); current != secret.ExpectedRevision.ID() {
+			return nil, errors.Wrapf(
+				ctx,
+				ErrRevisionConflict,
+				"secret %s is at revision %s, expected %s",
+				key,
+				current,
+				secret.ExpectedRevision.ID(),
+			)
+		}
+		conditions.Set("If-Match", strconv.Quote(secret.ExpectedRevision.ID()))
+	}
+	if !secret.UnchangedSince.IsZero() {
+		lastChanged := response.LastChanged.Time()
+		if lastChanged.IsZero() {
+			return nil, errors.Errorf(ctx, "server did not report when secret %s last changed", key)
+		}
+		// Compare at the whole-second precision of If-Unmodified-Since, so a
+		// time printed by the revision command matches its own revision.
+		lastChanged = lastChanged.Truncate(time.Second.Duration())
+		if lastChanged.After(secret.UnchangedSince) {
+			return nil, errors.Wrapf(
+				ctx,
+				ErrRevisionConflict,
+				"secret %s was changed at %s, after %s",
+				key,
+				lastChanged.UTC().Format(http.TimeFormat),
+				secret.UnchangedSince.UTC().Format(http.TimeFormat),
+			)
+		}
+		conditions.Set("If-Unmodified-Since", secret.UnchangedSince.UTC().Format(http.TimeFormat))
+	}
+	// A failed precondition stays failed; retrying only delays the conflict.
+	conditions.Set(libhttp.PreventRetryHeaderName, "true")
+	return conditions, nil
+}
+
 func (w *remoteWriter) GeneratePassword(ctx context.Context) (Password, error) {
 	var response struct {
 		Password Password `json:"password"`
 	}
-	if err := w.call(ctx, http.MethodPost, fmt.Sprintf("%s/api/generate_password/", w.url.String()), nil, &response); err != nil {
+	if err := w.call(ctx, http.MethodPost, fmt.Sprintf("%s/api/generate_password/", w.url.String()), nil, &response, nil); err != nil {
 		return "", err
 	}
 	return response.Password, nil
 }
 
-func (w *remoteWriter) call(ctx context.Context, method, url string, body any, response any) error {
+func (w *remoteWriter) call(
+	ctx context.Context,
+	method, url string,
+	body any,
+	response any,
+	header http.Header,
+) error {
 	glog.V(4).Infof("rest %s to %s", method, url)
 	start := w.currentDateTime.Now()
 	defer glog.V(8).
@@ -159,6 +237,11 @@ func (w *remoteWriter) call(ctx context.Context, method, url string, body any, r
 			req.Header.Add(key, value)
 		}
 	}
+	for key, values := range header {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
 
 	resp, err := w.httpClient.Do(
 		req,
@@ -178,6 +261,15 @@ func (w *remoteWriter) call(ctx context.Context, method, url string, body any, r
 				resp.StatusCode,
 			)
 		}
+		if resp.StatusCode == http.StatusPreconditionFailed {
+			return errors.Wrapf(
+				ctx,
+				ErrRevisionConflict,
+				"request to %s failed with status: %d",
+				url,
+				resp.StatusCode,
+			)
+		}
 		return errors.Errorf(ctx, "request to %s failed with status: %d", url, resp.StatusCode)
 	}
 
@@ -12,6 +12,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"time"
 
 	libhttp "github.com/bborbe/http"
 	libtime "github.com/bborbe/time"
@@ -239,6 +240,179 @@ var _ = Describe("RemoteWriter", func() {
 				).To(Equal(base64.StdEncoding.EncodeToString(fileContent)))
 			})
 		})
+
+		Context("with a web UI url as key", func() {
+			It("PATCHes the secret the url points at", func() {
+				server.RouteToHandler(
+					http.MethodPatch,
+					"/api/secrets/AbC123/",
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest(http.MethodPatch, "/api/secrets/AbC123/"),
+						ghttp.RespondWith(
+							http.StatusOK,
+							`{"api_url": "`+server.URL()+`/api/secrets/AbC123/"}`,
+						),
+					),
+				)
+
+				name := "renamed"
+				key, _, err := writer.Update(
+					ctx,
+					teamvault.Key(server.URL()+"/secrets/AbC123/"),
+					teamvault.UpdateSecret{Name: &name},
+				)
+
+				Expect(err).To(BeNil())
+				Expect(key).To(Equal(teamvault.Key("AbC123")))
+			})
+		})
+
+		Context("with a url of another vault as key", func() {
+			It("fails without a request", func() {
+				name := "renamed"
+				_, _, err := writer.Update(
+					ctx,
+					teamvault.Key("https://other.example.com/secrets/AbC123/"),
+					teamvault.UpdateSecret{Name: &name},
+				)
+
+				Expect(errors.Is(err, teamvault.ErrKeyHostMismatch)).To(BeTrue())
+				Expect(server.ReceivedRequests()).To(BeEmpty())
+			})
+		})
+
+		Context("with preconditions", func() {
+			var patchHeader http.Header
+			var lastChanged string
+
+			BeforeEach(func() {
+				patchHeader = nil
+				lastChanged = "2026-03-01T12:00:00Z"
+				server.RouteToHandler(
+					http.MethodGet,
+					"/api/secrets/AbC123/",
+					func(resp http.ResponseWriter, req *http.Request) {
+						resp.WriteHeader(http.StatusOK)
+						//nolint:errcheck
+						fmt.Fprintf(
+							resp,
+							`{"current_revision": "%s/api/secret-revisions/rev2/", "last_changed": "%s"}`,
+							server.URL(),
+							lastChanged,
+						)
+					},
+				)
+				server.RouteToHandler(
+					http.MethodPatch,
+					"/api/secrets/AbC123/",
+					func(resp http.ResponseWriter, req *http.Request) {
+						patchHeader = req.Header
+						resp.WriteHeader(http.StatusOK)
+						//nolint:errcheck
+						fmt.Fprintf(resp, `{"api_url": "%s/api/secrets/AbC123/"}`, server.URL())
+					},
+				)
+			})
+
+			It("patches when the expected revision is current", func() {
+				newPw := teamvault.Password("new")
+				_, _, err := writer.Update(ctx, teamvault.Key("AbC123"), teamvault.UpdateSecret{
+					Password:         &newPw,
+					ExpectedRevision: "rev2",
+				})
+
+				Expect(err).To(BeNil())
+				Expect(patchHeader.Get("If-Match")).To(Equal(`"rev2"`))
+			})
+
+			It("accepts the revision as API url", func() {
+				newPw := teamvault.Password("new")
+				_, _, err := writer.Update(ctx, teamvault.Key("AbC123"), teamvault.UpdateSecret{
+					Password: &newPw,
+					ExpectedRevision: teamvault.CurrentRevision(
+						server.URL() + "/api/secret-revisions/rev2/",
+					),
+				})
+
+				Expect(err).To(BeNil())
+			})
+
+			It("fails with ErrRevisionConflict without patching when the revision moved", func() {
+				newPw := teamvault.Password("new")
+				_, _, err := writer.Update(ctx, teamvault.Key("AbC123"), teamvault.UpdateSecret{
+					Password:         &newPw,
+					ExpectedRevision: "rev1",
+				})
+
+				Expect(errors.Is(err, teamvault.ErrRevisionConflict)).To(BeTrue())
+				Expect(err.Error()).To(ContainSubstring("at revision rev2, expected rev1"))
+				Expect(patchHeader).To(BeNil())
+			})
+
+			It("patches when unchanged since the given time", func() {
+				since := time.Date(2026, 3, 1, 12, 30, 0, 0, time.UTC)
+				name := "renamed"
+				_, _, err := writer.Update(ctx, teamvault.Key("AbC123"), teamvault.UpdateSecret{
+					Name:           &name,
+					UnchangedSince: since,
+				})
+
+				Expect(err).To(BeNil())
+				Expect(patchHeader.Get("If-Unmodified-Since")).
+					To(Equal("Sun, 01 Mar 2026 12:30:00 GMT"))
+			})
+
+			It("ignores fractional seconds of the last change", func() {
+				lastChanged = "2026-03-01T12:00:00.750Z"
+				name := "renamed"
+				_, _, err := writer.Update(ctx, teamvault.Key("AbC123"), teamvault.UpdateSecret{
+					Name:           &name,
+					UnchangedSince: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+				})
+
+				Expect(err).To(BeNil())
+				Expect(patchHeader.Get("If-Unmodified-Since")).
+					To(Equal("Sun, 01 Mar 2026 12:00:00 GMT"))
+			})
+
+			It("fails with ErrRevisionConflict when changed after the given time", func() {
+				name := "renamed"
+				_, _, err := writer.Update(ctx, teamvault.Key("AbC123"), teamvault.UpdateSecret{
+					Name:           &name,
+					UnchangedSince: time.Date(2026, 3, 1, 11, 0, 0, 0, time.UTC),
+				})
+
+				Expect(errors.Is(err, teamvault.ErrRevisionConflict)).To(BeTrue())
+				Expect(patchHeader).To(BeNil())
+			})
+
+			It("maps 412 Precondition Failed to ErrRevisionConflict", func() {
+				server.RouteToHandler(
+					http.MethodPatch,
+					"/api/secrets/AbC123/",
+					ghttp.RespondWith(http.StatusPreconditionFailed, ""),
+				)
+				newPw := teamvault.Password("new")
+				_, _, err := writer.Update(ctx, teamvault.Key("AbC123"), teamvault.UpdateSecret{
+					Password:         &newPw,
+					ExpectedRevision: "rev2",
+				})
+
+				Expect(errors.Is(err, teamvault.ErrRevisionConflict)).To(BeTrue())
+			})
+
+			It("does not read the secret without preconditions", func() {
+				name := "renamed"
+				_, _, err := writer.Update(
+					ctx,
+					teamvault.Key("AbC123"),
+					teamvault.UpdateSecret{Name: &name},
+				)
+
+				Expect(err).To(BeNil())
+				Expect(server.ReceivedRequests()).To(HaveLen(1))
+			})
+		})
 	})
 
 	Describe("GeneratePassword", func() {
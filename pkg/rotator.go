@@ -0,0 +1,327 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package teamvault
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/bborbe/errors"
+	libtime "github.com/bborbe/time"
+)
+
+// RotationStatus is the outcome of a rotation.
+type RotationStatus string
+
+const (
+	// RotationRotated means the hook applied the new password and TeamVault
+	// stores it.
+	RotationRotated RotationStatus = "rotated"
+	// RotationFailed means the rotation failed before the hook ran; nothing
+	// changed.
+	RotationFailed RotationStatus = "failed"
+	// RotationHookFailed means the hook failed; TeamVault was not updated.
+	RotationHookFailed RotationStatus = "hook failed"
+	// RotationUpdateFailed means the hook applied the new password but
+	// TeamVault could not store it and no rollback hook was given.
+	RotationUpdateFailed RotationStatus = "update failed"
+	// RotationRolledBack means TeamVault could not store the new password
+	// and the rollback hook restored the old one.
+	RotationRolledBack RotationStatus = "rolled back"
+	// RotationRollbackFailed means TeamVault could not store the new
+	// password and the rollback hook failed too.
+	RotationRollbackFailed RotationStatus = "rollback failed"
+)
+
+// RotationHookInput is what a rotation hook receives as JSON on stdin.
+type RotationHookInput struct {
+	Key         Key      `json:"key"`
+	Name        string   `json:"name"`
+	Username    string   `json:"username"`
+	Url         string   `json:"url"`
+	OldPassword Password `json:"old_password"`
+	NewPassword Password `json:"new_password"`
+}
+
+// RotationHook applies a password change to the system using the secret,
+// or reverts it.
+//
+//counterfeiter:generate -o mocks/rotation_hook.go --fake-name RotationHook . RotationHook
+type RotationHook interface {
+	Run(ctx context.Context, input RotationHookInput) error
+}
+
+// NewCommandRotationHook creates a RotationHook running the program args[0]
+// with the arguments args[1:], without a shell, and the input as JSON on
+// stdin. Passwords are never passed as arguments or environment. The
+// command's stdout and stderr go to output.
+func NewCommandRotationHook(args []string, output io.Writer) RotationHook {
+	return &commandRotationHook{
+		args:   args,
+		output: output,
+	}
+}
+
+type commandRotationHook struct {
+	args   []string
+	output io.Writer
+}
+
+func (h *commandRotationHook) Run(ctx context.Context, input RotationHookInput) error {
+	args := h.args
+	if len(args) == 0 || args[0] == "" {
+		return errors.New(ctx, "hook command is empty")
+	}
+	stdin, err := json.Marshal(input)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "marshal hook input failed")
+	}
+	// #nosec G204 -- runs the hook the user configured
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stdin = bytes.NewReader(stdin)
+	cmd.Stdout = h.output
+	cmd.Stderr = h.output
+	if err := cmd.Run(); err != nil {
+		return errors.Wrapf(ctx, err, "run %s failed", args[0])
+	}
+	return nil
+}
+
+// RotationJournalEntry records one rotation. It holds no secret values.
+type RotationJournalEntry struct {
+	Time             time.Time       `json:"time"`
+	Key              Key             `json:"key"`
+	Name             string          `json:"name,omitempty"`
+	Status           RotationStatus  `json:"status"`
+	PreviousRevision CurrentRevision `json:"previous_revision,omitempty"`
+	Error            string          `json:"error,omitempty"`
+}
+
+// RotationJournalPath is a JSON Lines file rotations are appended to.
+type RotationJournalPath string
+
+// ProfileRotationJournalPath returns the journal belonging to a config file:
+// ~/.teamvault.json uses ~/.teamvault.rotations.jsonl.
+func ProfileRotationJournalPath(configPath TeamvaultConfigPath) (RotationJournalPath, error) {
+	path, err := configPath.NormalizePath()
+	if err != nil {
+		return "", err
+	}
+	base := strings.TrimSuffix(path.String(), filepath.Ext(path.String()))
+	return RotationJournalPath(base + ".rotations.jsonl"), nil
+}
+
+// String returns the path.
+func (p RotationJournalPath) String() string {
+	return string(p)
+}
+
+// Append adds an entry to the journal, creating the file if needed.
+func (p RotationJournalPath) Append(ctx context.Context, entry RotationJournalEntry) error {
+	content, err := json.Marshal(entry)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "marshal journal entry failed")
+	}
+	file, err := os.OpenFile(p.String(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "open journal %s failed", p)
+	}
+	defer file.Close()
+	if _, err := file.Write(append(content, '\n')); err != nil {
+		return errors.Wrapf(ctx, err, "write journal %s failed", p)
+	}
+	return nil
+}
+
+// Read returns all entries, oldest first. A missing file has none.
+func (p RotationJournalPath) Read(ctx context.Context) ([]RotationJournalEntry, error) {
+	file, err := os.Open(p.String())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "open journal %s failed", p)
+	}
+	defer file.Close()
+	var entries []RotationJournalEntry
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var entry RotationJournalEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, errors.Wrapf(ctx, err, "parse journal %s line %d failed", p, line)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrapf(ctx, err, "read journal %s failed", p)
+	}
+	return entries, nil
+}
+
+// RotateOptions control a rotation.
+type RotateOptions struct {
+	// Generate returns the new password, e.g. Writer.GeneratePassword.
+	Generate func(ctx context.Context) (Password, error)
+	// Hook applies the new password to the target system.
+	Hook RotationHook
+	// Rollback, if set, restores the old password on the target system
+	// when TeamVault cannot store the new one.
+	Rollback RotationHook
+	// Journal, if set, gets an entry for every rotation.
+	Journal RotationJournalPath
+}
+
+// RotationResult reports a rotation.
+type RotationResult struct {
+	Key    Key
+	Name   string
+	Status RotationStatus
+	// NewPassword is the generated password. After RotationUpdateFailed
+	// and RotationRollbackFailed the target system may use it although
+	// TeamVault does not store it.
+	NewPassword Password
+}
+
+// Rotator replaces a secret's password in TeamVault and on the system
+// using it.
+type Rotator interface {
+	// Rotate generates a new password, runs the hook and updates TeamVault
+	// only if the hook succeeded. It returns an error unless the status is
+	// RotationRotated.
+	Rotate(ctx context.Context, key Key, options RotateOptions) (RotationResult, error)
+}
+
+// NewRotator creates a Rotator. conn must implement MetadataReader.
+func NewRotator(
+	conn Connector,
+	writer Writer,
+	currentDateTime libtime.CurrentDateTimeGetter,
+) Rotator {
+	return &rotator{
+		conn:            conn,
+		writer:          writer,
+		currentDateTime: currentDateTime,
+	}
+}
+
+type rotator struct {
+	conn            Connector
+	writer          Writer
+	currentDateTime libtime.CurrentDateTimeGetter
+}
+
+func (r *rotator) Rotate(
+	ctx context.Context,
+	key Key,
+	options RotateOptions,
+) (RotationResult, error) {
+	result := RotationResult{Key: key}
+	metadata, rotateErr := r.rotate(ctx, &result, options)
+	if options.Journal != "" {
+		entry := RotationJournalEntry{
+			Time:             r.currentDateTime.Now().Time().UTC(),
+			Key:              key,
+			Name:             result.Name,
+			Status:           result.Status,
+			PreviousRevision: metadata.CurrentRevision,
+		}
+		if rotateErr != nil {
+			entry.Error = rotateErr.Error()
+		}
+		if err := options.Journal.Append(ctx, entry); err != nil {
+			if rotateErr != nil {
+				return result, rotateErr
+			}
+			return result, errors.Wrapf(ctx, err, "secret %s rotated but journal failed", key)
+		}
+	}
+	return result, rotateErr
+}
+
+// rotate sets result.Status and returns the metadata read before rotating.
+func (r *rotator) rotate(
+	ctx context.Context,
+	result *RotationResult,
+	options RotateOptions,
+) (SecretMetadata, error) {
+	result.Status = RotationFailed
+	metadata, err := ReadMetadata(ctx, r.conn, result.Key)
+	if err != nil {
+		return metadata, errors.Wrapf(ctx, err, "read metadata failed")
+	}
+	result.Name = metadata.Name
+	if metadata.ContentType != ContentTypePassword {
+		return metadata, errors.Errorf(
+			ctx,
+			"secret %s is a %s secret, only passwords can be rotated",
+			result.Key,
+			metadata.ContentType,
+		)
+	}
+	oldPassword, err := r.conn.Password(ctx, result.Key)
+	if err != nil {
+		return metadata, errors.Wrapf(ctx, err, "read password failed")
+	}
+	newPassword, err := options.Generate(ctx)
+	if err != nil {
+		return metadata, errors.Wrapf(ctx, err, "generate password failed")
+	}
+	result.NewPassword = newPassword
+	input := RotationHookInput{
+		Key:         result.Key,
+		Name:        metadata.Name,
+		Username:    metadata.Username,
+		Url:         metadata.Url.String(),
+		OldPassword: oldPassword,
+		NewPassword: newPassword,
+	}
+
+	if err := options.Hook.Run(ctx, input); err != nil {
+		result.Status = RotationHookFailed
+		return metadata, errors.Wrapf(ctx, err, "hook failed; %s not updated", result.Key)
+	}
+	// Guard against a concurrent change: storing over it would lose the
+	// password someone else just set.
+	_, _, updateErr := r.writer.Update(ctx, result.Key, UpdateSecret{
+		Password:         &newPassword,
+		ExpectedRevision: metadata.CurrentRevision,
+	})
+	if updateErr == nil {
+		result.Status = RotationRotated
+		return metadata, nil
+	}
+	if options.Rollback == nil {
+		result.Status = RotationUpdateFailed
+		return metadata, errors.Wrapf(
+			ctx,
+			updateErr,
+			"hook applied the new password but updating %s failed",
+			result.Key,
+		)
+	}
+	if err := options.Rollback.Run(ctx, input); err != nil {
+		result.Status = RotationRollbackFailed
+		return metadata, errors.Wrapf(
+			ctx,
+			updateErr,
+			"updating %s failed and rollback failed too (%v)",
+			result.Key,
+			err,
+		)
+	}
+	result.Status = RotationRolledBack
+	return metadata, errors.Wrapf(ctx, updateErr, "updating %s failed; rolled back", result.Key)
+}
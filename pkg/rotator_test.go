@@ -0,0 +1,187 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package teamvault_test
+
+import (
+	"bytes"
+	"context"
+	stderrors "errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	libtime "github.com/bborbe/time"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	teamvault "github.com/Seibert-Data/teamvault-cli/v5/pkg"
+	"github.com/Seibert-Data/teamvault-cli/v5/pkg/mocks"
+)
+
+var _ = Describe("Rotator", func() {
+	var ctx context.Context
+	var conn metadataConnector
+	var writer *mocks.Writer
+	var hook *mocks.RotationHook
+	var rollback *mocks.RotationHook
+	var journal teamvault.RotationJournalPath
+	var now time.Time
+	var rotator teamvault.Rotator
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		conn = metadataConnector{
+			Connector:      &mocks.Connector{},
+			MetadataReader: &mocks.MetadataReader{},
+		}
+		conn.MetadataReader.MetadataReturns(teamvault.SecretMetadata{
+			Key:             "AbC123",
+			Name:            "payment-db",
+			Username:        "payment",
+			Url:             "postgres://db",
+			ContentType:     teamvault.ContentTypePassword,
+			CurrentRevision: "https://vault/api/secret-revisions/rev1/",
+		}, nil)
+		conn.PasswordReturns("old-pw", nil)
+		writer = &mocks.Writer{}
+		hook = &mocks.RotationHook{}
+		rollback = &mocks.RotationHook{}
+		journal = teamvault.RotationJournalPath(
+			filepath.Join(GinkgoT().TempDir(), "rotations.jsonl"),
+		)
+		now = time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+		currentDateTime := libtime.NewCurrentDateTime()
+		currentDateTime.SetNow(libtime.DateTime(now))
+		rotator = teamvault.NewRotator(conn, writer, currentDateTime)
+	})
+
+	rotate := func() (teamvault.RotationResult, error) {
+		return rotator.Rotate(ctx, "AbC123", teamvault.RotateOptions{
+			Generate: func(ctx context.Context) (teamvault.Password, error) { return "new-pw", nil },
+			Hook:     hook,
+			Rollback: rollback,
+			Journal:  journal,
+		})
+	}
+
+	It("runs the hook, then updates guarded by the revision and journals it", func() {
+		result, err := rotate()
+
+		Expect(err).To(BeNil())
+		Expect(result.Status).To(Equal(teamvault.RotationRotated))
+		_, input := hook.RunArgsForCall(0)
+		Expect(input).To(Equal(teamvault.RotationHookInput{
+			Key:         "AbC123",
+			Name:        "payment-db",
+			Username:    "payment",
+			Url:         "postgres://db",
+			OldPassword: "old-pw",
+			NewPassword: "new-pw",
+		}))
+		_, key, update := writer.UpdateArgsForCall(0)
+		Expect(key).To(Equal(teamvault.Key("AbC123")))
+		Expect(*update.Password).To(Equal(teamvault.Password("new-pw")))
+		Expect(update.ExpectedRevision).
+			To(Equal(teamvault.CurrentRevision("https://vault/api/secret-revisions/rev1/")))
+		Expect(rollback.RunCallCount()).To(Equal(0))
+		Expect(journal.Read(ctx)).To(Equal([]teamvault.RotationJournalEntry{{
+			Time:             now,
+			Key:              "AbC123",
+			Name:             "payment-db",
+			Status:           teamvault.RotationRotated,
+			PreviousRevision: "https://vault/api/secret-revisions/rev1/",
+		}}))
+	})
+
+	It("does not update when the hook fails", func() {
+		hook.RunReturns(stderrors.New("connection refused"))
+
+		result, err := rotate()
+
+		Expect(err).NotTo(BeNil())
+		Expect(result.Status).To(Equal(teamvault.RotationHookFailed))
+		Expect(writer.UpdateCallCount()).To(Equal(0))
+		Expect(rollback.RunCallCount()).To(Equal(0))
+		entries, _ := journal.Read(ctx)
+		Expect(entries[0].Status).To(Equal(teamvault.RotationHookFailed))
+		Expect(entries[0].Error).To(ContainSubstring("connection refused"))
+	})
+
+	It("rolls back when the update fails", func() {
+		writer.UpdateReturns("", "", teamvault.ErrRevisionConflict)
+
+		result, err := rotate()
+
+		Expect(stderrors.Is(err, teamvault.ErrRevisionConflict)).To(BeTrue())
+		Expect(result.Status).To(Equal(teamvault.RotationRolledBack))
+		_, input := rollback.RunArgsForCall(0)
+		Expect(input.OldPassword).To(Equal(teamvault.Password("old-pw")))
+	})
+
+	It("reports the new password when the rollback fails too", func() {
+		writer.UpdateReturns("", "", stderrors.New("server error"))
+		rollback.RunReturns(stderrors.New("rollback broken"))
+
+		result, err := rotate()
+
+		Expect(err).NotTo(BeNil())
+		Expect(err.Error()).To(ContainSubstring("rollback broken"))
+		Expect(result.Status).To(Equal(teamvault.RotationRollbackFailed))
+		Expect(result.NewPassword).To(Equal(teamvault.Password("new-pw")))
+		entries, _ := journal.Read(ctx)
+		Expect(entries[0].Status).To(Equal(teamvault.RotationRollbackFailed))
+	})
+
+	It("rejects secrets that are not passwords", func() {
+		conn.MetadataReader.MetadataReturns(teamvault.SecretMetadata{
+			Key:         "AbC123",
+			ContentType: teamvault.ContentTypeFile,
+		}, nil)
+
+		result, err := rotate()
+
+		Expect(err).NotTo(BeNil())
+		Expect(result.Status).To(Equal(teamvault.RotationFailed))
+		Expect(hook.RunCallCount()).To(Equal(0))
+	})
+
+	Describe("NewCommandRotationHook", func() {
+		It("passes the input as JSON on stdin, not as arguments", func() {
+			dir := GinkgoT().TempDir()
+			stdinFile := filepath.Join(dir, "stdin file.json")
+			script := filepath.Join(dir, "hook.sh")
+			Expect(os.WriteFile(script, []byte("#!/bin/sh\ncat > \"$1\"\necho \"$2\"\n"), 0700)).
+				To(Succeed())
+			var output bytes.Buffer
+
+			err := teamvault.NewCommandRotationHook(
+				[]string{script, stdinFile, `it's "applied"`},
+				&output,
+			).Run(
+				ctx,
+				teamvault.RotationHookInput{Key: "AbC123", OldPassword: "old", NewPassword: "new"},
+			)
+
+			Expect(err).To(BeNil())
+			Expect(output.String()).To(Equal("it's \"applied\"\n"))
+			Expect(os.ReadFile(stdinFile)).To(MatchJSON(`{
+				"key": "AbC123", "name": "", "username": "", "url": "",
+				"old_password": "old", "new_password": "new"
+			}`))
+		})
+
+		It("returns an error if the command fails", func() {
+			err := teamvault.NewCommandRotationHook([]string{"false"}, &bytes.Buffer{}).
+				Run(ctx, teamvault.RotationHookInput{})
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("returns an error if the command is empty", func() {
+			err := teamvault.NewCommandRotationHook(nil, &bytes.Buffer{}).
+				Run(ctx, teamvault.RotationHookInput{})
+			Expect(err).NotTo(BeNil())
+		})
+	})
+})
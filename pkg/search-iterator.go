@@ -0,0 +1,82 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package teamvault
+
+import (
+	"context"
+	"iter"
+	"strings"
+)
+
+// SearchQuery describes a streamed secret search.
+type SearchQuery struct {
+	// Name is the search term matched against secret names.
+	Name string
+	// Limit caps the number of results; 0 means no cap.
+	Limit int
+	// ContentType, Status and Description filter the results when set.
+	// They are sent to the server as filter parameters and checked again
+	// on every result, so a server ignoring them still filters correctly.
+	// ContentType and Status must match as a whole, Description as a
+	// substring, all ignoring case.
+	ContentType string
+	Status      string
+	Description string
+}
+
+// matches reports whether result passes the query's filters.
+func (q SearchQuery) matches(result SearchResult) bool {
+	if q.ContentType != "" && !strings.EqualFold(result.ContentType, q.ContentType) {
+		return false
+	}
+	if q.Status != "" && !strings.EqualFold(result.Status, q.Status) {
+		return false
+	}
+	if q.Description != "" &&
+		!strings.Contains(strings.ToLower(result.Description), strings.ToLower(q.Description)) {
+		return false
+	}
+	return true
+}
+
+// SearchIterator is implemented by connectors that can stream search results
+// page by page. Iteration stops fetching pages as soon as the consumer stops
+// or Limit is reached. An error is yielded once, as the last element.
+type SearchIterator interface {
+	SearchSeq(ctx context.Context, query SearchQuery) iter.Seq2[SearchResult, error]
+}
+
+// SearchSeq streams the results of query from connector. Connectors
+// implementing SearchIterator stream; for others the result of Search is
+// replayed, truncated to Limit.
+func SearchSeq(
+	ctx context.Context,
+	connector Connector,
+	query SearchQuery,
+) iter.Seq2[SearchResult, error] {
+	if searchIterator, ok := connector.(SearchIterator); ok {
+		return searchIterator.SearchSeq(ctx, query)
+	}
+	return func(yield func(SearchResult, error) bool) {
+		results, err := connector.Search(ctx, query.Name)
+		if err != nil {
+			yield(SearchResult{}, err)
+			return
+		}
+		count := 0
+		for _, result := range results {
+			if !query.matches(result) {
+				continue
+			}
+			if query.Limit > 0 && count >= query.Limit {
+				return
+			}
+			if !yield(result, nil) {
+				return
+			}
+			count++
+		}
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package teamvault_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	teamvault "github.com/Seibert-Data/teamvault-cli/v5/pkg"
+	"github.com/Seibert-Data/teamvault-cli/v5/pkg/mocks"
+)
+
+var _ = Describe("SearchSeq", func() {
+	var ctx context.Context
+	var connector *mocks.Connector
+	BeforeEach(func() {
+		ctx = context.Background()
+		connector = &mocks.Connector{}
+		connector.SearchReturns([]teamvault.SearchResult{
+			{Key: "key1"},
+			{Key: "key2"},
+			{Key: "key3"},
+		}, nil)
+	})
+
+	It("replays Search for connectors without SearchIterator", func() {
+		var keys []teamvault.Key
+		for result, err := range teamvault.SearchSeq(ctx, connector, teamvault.SearchQuery{Name: "foo", Limit: 2}) {
+			Expect(err).To(BeNil())
+			keys = append(keys, result.Key)
+		}
+		Expect(keys).To(Equal([]teamvault.Key{"key1", "key2"}))
+		_, name := connector.SearchArgsForCall(0)
+		Expect(name).To(Equal("foo"))
+	})
+
+	It("yields the Search error", func() {
+		connector.SearchReturns(nil, errors.New("banana"))
+		var errs []error
+		for _, err := range teamvault.SearchSeq(ctx, connector, teamvault.SearchQuery{Name: "foo"}) {
+			errs = append(errs, err)
+		}
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0]).To(MatchError("banana"))
+	})
+})
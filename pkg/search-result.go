@@ -4,11 +4,21 @@
 
 package teamvault
 
-// SearchResult is one match from a TeamVault secret search. Name/Username/Url
-// come directly from the search response's result object (no per-key fetch).
+import "time"
+
+// SearchResult is one match from a TeamVault secret search. All fields come
+// directly from the search response's result object (no per-key fetch);
+// fields the server does not send stay empty.
 type SearchResult struct {
 	Key      Key
 	Name     string
 	Username string
 	Url      Url
+	// ContentType is the kind of secret, e.g. "password", "cc" or "file".
+	ContentType string
+	Description string
+	// Status is the secret's state, e.g. "ok" or "needs_changing".
+	Status string
+	// LastModified is when the secret last changed; zero if unknown.
+	LastModified time.Time
 }
@@ -0,0 +1,57 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package teamvault
+
+import (
+	"context"
+	stderrors "errors"
+	"time"
+
+	"github.com/bborbe/errors"
+)
+
+// ErrMetadataUnsupported is returned by ReadMetadata for connectors that
+// cannot read metadata.
+var ErrMetadataUnsupported = stderrors.New("connector does not support reading metadata")
+
+// SecretMetadata is the non-secret part of a TeamVault secret.
+type SecretMetadata struct {
+	Key         Key
+	Name        string
+	Username    string
+	Url         Url
+	Description string
+	// ContentType is ContentTypePassword, ContentTypeFile or another type
+	// reported by the server (e.g. "cc").
+	ContentType ContentType
+	// CurrentRevision is the API URL of the current revision; it changes
+	// whenever the secret's value changes.
+	CurrentRevision CurrentRevision
+	// LastModified is when the secret last changed; zero if unknown.
+	LastModified time.Time
+}
+
+//counterfeiter:generate -o mocks/metadata_reader.go --fake-name MetadataReader . MetadataReader
+
+// MetadataReader is implemented by connectors that can read a secret's
+// metadata in a single request, without reading its value.
+type MetadataReader interface {
+	Metadata(ctx context.Context, key Key) (SecretMetadata, error)
+}
+
+// ReadMetadata reads the metadata of key via connector, which must
+// implement MetadataReader.
+func ReadMetadata(ctx context.Context, connector Connector, key Key) (SecretMetadata, error) {
+	metadataReader, ok := connector.(MetadataReader)
+	if !ok {
+		return SecretMetadata{}, errors.Wrapf(
+			ctx,
+			ErrMetadataUnsupported,
+			"read metadata of %s",
+			key,
+		)
+	}
+	return metadataReader.Metadata(ctx, key)
+}
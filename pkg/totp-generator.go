@@ -0,0 +1,92 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package teamvault
+
+import (
+	"context"
+	"time"
+
+	"github.com/bborbe/errors"
+	libtime "github.com/bborbe/time"
+	"github.com/golang/glog"
+)
+
+// TotpCode is a one-time password and how long it remains valid.
+type TotpCode struct {
+	Code      string
+	Remaining time.Duration
+}
+
+// TotpGenerator generates TOTP codes from seeds stored in TeamVault secrets.
+type TotpGenerator interface {
+	Generate(ctx context.Context, key Key) (*TotpCode, error)
+}
+
+// NewTotpGenerator creates a new TotpGenerator with the given Connector.
+func NewTotpGenerator(
+	connector Connector,
+	currentDateTime libtime.CurrentDateTimeGetter,
+) TotpGenerator {
+	return &totpGenerator{
+		connector:       connector,
+		currentDateTime: currentDateTime,
+	}
+}
+
+type totpGenerator struct {
+	connector       Connector
+	currentDateTime libtime.CurrentDateTimeGetter
+}
+
+// Generate reads the seed from the secret's password, falling back to its
+// file, so both ways of storing an otpauth URI work.
+func (t *totpGenerator) Generate(ctx context.Context, key Key) (*TotpCode, error) {
+	totp, err := t.readTotp(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	now := t.currentDateTime.Now().Time()
+	code, err := totp.Code(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+	return &TotpCode{
+		Code:      code,
+		Remaining: totp.Remaining(now),
+	}, nil
+}
+
+func (t *totpGenerator) readTotp(ctx context.Context, key Key) (*Totp, error) {
+	pass, err := t.connector.Password(ctx, key)
+	if err != nil {
+		glog.V(2).Infof("get password from teamvault for key %v failed: %v", key, err)
+		return nil, errors.Wrapf(ctx, err, "get password for key %v failed", key)
+	}
+	totp, passErr := ParseTotp(ctx, pass.String())
+	if passErr == nil {
+		return totp, nil
+	}
+	glog.V(4).Infof("password of key %v is no totp seed: %v", key, passErr)
+
+	file, err := t.connector.File(ctx, key)
+	if err != nil {
+		return nil, errors.Wrapf(
+			ctx,
+			passErr,
+			"no totp seed in password of key %v and get file failed: %v",
+			key,
+			err,
+		)
+	}
+	content, err := file.Content()
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "decode file of key %v failed", key)
+	}
+	totp, err = ParseTotp(ctx, string(content))
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "no totp seed in password or file of key %v", key)
+	}
+	return totp, nil
+}
@@ -0,0 +1,162 @@
+// Copyright (c) 2016-2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package teamvault_test
+
+import (
+	"context"
+	"encoding/base32"
+	"encoding/base64"
+	"errors"
+	"time"
+
+	libtime "github.com/bborbe/time"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	teamvault "github.com/Seibert-Data/teamvault-cli/v5/pkg"
+	"github.com/Seibert-Data/teamvault-cli/v5/pkg/mocks"
+)
+
+var _ = Describe("Totp", func() {
+	var ctx context.Context
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	seed := func(s string) string {
+		return base32.StdEncoding.EncodeToString([]byte(s))
+	}
+
+	// RFC 6238 appendix B
+	DescribeTable("Code matches the RFC 6238 test vectors",
+		func(algorithm string, secret string, unix int64, expected string) {
+			totp, err := teamvault.ParseTotp(
+				ctx,
+				"otpauth://totp/rfc?secret="+seed(secret)+"&algorithm="+algorithm+"&digits=8",
+			)
+			Expect(err).To(BeNil())
+			code, err := totp.Code(ctx, time.Unix(unix, 0))
+			Expect(err).To(BeNil())
+			Expect(code).To(Equal(expected))
+		},
+		Entry("SHA1 59", "SHA1", "12345678901234567890", int64(59), "94287082"),
+		Entry("SHA256 59", "SHA256", "12345678901234567890123456789012", int64(59), "46119246"),
+		Entry(
+			"SHA512 59",
+			"SHA512",
+			"1234567890123456789012345678901234567890123456789012345678901234",
+			int64(59),
+			"90693936",
+		),
+		Entry("SHA1 1111111109", "SHA1", "12345678901234567890", int64(1111111109), "07081804"),
+		Entry(
+			"SHA256 1234567890",
+			"SHA256",
+			"12345678901234567890123456789012",
+			int64(1234567890),
+			"91819424",
+		),
+		Entry(
+			"SHA512 2000000000",
+			"SHA512",
+			"1234567890123456789012345678901234567890123456789012345678901234",
+			int64(2000000000),
+			"38618901",
+		),
+		Entry("SHA1 20000000000", "SHA1", "12345678901234567890", int64(20000000000), "65353130"),
+	)
+
+	It("parses a raw base32 seed with defaults", func() {
+		totp, err := teamvault.ParseTotp(ctx, "gezd gnbv gy3t qojq gezd gnbv gy3t qojq")
+		Expect(err).To(BeNil())
+		Expect(totp.Algorithm).To(Equal(teamvault.TotpAlgorithmSHA1))
+		Expect(totp.Digits).To(Equal(6))
+		Expect(totp.Period).To(Equal(30 * time.Second))
+		code, err := totp.Code(ctx, time.Unix(59, 0))
+		Expect(err).To(BeNil())
+		Expect(code).To(Equal("287082"))
+	})
+
+	It("honors period and reports the remaining validity", func() {
+		totp, err := teamvault.ParseTotp(
+			ctx,
+			"otpauth://totp/Acme:admin?secret="+seed(
+				"12345678901234567890",
+			)+"&period=60&issuer=Acme",
+		)
+		Expect(err).To(BeNil())
+		Expect(totp.Period).To(Equal(60 * time.Second))
+		Expect(totp.Remaining(time.Unix(125, 0))).To(Equal(55 * time.Second))
+	})
+
+	DescribeTable(
+		"rejects invalid seeds",
+		func(value string) {
+			_, err := teamvault.ParseTotp(ctx, value)
+			Expect(err).NotTo(BeNil())
+		},
+		Entry("hotp", "otpauth://hotp/x?secret="+seed("12345678901234567890")),
+		Entry("missing secret", "otpauth://totp/x?digits=6"),
+		Entry(
+			"unknown algorithm",
+			"otpauth://totp/x?secret="+seed("12345678901234567890")+"&algorithm=MD5",
+		),
+		Entry(
+			"too many digits",
+			"otpauth://totp/x?secret="+seed("12345678901234567890")+"&digits=12",
+		),
+		Entry("ordinary password", "correct horse battery staple"),
+		Entry("short base32 word", "ABCDEFG"),
+	)
+})
+
+var _ = Describe("TotpGenerator", func() {
+	var ctx context.Context
+	var connector *mocks.Connector
+	var generator teamvault.TotpGenerator
+	uri := "otpauth://totp/x?secret=" + base32.StdEncoding.EncodeToString(
+		[]byte("12345678901234567890"),
+	)
+	BeforeEach(func() {
+		ctx = context.Background()
+		connector = &mocks.Connector{}
+		generator = teamvault.NewTotpGenerator(
+			connector,
+			libtime.CurrentDateTimeGetterFunc(func() libtime.DateTime {
+				return libtime.DateTime(time.Unix(59, 0))
+			}),
+		)
+	})
+
+	It("reads the seed from the password", func() {
+		connector.PasswordReturns(teamvault.Password(uri), nil)
+
+		code, err := generator.Generate(ctx, "key123")
+		Expect(err).To(BeNil())
+		Expect(code.Code).To(Equal("287082"))
+		Expect(code.Remaining).To(Equal(time.Second))
+		Expect(connector.FileCallCount()).To(Equal(0))
+	})
+
+	It("falls back to the file", func() {
+		connector.PasswordReturns(teamvault.Password("not-a-seed"), nil)
+		connector.FileReturns(
+			teamvault.File(base64.StdEncoding.EncodeToString([]byte(uri))),
+			nil,
+		)
+
+		code, err := generator.Generate(ctx, "key123")
+		Expect(err).To(BeNil())
+		Expect(code.Code).To(Equal("287082"))
+	})
+
+	It("fails when neither holds a seed", func() {
+		connector.PasswordReturns(teamvault.Password("not-a-seed"), nil)
+		connector.FileReturns("", errors.New("no file"))
+
+		_, err := generator.Generate(ctx, "key123")
+		Expect(err).NotTo(BeNil())
+	})
+})
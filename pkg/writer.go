@@ -4,7 +4,15 @@
 
 package teamvault
 
-import "context"
+import (
+	"context"
+	stderrors "errors"
+	"time"
+)
+
+// ErrRevisionConflict is returned by Writer.Update when the secret changed
+// since the revision or time given in UpdateSecret.
+var ErrRevisionConflict = stderrors.New("secret was changed concurrently")
 
 // ContentType is the TeamVault secret content type. Only "password" and
 // "file" are supported; "cc" is deliberately out of scope.
@@ -56,6 +64,13 @@ type UpdateSecret struct {
 	Password *Password
 	// FileContent is the new file content; non-nil creates a new revision.
 	FileContent []byte
+	// ExpectedRevision makes the update fail with ErrRevisionConflict unless
+	// the secret's current revision still is this one. Either the revision's
+	// API URL or its ID; empty skips the check.
+	ExpectedRevision CurrentRevision
+	// UnchangedSince makes the update fail with ErrRevisionConflict if the
+	// secret was changed after this time; zero skips the check.
+	UnchangedSince time.Time
 }
 
 //counterfeiter:generate -o mocks/writer.go --fake-name Writer . Writer
@@ -66,8 +81,9 @@ type UpdateSecret struct {
 type Writer interface {
 	// Create posts a new secret and returns its key and api_url.
 	Create(ctx context.Context, secret CreateSecret) (Key, ApiUrl, error)
-	// Update patches an existing secret named by key. Only the fields set
-	// in UpdateSecret are sent.
+	// Update patches an existing secret named by key, which may also be a
+	// web UI or API URL of the vault. Only the fields set in UpdateSecret
+	// are sent.
 	Update(ctx context.Context, key Key, secret UpdateSecret) (Key, ApiUrl, error)
 	// GeneratePassword asks the server to generate a strong password.
 	GeneratePassword(ctx context.Context) (Password, error)
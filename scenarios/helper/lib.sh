@@ -19,9 +19,11 @@
 TV_ROOT="$(cd "$(dirname "${BASH_SOURCE[0]}")/../.." && pwd)"
 _FAIL=0
 FV_PID=""
+FV_TARGET_PID=""
 
 _cleanup() {
 	[ -n "$FV_PID" ] && kill "$FV_PID" 2>/dev/null || true
+	[ -n "$FV_TARGET_PID" ] && kill "$FV_TARGET_PID" 2>/dev/null || true
 	[ -n "${WORK_DIR:-}" ] && rm -rf "$WORK_DIR"
 }
 trap _cleanup EXIT
@@ -34,26 +36,43 @@ build_binaries() {
 	TV="$WORK_DIR/teamvault-cli"
 }
 
+# _wait_fakevault <log> waits for a fake server started with its output in
+# <log> and prints the URL it listens on.
+_wait_fakevault() {
+	local url=""
+	for _ in $(seq 1 100); do
+		url="$(sed -n 's#^fakevault listening on ##p' "$1")"
+		[ -n "$url" ] && break
+		sleep 0.1
+	done
+	if [ -z "$url" ]; then
+		echo "fakevault did not start" >&2; cat "$1" >&2; exit 1
+	fi
+	echo "$url"
+}
+
 # start_fakevault launches the fake server on an OS-assigned port, writes a temp
 # config (url+user+pass) pointing at it, and exports TEAMVAULT_CONFIG.
 start_fakevault() {
 	"$WORK_DIR/fakevault" --addr 127.0.0.1:0 >"$WORK_DIR/fv.log" 2>&1 &
 	FV_PID=$!
-	FV_URL=""
-	for _ in $(seq 1 100); do
-		FV_URL="$(sed -n 's#^fakevault listening on ##p' "$WORK_DIR/fv.log")"
-		[ -n "$FV_URL" ] && break
-		sleep 0.1
-	done
-	if [ -z "$FV_URL" ]; then
-		echo "fakevault did not start"; cat "$WORK_DIR/fv.log"; exit 1
-	fi
+	FV_URL="$(_wait_fakevault "$WORK_DIR/fv.log")" || exit 1
 	# Password lives in the config file so the Keychain is never consulted — the
 	# CI runner has no macOS Keychain / freedesktop secret service.
 	printf '{"url":"%s","user":"test","pass":"test"}\n' "$FV_URL" >"$WORK_DIR/config.json"
 	export TEAMVAULT_CONFIG="$WORK_DIR/config.json"
 }
 
+# start_target_fakevault launches a second, independent fake server (e.g. the
+# target of mirror) and writes its config to $FV_TARGET_CONFIG.
+start_target_fakevault() {
+	"$WORK_DIR/fakevault" --addr 127.0.0.1:0 >"$WORK_DIR/fv-target.log" 2>&1 &
+	FV_TARGET_PID=$!
+	FV_TARGET_URL="$(_wait_fakevault "$WORK_DIR/fv-target.log")" || exit 1
+	FV_TARGET_CONFIG="$WORK_DIR/target-config.json"
+	printf '{"url":"%s","user":"test","pass":"test"}\n' "$FV_TARGET_URL" >"$FV_TARGET_CONFIG"
+}
+
 # assert_eq <desc> <expected> <actual>
 assert_eq() {
 	if [ "$2" = "$3" ]; then
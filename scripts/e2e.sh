@@ -105,4 +105,173 @@ HTP_NEW="$("$TV" htpasswd --teamvault-key "$HTP_KEY")"
 assert_contains "htpasswd of a created secret carries its username" "reg-user:" "$HTP_NEW"
 assert_contains "htpasswd of a created secret is bcrypt"            '$2'        "$HTP_NEW"
 
+# --- Scenario 010: update preconditions (--if-revision / --if-unchanged-since) -
+
+# A value change bumps the revision; the old revision id no longer matches, so a
+# guarded update fails with a conflict and leaves the password alone.
+REV_KEY="$(printf 'rev-pw-1' | "$TV" create --name revision-e2e-secret --password-stdin)"
+REV_1="$("$TV" revision "$REV_KEY")"
+printf 'rev-pw-2' | "$TV" update "$REV_KEY" --password-stdin --if-revision "$REV_1" >/dev/null
+assert_eq "guarded update with the current revision succeeds" "rev-pw-2" "$("$TV" password "$REV_KEY")"
+assert_eq "value change moves the revision" "moved" \
+	"$([ "$("$TV" revision "$REV_KEY")" != "$REV_1" ] && echo moved || echo same)"
+assert_contains "stale --if-revision reports a conflict" "changed concurrently" \
+	"$(printf 'rev-pw-3' | "$TV" update "$REV_KEY" --password-stdin --if-revision "$REV_1" 2>&1 1>/dev/null)"
+assert_eq "conflicting update leaves the password" "rev-pw-2" "$("$TV" password "$REV_KEY")"
+# The last change has fractional seconds; the printed time still guards it.
+REV_SINCE="$("$TV" revision "$REV_KEY" --json | sed 's/.*"last_modified":"\([^"]*\)".*/\1/')"
+assert_eq "--if-unchanged-since with the revision's own time succeeds" "ok" \
+	"$("$TV" update "$REV_KEY" --description guarded --if-unchanged-since "$REV_SINCE" >/dev/null && echo ok || echo failed)"
+
+# The seeded fixtures last changed on 2026-01-01.
+assert_exit_nonzero "--if-unchanged-since before the last change fails" \
+	"$TV" update demo --description x --if-unchanged-since 2025-12-31T00:00:00Z
+assert_eq "--if-unchanged-since after the last change succeeds" "ok" \
+	"$("$TV" update demo --description guarded --if-unchanged-since 2026-01-02T00:00:00Z >/dev/null && echo ok || echo failed)"
+
+# --- Scenario 011: inventory plan / apply converge a manifest ----------------
+
+# A fresh manifest plans two creates; apply creates them and records the keys;
+# a second plan is empty (idempotent); a changed source file plans an update.
+mkdir -p "$WORK_DIR/inventory"
+printf 'tls-v1' >"$WORK_DIR/inventory/tls.pem"
+cat >"$WORK_DIR/inventory/teamvault-inventory.yaml" <<'YAML'
+secrets:
+  - name: inventory-e2e-db
+    username: inv-user
+    generate: true
+  - name: inventory-e2e-tls
+    content_type: file
+    source_file: tls.pem
+YAML
+INV=(inventory -f "$WORK_DIR/inventory/teamvault-inventory.yaml")
+assert_contains "plan lists the creates" "Plan: 2 to create, 0 to update, 0 unchanged." \
+	"$("$TV" "${INV[@]}" plan)"
+"$TV" "${INV[@]}" apply --auto-approve >/dev/null 2>&1
+INV_LOCK="$(cat "$WORK_DIR/inventory/teamvault-inventory.lock.yaml")"
+assert_contains "apply writes the lock file" "inventory-e2e-db:" "$INV_LOCK"
+INV_DB_KEY="$(sed -n 's/^ *inventory-e2e-db: *//p' "$WORK_DIR/inventory/teamvault-inventory.lock.yaml")"
+assert_eq "created secret has the declared username" "inv-user" "$("$TV" username "$INV_DB_KEY")"
+assert_contains "second plan is empty" "Plan: 0 to create, 0 to update, 2 unchanged." \
+	"$("$TV" "${INV[@]}" plan)"
+printf 'tls-v2' >"$WORK_DIR/inventory/tls.pem"
+assert_contains "changed source file plans an update" "Plan: 0 to create, 1 to update, 1 unchanged." \
+	"$("$TV" "${INV[@]}" plan)"
+"$TV" "${INV[@]}" apply --auto-approve >/dev/null 2>&1
+INV_TLS_KEY="$(sed -n 's/^ *inventory-e2e-tls: *//p' "$WORK_DIR/inventory/teamvault-inventory.lock.yaml")"
+assert_eq "apply uploads the new file" "tls-v2" "$("$TV" file "$INV_TLS_KEY" | base64 -d)"
+
+# --- Scenario 012: import from CSV with duplicate detection and resume ---------
+
+# The seeded "demo" secret is a duplicate; a dry run writes nothing; the import
+# creates the rest and records them; a re-run reports them as done.
+cat >"$WORK_DIR/import.csv" <<'CSV'
+Title,Login,Secret
+import-e2e-a,user-a,pw-a
+demo,x,y
+import-e2e-b,user-b,pw-b
+CSV
+IMP=(import "$WORK_DIR/import.csv" --columns name=Title,username=Login,password=Secret)
+assert_contains "dry run reports the creates" "Import: 2 would create, 1 duplicate." \
+	"$("$TV" "${IMP[@]}" --dry-run)"
+assert_eq "dry run writes no state file" "absent" \
+	"$([ -e "$WORK_DIR/import.csv.import-state.json" ] && echo present || echo absent)"
+assert_contains "import creates the new records" "Import: 2 created, 1 duplicate." \
+	"$("$TV" "${IMP[@]}")"
+IMP_A_KEY="$(sed -n 's/^ *"import-e2e-a": *"\(.*\)".*/\1/p' "$WORK_DIR/import.csv.import-state.json")"
+assert_eq "imported secret has its password" "pw-a" "$("$TV" password "$IMP_A_KEY")"
+assert_eq "imported secret has its username" "user-a" "$("$TV" username "$IMP_A_KEY")"
+assert_contains "re-run skips what is done" "Import: 2 done, 1 duplicate." \
+	"$("$TV" "${IMP[@]}")"
+
+# --- Scenario 013: encrypted backup, verify and restore ------------------------
+
+# A passphrase backup holds the seeded and created secrets; verify lists them
+# without touching TeamVault; a wrong passphrase fails; restoring into the same
+# vault creates nothing because every name exists.
+export TEAMVAULT_BACKUP_PASSPHRASE="e2e passphrase"
+"$TV" backup -o "$WORK_DIR/teamvault.age" --passphrase 2>/dev/null
+assert_contains "backup is an age archive" "age-encryption.org/v1" \
+	"$(head -c 21 "$WORK_DIR/teamvault.age")"
+BACKUP_LIST="$("$TV" restore "$WORK_DIR/teamvault.age" --verify)"
+assert_contains "verify lists a seeded secret" "demo" "$BACKUP_LIST"
+assert_contains "verify lists a created secret" "import-e2e-a" "$BACKUP_LIST"
+assert_contains "verify reports a valid backup" "is valid" "$BACKUP_LIST"
+assert_exit_nonzero "wrong passphrase fails" \
+	env TEAMVAULT_BACKUP_PASSPHRASE=wrong "$TV" restore "$WORK_DIR/teamvault.age" --verify
+assert_contains "restore into the same vault creates nothing" "Restore: 0 created" \
+	"$("$TV" restore "$WORK_DIR/teamvault.age")"
+unset TEAMVAULT_BACKUP_PASSPHRASE
+
+# --- Scenario 014: mirror to a second TeamVault --------------------------------
+
+# A dry run copies nothing; the mirror creates the imported secret in a second
+# fakevault and records it in the key map; a re-run leaves it alone; after the
+# source password changes, a re-run updates the target copy.
+start_target_fakevault
+MIR=(mirror "$IMP_A_KEY" --target-config "$FV_TARGET_CONFIG" --key-map "$WORK_DIR/mirror.json")
+assert_contains "dry run reports the create" "Mirror (dry run): 1 to create" \
+	"$("$TV" "${MIR[@]}" --dry-run)"
+assert_eq "dry run writes no key map" "absent" \
+	"$([ -e "$WORK_DIR/mirror.json" ] && echo present || echo absent)"
+assert_contains "mirror creates the secret" "Mirror: 1 created" "$("$TV" "${MIR[@]}")"
+MIR_KEY="$(sed -n 's/^ *"key": *"\(.*\)".*/\1/p' "$WORK_DIR/mirror.json")"
+assert_eq "target copy has the password" "pw-a" \
+	"$("$TV" password --teamvault-config "$FV_TARGET_CONFIG" "$MIR_KEY")"
+assert_contains "re-run leaves it unchanged" "Mirror: 0 created, 0 updated, 1 unchanged" \
+	"$("$TV" "${MIR[@]}")"
+printf 'pw-a-2' | "$TV" update "$IMP_A_KEY" --password-stdin >/dev/null
+assert_contains "changed source revision is updated" "Mirror: 0 created, 1 updated" \
+	"$("$TV" "${MIR[@]}")"
+assert_eq "target copy has the new password" "pw-a-2" \
+	"$("$TV" password --teamvault-config "$FV_TARGET_CONFIG" "$MIR_KEY")"
+
+# --- Scenario 015: rotate via a hook, with rollback and journal --------------
+
+# The hook gets old and new password as JSON on stdin; only after it succeeds
+# does TeamVault store the new password. A failing hook leaves the secret as it
+# was. Both rotations are journaled without passwords.
+ROT_KEY="$(printf 'rotate-old' | "$TV" create --name rotate-e2e --password-stdin)"
+printf '#!/bin/sh\ncat > "%s/hook.json"\n' "$WORK_DIR" >"$WORK_DIR/hook.sh"
+printf '#!/bin/sh\nexit 1\n' >"$WORK_DIR/failing-hook.sh"
+chmod +x "$WORK_DIR/hook.sh" "$WORK_DIR/failing-hook.sh"
+ROT=(rotate "$ROT_KEY" --journal "$WORK_DIR/rotations.jsonl")
+"$TV" "${ROT[@]}" --hook "$WORK_DIR/hook.sh" 2>/dev/null
+ROT_NEW="$("$TV" password "$ROT_KEY")"
+assert_contains "hook got the old password" '"old_password":"rotate-old"' \
+	"$(cat "$WORK_DIR/hook.json")"
+assert_contains "hook got the stored new password" "\"new_password\":\"$ROT_NEW\"" \
+	"$(cat "$WORK_DIR/hook.json")"
+assert_exit_nonzero "failing hook fails the rotation" \
+	"$TV" "${ROT[@]}" --hook "$WORK_DIR/failing-hook.sh"
+assert_eq "failing hook leaves the password" "$ROT_NEW" "$("$TV" password "$ROT_KEY")"
+assert_contains "journal records the rotation" '"status":"rotated"' \
+	"$(sed -n 1p "$WORK_DIR/rotations.jsonl")"
+assert_contains "journal records the failed hook" '"status":"hook failed"' \
+	"$(sed -n 2p "$WORK_DIR/rotations.jsonl")"
+assert_eq "journal holds no passwords" "0" \
+	"$(grep -c -e "$ROT_NEW" -e rotate-old "$WORK_DIR/rotations.jsonl")"
+
+# --- Scenario 016: audit for weak, reused and stale passwords ----------------
+
+# Two secrets share a common password and one has a strong one; the audit
+# reports the shared password as weak and reused in all formats, without ever
+# printing it.
+AUD_A="$(printf 'password1' | "$TV" create --name audit-e2e-a --password-stdin)"
+AUD_B="$(printf 'password1' | "$TV" create --name audit-e2e-b --password-stdin)"
+printf 'r8N!c2pW@x4Lq#Zt6vB$m9Hy' | "$TV" create --name audit-e2e-c --password-stdin >/dev/null
+AUD_TABLE="$("$TV" audit audit-e2e)"
+assert_contains "audit summarizes the findings" \
+	"Audit: 3 passwords, 2 weak, 2 reused, 0 stale, 0 need change, 0 failed." "$AUD_TABLE"
+assert_contains "audit lists the weak reused secret" "weak, reused" "$AUD_TABLE"
+AUD_JSON="$("$TV" audit audit-e2e --format json)"
+assert_eq "json reports both reused secrets" "2" \
+	"$(printf '%s\n' "$AUD_JSON" | grep -c '"reused_with"')"
+assert_eq "csv lists header and all secrets" "4" \
+	"$("$TV" audit audit-e2e --format csv --all | wc -l | tr -d ' ')"
+assert_contains "csv reports the findings" "$AUD_A,audit-e2e-a" \
+	"$("$TV" audit audit-e2e --format csv)"
+assert_eq "audit prints no passwords" "0" \
+	"$(printf '%s\n%s\n' "$AUD_TABLE" "$AUD_JSON" | grep -c password1)"
+
 scenario_done